@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/resource"
+)
+
+// applyOptions holds the flags of the apply command
+type applyOptions struct {
+	filenames      []string
+	recursive      bool
+	serverSide     bool
+	fieldManager   string
+	forceConflicts bool
+	dryRun         string
+	prune          bool
+	pruneAllowlist []string
+	selector       string
+}
+
+// NewApplyCmd creates a new apply command
+func NewApplyCmd() *cobra.Command {
+	o := &applyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "apply -f <file|dir|url|->",
+		Short: "Apply a configuration to a resource by file name or stdin",
+		Long: `Apply a configuration to a resource by file name or stdin.
+
+The objects are created when missing and updated otherwise. Manifests are YAML
+or JSON, with several documents per file, read from files, directories, URLs
+or the standard input.
+
+Server-side apply is used by default, with skectl as field manager. Servers
+without server-side apply get a client-side three-way merge of the last applied
+configuration, the manifest and the live object instead, based on the same
+last-applied-configuration annotation kubectl uses.`,
+		Example: `  # Apply the objects of a file
+  skectl apply -f deployment.yaml
+
+  # Apply every manifest of a directory and its subdirectories
+  skectl apply -f manifests/ -R
+
+  # Apply the manifest from the standard input
+  cat pod.json | skectl apply -f -
+
+  # Take over fields owned by other managers
+  skectl apply -f deployment.yaml --force-conflicts
+
+  # Check the objects on the server without persisting them
+  skectl apply -f manifests/ --dry-run=server
+
+  # Apply the manifests and delete the objects labeled app=web that are no longer in them
+  skectl apply -f manifests/ --prune -l app=web
+
+  # Only prune the config maps and deployments labeled app=web
+  skectl apply -f manifests/ --prune -l app=web --prune-allowlist=core/v1/ConfigMap --prune-allowlist=apps/v1/Deployment`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run(cmd.Context(), cmd.InOrStdin())
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&o.filenames, "filename", "f", nil, "The files, directories or URLs that contain the configuration to apply, - for the standard input")
+	cmd.Flags().BoolVarP(&o.recursive, "recursive", "R", false, "Process the directory used in -f, --filename recursively")
+	cmd.Flags().BoolVar(&o.serverSide, "server-side", true, "Apply on the server, falling back to a client-side merge when the server does not support it")
+	cmd.Flags().StringVar(&o.fieldManager, "field-manager", resource.DefaultFieldManager, "Name of the manager used to track field ownership")
+	cmd.Flags().BoolVar(&o.forceConflicts, "force-conflicts", false, "Take over the fields owned by other managers on server-side apply")
+	cmd.Flags().StringVar(&o.dryRun, "dry-run", "none", `Must be "none", "server", or "client". With "client", only print the objects that would be sent; with "server", submit the requests without persisting them`)
+	cmd.Flags().BoolVar(&o.prune, "prune", false, "Delete the previously applied objects matching the selector that are not in the manifests")
+	cmd.Flags().StringArrayVar(&o.pruneAllowlist, "prune-allowlist", nil, "Group/version/kind searched by --prune besides the kinds of the manifests, such as core/v1/ConfigMap, can be repeated; defaults to the kubectl set of common kinds")
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "Selector (label query) of the objects to prune, supports '=', '==', and '!='")
+
+	return cmd
+}
+
+// run applies every object of the manifests and prunes the missing ones
+func (o *applyOptions) run(ctx context.Context, stdin io.Reader) error {
+	dryRun, err := resource.ParseDryRun(o.dryRun)
+	if err != nil {
+		return err
+	}
+	if o.prune && o.selector == "" {
+		return fmt.Errorf("--prune requires a selector, set one with -l")
+	}
+	if !o.prune && len(o.pruneAllowlist) > 0 {
+		return fmt.Errorf("--prune-allowlist requires --prune")
+	}
+	pruneKinds := resource.DefaultPruneKinds
+	if len(o.pruneAllowlist) > 0 {
+		if pruneKinds, err = resource.ParsePruneAllowlist(o.pruneAllowlist); err != nil {
+			return err
+		}
+	}
+	if o.forceConflicts && !o.serverSide {
+		return fmt.Errorf("--force-conflicts only works with --server-side")
+	}
+
+	objects, err := resource.ReadObjects(ctx, o.filenames, o.recursive, stdin)
+	if err != nil {
+		return err
+	}
+	if len(objects) == 0 {
+		return fmt.Errorf("no objects passed to apply")
+	}
+
+	namespace, enforceNamespace, err := configFlags.ToNamespace()
+	if err != nil {
+		return err
+	}
+	mapper, err := configFlags.ToRESTMapper()
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := configFlags.ToDynamicClient()
+	if err != nil {
+		return err
+	}
+
+	applier := &resource.Applier{
+		Client:           dynamicClient,
+		Mapper:           mapper,
+		Namespace:        namespace,
+		EnforceNamespace: enforceNamespace,
+		ServerSide:       o.serverSide,
+		FieldManager:     o.fieldManager,
+		ForceConflicts:   o.forceConflicts,
+		DryRun:           dryRun,
+	}
+
+	// Keep applying after a failure and report every error at the end
+	var applied []*resource.Result
+	var errs []error
+	for _, obj := range objects {
+		result, err := applier.Apply(ctx, obj)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		applied = append(applied, result)
+		fmt.Printf("%s%s\n", result, dryRun.Suffix())
+	}
+	if o.serverSide && !applier.ServerSide {
+		fmt.Fprintln(os.Stderr, "Warning: the server does not support server-side apply, the objects were applied on the client")
+	}
+
+	if o.prune && len(errs) == 0 {
+		pruned, err := applier.Prune(ctx, applied, o.selector, pruneKinds)
+		for _, result := range pruned {
+			fmt.Printf("%s%s\n", result, dryRun.Suffix())
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/testutil"
+)
+
+func TestApplyCmd(t *testing.T) {
+	dir := t.TempDir()
+	manifests := map[string]string{
+		"web.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+  labels:
+    app: web
+data:
+  color: blue
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+`,
+		"nested/db.json": `{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "db", "labels": {"app": "web"}}}`,
+	}
+	for name, content := range manifests {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		stdin       string
+		noApply     bool
+		expected    []string
+		unexpected  []string
+		wantRequest string
+		expectError bool
+	}{
+		{
+			name:        "file",
+			args:        []string{"-f", filepath.Join(dir, "web.yaml")},
+			expected:    []string{"configmap/web serverside-applied\n", "deployment.apps/web serverside-applied\n"},
+			unexpected:  []string{"service/db"},
+			wantRequest: "PATCH /api/v1/namespaces/project-a/configmaps/web?fieldManager=skectl&force=false",
+		},
+		{
+			name:     "recursive directory",
+			args:     []string{"-f", dir, "-R"},
+			expected: []string{"configmap/web serverside-applied\n", "service/db serverside-applied\n"},
+		},
+		{
+			name:        "standard input with field manager and forced conflicts",
+			args:        []string{"-f", "-", "--field-manager", "ci", "--force-conflicts"},
+			stdin:       "apiVersion: v1\nkind: Secret\nmetadata:\n  name: token\n",
+			expected:    []string{"secret/token serverside-applied\n"},
+			wantRequest: "PATCH /api/v1/namespaces/project-a/secrets/token?fieldManager=ci&force=true",
+		},
+		{
+			name:        "server dry run",
+			args:        []string{"-f", filepath.Join(dir, "web.yaml"), "--dry-run=server"},
+			expected:    []string{"configmap/web serverside-applied (server dry run)\n"},
+			wantRequest: "PATCH /api/v1/namespaces/project-a/configmaps/web?dryRun=All&fieldManager=skectl&force=false",
+		},
+		{
+			name:     "client-side fallback",
+			args:     []string{"-f", filepath.Join(dir, "web.yaml")},
+			noApply:  true,
+			expected: []string{"configmap/web created\n", "deployment.apps/web created\n"},
+		},
+		{
+			name:     "client dry run",
+			args:     []string{"-f", filepath.Join(dir, "web.yaml"), "--server-side=false", "--dry-run=client"},
+			expected: []string{"configmap/web created (dry run)\n"},
+		},
+		{
+			name:        "namespace mismatch",
+			args:        []string{"-n", "project-b", "-f", "-"},
+			stdin:       "apiVersion: v1\nkind: Secret\nmetadata:\n  name: token\n  namespace: project-a\n",
+			expectError: true,
+		},
+		{
+			name:        "prune without selector",
+			args:        []string{"-f", dir, "--prune"},
+			expectError: true,
+		},
+		{
+			name:        "invalid dry run",
+			args:        []string{"-f", dir, "--dry-run=maybe"},
+			expectError: true,
+		},
+		{
+			name:        "missing file",
+			args:        []string{"-f", filepath.Join(dir, "missing.yaml")},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupFakeAPIServer(t, "project-a")
+			if tt.noApply {
+				server.DisableServerSideApply()
+			}
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetIn(strings.NewReader(tt.stdin))
+			cmd.SetArgs(append([]string{"apply"}, tt.args...))
+			err := cmd.Execute()
+			output := capture.Stdout()
+			*configFlags = *client.NewConfigFlags()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, output, expected)
+			}
+			for _, unexpected := range tt.unexpected {
+				assert.NotContains(t, output, unexpected)
+			}
+			if tt.wantRequest != "" {
+				assert.Contains(t, server.Requests(), tt.wantRequest)
+			}
+		})
+	}
+}
+
+func TestApplyCmdPrune(t *testing.T) {
+	server := setupFakeAPIServer(t, "project-a")
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "app.yaml")
+
+	apply := func(content string, args ...string) string {
+		require.NoError(t, os.WriteFile(manifest, []byte(content), 0644))
+		capture := testutil.NewCaptureOutput()
+		require.NoError(t, capture.Start(), "Failed to start output capture")
+		defer capture.Stop()
+
+		cmd := NewRootCmd()
+		cmd.SetArgs(append([]string{"apply", "-f", manifest}, args...))
+		err := cmd.Execute()
+		*configFlags = *client.NewConfigFlags()
+		require.NoError(t, err)
+		return capture.Stdout()
+	}
+
+	apply(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+  labels:
+    app: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+  labels:
+    app: web
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+  labels:
+    app: web
+`)
+	// The secret kind is dropped from the manifests but still pruned
+	output := apply(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+  labels:
+    app: web
+`, "--prune", "-l", "app=web")
+
+	assert.Equal(t, "configmap/a serverside-applied\nconfigmap/b pruned\nsecret/credentials pruned\n", output)
+	assert.NotNil(t, server.Object("v1", "ConfigMap", "project-a", "a"))
+	assert.Nil(t, server.Object("v1", "ConfigMap", "project-a", "b"))
+	assert.Nil(t, server.Object("v1", "Secret", "project-a", "credentials"))
+}
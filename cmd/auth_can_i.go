@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/printers"
+	"github.com/withlin/oc-demo/pkg/resource"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// canIOptions holds the flags of the auth can-i command
+type canIOptions struct {
+	subresource   string
+	allNamespaces bool
+	list          bool
+	quiet         bool
+	noHeaders     bool
+}
+
+// NewCanICmd creates a new auth can-i command
+func NewCanICmd() *cobra.Command {
+	o := &canIOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "can-i (<verb> <type>[/<name>] [name] | <verb> <non-resource-url> | --list)",
+		Short: "Check whether an action is allowed",
+		Long: `Check whether an action is allowed.
+
+The server answers a SelfSubjectAccessReview for the action: "yes" is printed
+and the exit code is 0 when it is allowed, "no" is printed and the exit code is
+1 otherwise. The type may be a resource such as pods, a resource with its group
+such as deployments.apps, or * for every resource. A path starting with / is
+checked as a non-resource URL.
+
+With --list the rules of the user in the namespace are printed as a table, read
+from a SelfSubjectRulesReview.`,
+		Example: `  # Check whether I can create pods in the current namespace
+  skectl auth can-i create pods
+
+  # Check whether I can list deployments in the kube-system namespace
+  skectl auth can-i list deployments.apps -n kube-system
+
+  # Check whether I can read the logs of a pod
+  skectl auth can-i get pods --subresource=log
+
+  # Check whether the developer user can delete the web config map
+  skectl auth can-i delete configmap/web --as=developer
+
+  # Check whether I can access the /logs non-resource URL
+  skectl auth can-i get /logs/
+
+  # List everything I can do in the current namespace
+  skectl auth can-i --list`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if o.list {
+				if len(args) > 0 {
+					return fmt.Errorf("--list does not take arguments")
+				}
+				return o.runList(cmd.Context())
+			}
+			if len(args) < 2 || len(args) > 3 {
+				return fmt.Errorf("you must specify a verb and a resource, and optionally a name")
+			}
+			return o.run(cmd.Context(), args)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.subresource, "subresource", "", "Subresource such as pod/log or deployment/scale")
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "Check the action in all namespaces")
+	cmd.Flags().BoolVar(&o.list, "list", false, "List all the allowed actions in the namespace")
+	cmd.Flags().BoolVarP(&o.quiet, "quiet", "q", false, "Print nothing, only report the answer through the exit code")
+	cmd.Flags().BoolVar(&o.noHeaders, "no-headers", false, "Don't print the headers of the --list table")
+
+	return cmd
+}
+
+// run asks the server whether the action is allowed and reports the answer
+// through the output and the exit code
+func (o *canIOptions) run(ctx context.Context, args []string) error {
+	verb, target := args[0], args[1]
+	review := &authorizationv1.SelfSubjectAccessReview{}
+
+	if strings.HasPrefix(target, "/") {
+		if len(args) == 3 || o.subresource != "" {
+			return fmt.Errorf("a non-resource URL takes neither a name nor a subresource")
+		}
+		review.Spec.NonResourceAttributes = &authorizationv1.NonResourceAttributes{Verb: verb, Path: target}
+	} else {
+		resourceType, name, _ := strings.Cut(target, "/")
+		if len(args) == 3 {
+			if name != "" {
+				return fmt.Errorf("only one of <type>/<name> or a name argument is allowed")
+			}
+			name = args[2]
+		}
+		attributes, err := o.resourceAttributes(resourceType)
+		if err != nil {
+			return err
+		}
+		attributes.Verb = verb
+		attributes.Name = name
+		attributes.Subresource = o.subresource
+		review.Spec.ResourceAttributes = attributes
+	}
+
+	clientSet, err := configFlags.ToClientSet()
+	if err != nil {
+		return err
+	}
+	result, err := clientSet.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create SelfSubjectAccessReview: %w", err)
+	}
+
+	if !o.quiet {
+		switch {
+		case result.Status.Allowed:
+			fmt.Println("yes")
+		case result.Status.Reason != "":
+			fmt.Printf("no - %s\n", result.Status.Reason)
+		default:
+			fmt.Println("no")
+		}
+		if result.Status.EvaluationError != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", result.Status.EvaluationError)
+		}
+	}
+	if !result.Status.Allowed {
+		return &ExitError{Code: 1}
+	}
+	return nil
+}
+
+// resourceAttributes resolves the resource type through discovery and
+// returns its group, resource and namespace. Types unknown to the server are
+// checked as given, with a warning.
+func (o *canIOptions) resourceAttributes(resourceType string) (*authorizationv1.ResourceAttributes, error) {
+	namespace, _, err := configFlags.ToNamespace()
+	if err != nil {
+		return nil, err
+	}
+	if o.allNamespaces {
+		namespace = ""
+	}
+	if resourceType == "*" {
+		return &authorizationv1.ResourceAttributes{Namespace: namespace, Group: "*", Resource: "*"}, nil
+	}
+
+	mapper, err := configFlags.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := resource.Mapping(mapper, resourceType)
+	if err != nil {
+		groupResource := schema.ParseGroupResource(strings.ToLower(resourceType))
+		fmt.Fprintf(os.Stderr, "Warning: the server doesn't have a resource type %q\n", resourceType)
+		return &authorizationv1.ResourceAttributes{Namespace: namespace, Group: groupResource.Group, Resource: groupResource.Resource}, nil
+	}
+	if !resource.Namespaced(mapping) {
+		namespace = ""
+	}
+	return &authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Group:     mapping.Resource.Group,
+		Version:   mapping.Resource.Version,
+		Resource:  mapping.Resource.Resource,
+	}, nil
+}
+
+// runList prints the rules of the user in the namespace
+func (o *canIOptions) runList(ctx context.Context) error {
+	namespace, _, err := configFlags.ToNamespace()
+	if err != nil {
+		return err
+	}
+	clientSet, err := configFlags.ToClientSet()
+	if err != nil {
+		return err
+	}
+	review := &authorizationv1.SelfSubjectRulesReview{Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace}}
+	result, err := clientSet.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create SelfSubjectRulesReview: %w", err)
+	}
+
+	if result.Status.Incomplete {
+		fmt.Fprintf(os.Stderr, "Warning: the list may be incomplete: %s\n", result.Status.EvaluationError)
+	}
+	printer := &printers.TablePrinter{NoHeaders: o.noHeaders}
+	return printer.PrintTable(rulesTable(result.Status), os.Stdout)
+}
+
+// rulesTable returns the rules as a table with a row per resource, sorted by
+// resource, followed by the non-resource rules
+func rulesTable(status authorizationv1.SubjectRulesReviewStatus) *metav1.Table {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Resources", Type: "string"},
+			{Name: "Non-Resource URLs", Type: "string"},
+			{Name: "Resource Names", Type: "string"},
+			{Name: "Verbs", Type: "string"},
+		},
+	}
+
+	var rows [][]interface{}
+	for _, rule := range status.ResourceRules {
+		groups := rule.APIGroups
+		if len(groups) == 0 {
+			groups = []string{""}
+		}
+		for _, group := range groups {
+			for _, name := range rule.Resources {
+				if group != "" {
+					name += "." + group
+				}
+				rows = append(rows, []interface{}{name, "[]", bracketed(rule.ResourceNames), bracketed(rule.Verbs)})
+			}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i][0].(string) < rows[j][0].(string) })
+	for _, rule := range status.NonResourceRules {
+		rows = append(rows, []interface{}{"", bracketed(rule.NonResourceURLs), "[]", bracketed(rule.Verbs)})
+	}
+
+	for _, cells := range rows {
+		table.Rows = append(table.Rows, metav1.TableRow{Cells: cells})
+	}
+	return table
+}
+
+// bracketed returns the values separated by spaces between brackets
+func bracketed(values []string) string {
+	return "[" + strings.Join(values, " ") + "]"
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/testutil"
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+// handleAccessReviews answers the access reviews of the fake server: pods can
+// be read, the admin user can do anything, and every review is recorded
+func handleAccessReviews(server *testutil.FakeAPIServer, reviews *[]authorizationv1.SelfSubjectAccessReview, users *[]string) {
+	server.Handle(http.MethodPost, "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews", func(w http.ResponseWriter, r *http.Request) {
+		review := authorizationv1.SelfSubjectAccessReview{}
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*reviews = append(*reviews, review)
+		*users = append(*users, r.Header.Get("Impersonate-User"))
+
+		attributes := review.Spec.ResourceAttributes
+		switch {
+		case r.Header.Get("Impersonate-User") == "admin":
+			review.Status.Allowed = true
+		case attributes != nil && attributes.Resource == "pods" && attributes.Subresource == "" && (attributes.Verb == "get" || attributes.Verb == "list"):
+			review.Status.Allowed = true
+		case review.Spec.NonResourceAttributes != nil:
+			review.Status.Reason = "non-resource URLs are not allowed"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(review)
+	})
+	server.Handle(http.MethodPost, "/apis/authorization.k8s.io/v1/selfsubjectrulesreviews", func(w http.ResponseWriter, r *http.Request) {
+		review := authorizationv1.SelfSubjectRulesReview{}
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*users = append(*users, r.Header.Get("Impersonate-User"))
+		review.Status = authorizationv1.SubjectRulesReviewStatus{
+			ResourceRules: []authorizationv1.ResourceRule{
+				{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods", "configmaps"}},
+				{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: []string{"web"}},
+			},
+			NonResourceRules: []authorizationv1.NonResourceRule{
+				{Verbs: []string{"get"}, NonResourceURLs: []string{"/healthz", "/version"}},
+			},
+		}
+		if review.Spec.Namespace != "project-a" {
+			review.Status.ResourceRules = nil
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(review)
+	})
+}
+
+func TestCanICmd(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expected       string
+		exitCode       int
+		wantAttributes *authorizationv1.ResourceAttributes
+		wantUser       string
+		expectError    bool
+	}{
+		{
+			name:           "allowed action",
+			args:           []string{"get", "pods"},
+			expected:       "yes\n",
+			wantAttributes: &authorizationv1.ResourceAttributes{Namespace: "project-a", Verb: "get", Version: "v1", Resource: "pods"},
+		},
+		{
+			name:           "denied action on a named object of another namespace",
+			args:           []string{"delete", "deploy/web", "-n", "other"},
+			expected:       "no\n",
+			exitCode:       1,
+			wantAttributes: &authorizationv1.ResourceAttributes{Namespace: "other", Verb: "delete", Group: "apps", Version: "v1", Resource: "deployments", Name: "web"},
+		},
+		{
+			name:           "subresource",
+			args:           []string{"get", "pods", "web-1", "--subresource=log"},
+			expected:       "no\n",
+			exitCode:       1,
+			wantAttributes: &authorizationv1.ResourceAttributes{Namespace: "project-a", Verb: "get", Version: "v1", Resource: "pods", Subresource: "log", Name: "web-1"},
+		},
+		{
+			name:           "impersonated user",
+			args:           []string{"delete", "nodes", "--as=admin"},
+			expected:       "yes\n",
+			wantAttributes: &authorizationv1.ResourceAttributes{Verb: "delete", Version: "v1", Resource: "nodes"},
+			wantUser:       "admin",
+		},
+		{
+			name:     "quiet answer",
+			args:     []string{"create", "pods", "-q"},
+			exitCode: 1,
+		},
+		{
+			name:     "non-resource URL with a reason",
+			args:     []string{"get", "/logs"},
+			expected: "no - non-resource URLs are not allowed\n",
+			exitCode: 1,
+		},
+		{
+			name: "rules of the namespace",
+			args: []string{"--list"},
+			expected: "RESOURCES          NON-RESOURCE URLS     RESOURCE NAMES   VERBS\n" +
+				"configmaps         []                    []               [get list]\n" +
+				"deployments.apps   []                    [web]            [get]\n" +
+				"pods               []                    []               [get list]\n" +
+				"                   [/healthz /version]   []               [get]\n",
+		},
+		{
+			name:        "missing resource",
+			args:        []string{"get"},
+			expectError: true,
+		},
+		{
+			name:        "name given twice",
+			args:        []string{"get", "pods/web-1", "web-2"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupFakeAPIServer(t, "project-a")
+			var reviews []authorizationv1.SelfSubjectAccessReview
+			var users []string
+			handleAccessReviews(server, &reviews, &users)
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(append([]string{"auth", "can-i"}, tt.args...))
+			err := cmd.Execute()
+			output := capture.Stdout()
+			*configFlags = *client.NewConfigFlags()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Empty(t, reviews)
+				return
+			}
+			assert.Equal(t, tt.exitCode, ExitCode(err), "error: %v", err)
+			assert.Equal(t, tt.expected, output)
+			if tt.wantAttributes != nil {
+				require.Len(t, reviews, 1)
+				assert.Equal(t, tt.wantAttributes, reviews[0].Spec.ResourceAttributes)
+			}
+			if len(users) > 0 {
+				assert.Equal(t, tt.wantUser, users[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCurrentContextCmd creates a new current-context command
+func NewCurrentContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "current-context",
+		Short: "Display the current context",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Load kubeconfig
+			_, config, err := loadKubeconfig()
+			if err != nil {
+				return err
+			}
+
+			if config.CurrentContext == "" {
+				return fmt.Errorf("current-context is not set")
+			}
+
+			fmt.Println(config.CurrentContext)
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/testutil"
+)
+
+func TestCurrentContextCmd(t *testing.T) {
+	t.Run("print current context", func(t *testing.T) {
+		setupTestKubeconfig(t)
+
+		capture := testutil.NewCaptureOutput()
+		require.NoError(t, capture.Start(), "Failed to start output capture")
+		defer capture.Stop()
+
+		cmd := NewCurrentContextCmd()
+		cmd.SetArgs([]string{})
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "context1\n", capture.Stdout())
+	})
+
+	t.Run("current context not set", func(t *testing.T) {
+		t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+
+		cmd := NewCurrentContextCmd()
+		cmd.SetArgs([]string{})
+		assert.Error(t, cmd.Execute())
+	})
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/resource"
+	"github.com/withlin/oc-demo/pkg/util"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/dynamic"
+)
+
+// deleteOptions holds the flags of the delete command
+type deleteOptions struct {
+	filenames   []string
+	recursive   bool
+	selector    string
+	all         bool
+	cascade     string
+	gracePeriod int64
+	wait        bool
+	timeout     time.Duration
+	yes         bool
+	reader      util.InputReader
+}
+
+// deleteTarget is an object to delete with its resource
+type deleteTarget struct {
+	mapping *meta.RESTMapping
+	obj     *unstructured.Unstructured
+}
+
+// NewDeleteCmd creates a new delete command
+func NewDeleteCmd() *cobra.Command {
+	o := &deleteOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "delete ([-f <file>] | <type>[/<name>] [name...] | <type> -l <selector> | <type> --all)",
+		Short: "Delete resources by file names, stdin, resources and names, or by resources and label selector",
+		Long: `Delete resources by file names, stdin, resources and names, or by resources and
+label selector.
+
+The dependents of the objects are deleted in the background by default. Use
+--cascade=foreground to delete them before their owner, or --cascade=orphan to
+keep them. The command waits until the objects are gone unless --wait=false is
+given.
+
+Deleting every object of a type with --all, or the objects matching a broad
+selector without any equality requirement such as app or app!=web, asks for a
+confirmation first. Use --yes to skip it.`,
+		Example: `  # Delete the objects of a file
+  skectl delete -f deployment.yaml
+
+  # Delete a pod and a service
+  skectl delete pod/web-1 svc/web
+
+  # Delete the pods labeled app=web, leaving their dependents
+  skectl delete pods -l app=web --cascade=orphan
+
+  # Delete a pod immediately without waiting for it to be gone
+  skectl delete pod web-1 --grace-period=0 --wait=false
+
+  # Delete every config map in the current namespace without confirmation
+  skectl delete configmaps --all --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.reader = util.NewInputReader()
+			return o.run(cmd.Context(), args, cmd.InOrStdin())
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&o.filenames, "filename", "f", nil, "The files, directories or URLs that contain the objects to delete, - for the standard input")
+	cmd.Flags().BoolVarP(&o.recursive, "recursive", "R", false, "Process the directory used in -f, --filename recursively")
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "Selector (label query) to filter on, supports '=', '==', and '!='")
+	cmd.Flags().BoolVar(&o.all, "all", false, "Delete all objects of the resource types in the namespace")
+	cmd.Flags().StringVar(&o.cascade, "cascade", "background", `Must be "background", "foreground", or "orphan". Selects the deletion cascading strategy for the dependents`)
+	cmd.Flags().Int64Var(&o.gracePeriod, "grace-period", -1, "Period of time in seconds given to the objects to terminate gracefully, negative to use the default of the objects")
+	cmd.Flags().BoolVar(&o.wait, "wait", true, "Wait for the objects to be gone before returning")
+	cmd.Flags().DurationVar(&o.timeout, "timeout", 0, "The length of time to wait for the deletion, zero to wait forever")
+	cmd.Flags().BoolVarP(&o.yes, "yes", "y", false, "Delete without asking for confirmation")
+
+	return cmd
+}
+
+// run collects the objects, asks for confirmation when needed, deletes them
+// and waits for them to be gone
+func (o *deleteOptions) run(ctx context.Context, args []string, stdin io.Reader) error {
+	cascade, err := resource.ParseCascade(o.cascade)
+	if err != nil {
+		return err
+	}
+	if len(o.filenames) > 0 && len(args) > 0 {
+		return fmt.Errorf("resource arguments cannot be combined with -f, --filename")
+	}
+	if len(o.filenames) == 0 && len(args) == 0 {
+		return fmt.Errorf("you must provide one or more resources by argument or file name")
+	}
+	if o.all && o.selector != "" {
+		return fmt.Errorf("--all cannot be combined with a selector")
+	}
+
+	namespace, enforceNamespace, err := configFlags.ToNamespace()
+	if err != nil {
+		return err
+	}
+	mapper, err := configFlags.ToRESTMapper()
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := configFlags.ToDynamicClient()
+	if err != nil {
+		return err
+	}
+
+	var targets []deleteTarget
+	if len(o.filenames) > 0 {
+		targets, err = o.fileTargets(ctx, mapper, namespace, enforceNamespace, stdin)
+	} else {
+		targets, err = o.argTargets(ctx, mapper, dynamicClient, namespace, args)
+	}
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Fprintf(os.Stderr, "No resources found in %s namespace.\n", namespace)
+		return nil
+	}
+
+	broad, err := o.broad()
+	if err != nil {
+		return err
+	}
+	if broad && !o.yes {
+		confirmed, err := o.confirm(targets)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return fmt.Errorf("deletion cancelled")
+		}
+	}
+
+	deleter := &resource.Deleter{Client: dynamicClient, Cascade: cascade}
+	if o.gracePeriod >= 0 {
+		deleter.GracePeriod = &o.gracePeriod
+	}
+
+	// Keep deleting after a failure and report every error at the end
+	var deleted []deleteTarget
+	var errs []error
+	for _, target := range targets {
+		if err := deleter.Delete(ctx, target.mapping, target.obj); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		deleted = append(deleted, target)
+		fmt.Printf("%s %q deleted\n", resource.QualifiedKind(target.mapping), target.obj.GetName())
+	}
+
+	if o.wait {
+		if o.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, o.timeout)
+			defer cancel()
+		}
+		for _, target := range deleted {
+			if err := deleter.Wait(ctx, target.mapping, target.obj); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fileTargets returns the objects of the manifests
+func (o *deleteOptions) fileTargets(ctx context.Context, mapper meta.RESTMapper, namespace string, enforceNamespace bool, stdin io.Reader) ([]deleteTarget, error) {
+	if o.all || o.selector != "" {
+		return nil, fmt.Errorf("--all and selectors cannot be combined with -f, --filename")
+	}
+	objects, err := resource.ReadObjects(ctx, o.filenames, o.recursive, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]deleteTarget, 0, len(objects))
+	for _, obj := range objects {
+		mapping, err := resource.PrepareObject(mapper, obj, namespace, enforceNamespace)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, deleteTarget{mapping: mapping, obj: obj})
+	}
+	return targets, nil
+}
+
+// argTargets fetches the named objects, or lists the objects of the types
+// matching the selector
+func (o *deleteOptions) argTargets(ctx context.Context, mapper meta.RESTMapper, client dynamic.Interface, namespace string, args []string) ([]deleteTarget, error) {
+	refs, err := resource.ParseRefs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []deleteTarget
+	for _, ref := range refs {
+		if ref.Name != "" && (o.all || o.selector != "") {
+			return nil, fmt.Errorf("name cannot be provided when a selector or --all is specified")
+		}
+		if ref.Name == "" && !o.all && o.selector == "" {
+			return nil, fmt.Errorf("resource(s) were provided, but no name was specified, use --all to delete every %s", ref.Type)
+		}
+
+		mapping, err := resource.Mapping(mapper, ref.Type)
+		if err != nil {
+			return nil, err
+		}
+		resourceClient := resource.ClientFor(client, mapping, namespace)
+
+		if ref.Name != "" {
+			obj, err := resourceClient.Get(ctx, ref.Name, metav1.GetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s %q: %w", mapping.Resource.Resource, ref.Name, err)
+			}
+			targets = append(targets, deleteTarget{mapping: mapping, obj: obj})
+			continue
+		}
+
+		list, err := resourceClient.List(ctx, metav1.ListOptions{LabelSelector: o.selector})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", mapping.Resource.Resource, err)
+		}
+		for i := range list.Items {
+			targets = append(targets, deleteTarget{mapping: mapping, obj: &list.Items[i]})
+		}
+	}
+	return targets, nil
+}
+
+// broad reports whether the objects are selected with --all or with a
+// selector that has no equality requirement
+func (o *deleteOptions) broad() (bool, error) {
+	if o.all {
+		return true, nil
+	}
+	if o.selector == "" {
+		return false, nil
+	}
+	selector, err := labels.Parse(o.selector)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse selector %q: %w", o.selector, err)
+	}
+	requirements, _ := selector.Requirements()
+	for _, requirement := range requirements {
+		switch requirement.Operator() {
+		case selection.Equals, selection.DoubleEquals, selection.In:
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// confirm lists the objects and asks whether to delete them
+func (o *deleteOptions) confirm(targets []deleteTarget) (bool, error) {
+	fmt.Println("The following objects will be deleted:")
+	for _, target := range targets {
+		if target.obj.GetNamespace() == "" {
+			fmt.Printf("  %s/%s\n", resource.QualifiedKind(target.mapping), target.obj.GetName())
+		} else {
+			fmt.Printf("  %s/%s in %s\n", resource.QualifiedKind(target.mapping), target.obj.GetName(), target.obj.GetNamespace())
+		}
+	}
+
+	confirmed, err := util.Confirm(o.reader, "Do you want to continue? (y/n): ")
+	if err != nil {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	return confirmed, nil
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// NewDeleteContextCmd creates a new delete-context command
+func NewDeleteContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-context <context>",
+		Short: "Delete the specified context from the kubeconfig",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("context name is required")
+			}
+			contextName := args[0]
+
+			// Load kubeconfig
+			configAccess, config, err := loadKubeconfig()
+			if err != nil {
+				return err
+			}
+
+			// Check if context exists
+			if _, exists := config.Contexts[contextName]; !exists {
+				return fmt.Errorf("context %q does not exist", contextName)
+			}
+
+			if config.CurrentContext == contextName {
+				fmt.Fprintln(os.Stderr, "Warning: this removed your active context, use \"skectl use-context\" to select a different one")
+				config.CurrentContext = ""
+			}
+			delete(config.Contexts, contextName)
+
+			// Save kubeconfig
+			if err := saveKubeconfig(configAccess, config); err != nil {
+				return err
+			}
+
+			fmt.Printf("Deleted context %q\n", contextName)
+			return nil
+		},
+	}
+
+	return cmd
+}
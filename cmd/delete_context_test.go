@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestDeleteContextCmd(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expectError   bool
+		wantCurrent   string
+		wantRemaining []string
+	}{
+		{
+			name:          "delete other context",
+			args:          []string{"context2"},
+			wantCurrent:   "context1",
+			wantRemaining: []string{"context1"},
+		},
+		{
+			name:          "delete current context",
+			args:          []string{"context1"},
+			wantCurrent:   "",
+			wantRemaining: []string{"context2"},
+		},
+		{
+			name:        "delete non-existent context",
+			args:        []string{"unknown"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeconfigPath := setupTestKubeconfig(t)
+
+			cmd := NewDeleteContextCmd()
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			config, err := clientcmd.LoadFromFile(kubeconfigPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCurrent, config.CurrentContext)
+			assert.Len(t, config.Contexts, len(tt.wantRemaining))
+			for _, name := range tt.wantRemaining {
+				assert.Contains(t, config.Contexts, name)
+			}
+			// Clusters and users are kept
+			assert.Len(t, config.Clusters, 2)
+			assert.Len(t, config.AuthInfos, 2)
+		})
+	}
+}
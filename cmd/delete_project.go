@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/resource"
+	"github.com/withlin/oc-demo/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// deleteProjectOptions holds the flags of the delete-project command
+type deleteProjectOptions struct {
+	wait    bool
+	timeout time.Duration
+	yes     bool
+	reader  util.InputReader
+}
+
+// NewDeleteProjectCmd creates a new delete-project command
+func NewDeleteProjectCmd() *cobra.Command {
+	o := &deleteProjectOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "delete-project <name>",
+		Short: "Delete a project",
+		Long: `Delete a project and every object in it.
+
+The project is deleted after a confirmation, unless --yes is given. The server
+terminates the objects of the project in the background; use --wait to return
+only once the project is gone. On Kubernetes the namespace is deleted.`,
+		Example: `  # Delete a project after a confirmation
+  skectl delete-project web-team-dev
+
+  # Delete a project without confirmation and wait for it to be gone
+  skectl delete-project web-team-dev --yes --wait --timeout=5m`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.reader = util.NewInputReader()
+			return o.run(cmd.Context(), args[0])
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.wait, "wait", false, "Wait for the project to be gone before returning")
+	cmd.Flags().DurationVar(&o.timeout, "timeout", 0, "The length of time to wait for the deletion, zero to wait forever")
+	cmd.Flags().BoolVarP(&o.yes, "yes", "y", false, "Delete without asking for confirmation")
+
+	return cmd
+}
+
+// run confirms and deletes the project, then waits for it to be gone
+func (o *deleteProjectOptions) run(ctx context.Context, name string) error {
+	mapper, err := configFlags.ToRESTMapper()
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := configFlags.ToDynamicClient()
+	if err != nil {
+		return err
+	}
+
+	// Projects are namespaces on servers without the OpenShift project API
+	mapping, err := resource.Mapping(mapper, "projects.project.openshift.io")
+	if err != nil {
+		if mapping, err = resource.Mapping(mapper, "namespaces"); err != nil {
+			return err
+		}
+	}
+	obj, err := resource.ClientFor(dynamicClient, mapping, "").Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get project %q: %w", name, err)
+	}
+
+	if !o.yes {
+		confirmed, err := util.Confirm(o.reader, fmt.Sprintf("Are you sure you want to delete project %q and every object in it? (y/n): ", name))
+		if err != nil {
+			return fmt.Errorf("failed to read answer: %w", err)
+		}
+		if !confirmed {
+			return fmt.Errorf("deletion cancelled")
+		}
+	}
+
+	deleter := &resource.Deleter{Client: dynamicClient}
+	if err := deleter.Delete(ctx, mapping, obj); err != nil {
+		return err
+	}
+	fmt.Printf("project %q deleted\n", name)
+
+	if current, _, err := configFlags.ToNamespace(); err == nil && current == name {
+		fmt.Fprintf(os.Stderr, "You are still using the deleted project %q, switch to another one with 'skectl project <name>'.\n", name)
+	}
+
+	if !o.wait {
+		return nil
+	}
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+	return deleter.Wait(ctx, mapping, obj)
+}
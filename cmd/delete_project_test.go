@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/testutil"
+)
+
+func TestDeleteProjectCmd(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		openshift   bool
+		answer      string
+		deleted     bool
+		expectError bool
+	}{
+		{
+			name:      "confirmed deletion of a project",
+			args:      []string{"delete-project", "project-b"},
+			openshift: true,
+			answer:    "y\n",
+			deleted:   true,
+		},
+		{
+			name:    "namespace deleted without confirmation and waited for",
+			args:    []string{"delete-project", "project-b", "--yes", "--wait", "--timeout=5s"},
+			deleted: true,
+		},
+		{
+			name:        "declined deletion",
+			args:        []string{"delete-project", "project-b"},
+			openshift:   true,
+			answer:      "n\n",
+			expectError: true,
+		},
+		{
+			name:        "missing project",
+			args:        []string{"delete-project", "missing", "--yes"},
+			openshift:   true,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupFakeAPIServer(t, "project-a")
+			addProjects(server, tt.openshift)
+			apiVersion, kind := "v1", "Namespace"
+			if tt.openshift {
+				apiVersion, kind = "project.openshift.io/v1", "Project"
+			}
+
+			// Provide the answer to the confirmation prompt on stdin
+			oldStdin := os.Stdin
+			defer func() { os.Stdin = oldStdin }()
+			r, w, err := os.Pipe()
+			require.NoError(t, err)
+			os.Stdin = r
+			_, err = w.WriteString(tt.answer)
+			require.NoError(t, err)
+			w.Close()
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(tt.args)
+			err = cmd.Execute()
+			output := capture.Stdout()
+			*configFlags = *client.NewConfigFlags()
+
+			assert.Equal(t, tt.deleted, server.Object(apiVersion, kind, "", "project-b") == nil)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, output, "project \"project-b\" deleted\n")
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// deleteTestObjects are the config maps and the deployment of the delete tests
+const deleteTestObjects = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+  namespace: project-a
+  labels:
+    app: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: db
+  namespace: project-a
+  labels:
+    app: db
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: project-a
+  labels:
+    app: web
+`
+
+func TestDeleteCmd(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "web.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\n"), 0644))
+
+	tests := []struct {
+		name        string
+		args        []string
+		answer      string
+		expected    []string
+		deleted     []string
+		kept        []string
+		wantCascade metav1.DeletionPropagation
+		expectError bool
+	}{
+		{
+			name:     "file",
+			args:     []string{"-f", manifest},
+			expected: []string{"configmap \"web\" deleted\n"},
+			deleted:  []string{"ConfigMap/web"},
+			kept:     []string{"ConfigMap/db", "Deployment/web"},
+		},
+		{
+			name:        "type and name pairs with cascade",
+			args:        []string{"cm/db", "deploy/web", "--cascade=foreground"},
+			expected:    []string{"configmap \"db\" deleted\n", "deployment.apps \"web\" deleted\n"},
+			deleted:     []string{"ConfigMap/db", "Deployment/web"},
+			kept:        []string{"ConfigMap/web"},
+			wantCascade: metav1.DeletePropagationForeground,
+		},
+		{
+			name:        "equality selector without confirmation",
+			args:        []string{"configmaps", "-l", "app=web", "--cascade=orphan"},
+			expected:    []string{"configmap \"web\" deleted\n"},
+			deleted:     []string{"ConfigMap/web"},
+			kept:        []string{"ConfigMap/db"},
+			wantCascade: metav1.DeletePropagationOrphan,
+		},
+		{
+			name:     "all confirmed",
+			args:     []string{"configmaps", "--all"},
+			answer:   "y\n",
+			expected: []string{"The following objects will be deleted:\n  configmap/web in project-a\n  configmap/db in project-a\n", "configmap \"db\" deleted\n"},
+			deleted:  []string{"ConfigMap/web", "ConfigMap/db"},
+			kept:     []string{"Deployment/web"},
+		},
+		{
+			name:        "all declined",
+			args:        []string{"configmaps", "--all"},
+			answer:      "n\n",
+			kept:        []string{"ConfigMap/web", "ConfigMap/db"},
+			expectError: true,
+		},
+		{
+			name:        "broad selector declined",
+			args:        []string{"configmaps", "-l", "app!=db"},
+			answer:      "\n",
+			kept:        []string{"ConfigMap/web"},
+			expectError: true,
+		},
+		{
+			name:     "all with yes",
+			args:     []string{"configmaps", "--all", "--yes"},
+			deleted:  []string{"ConfigMap/web", "ConfigMap/db"},
+			expected: []string{"configmap \"web\" deleted\n"},
+		},
+		{
+			name:        "type without name",
+			args:        []string{"configmaps"},
+			kept:        []string{"ConfigMap/web", "ConfigMap/db"},
+			expectError: true,
+		},
+		{
+			name:        "name with selector",
+			args:        []string{"configmap", "web", "-l", "app=web"},
+			expectError: true,
+		},
+		{
+			name:        "invalid cascade",
+			args:        []string{"configmap", "web", "--cascade=never"},
+			kept:        []string{"ConfigMap/web"},
+			expectError: true,
+		},
+		{
+			name:        "missing object",
+			args:        []string{"configmap", "missing"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupFakeAPIServer(t, "project-a")
+			for _, manifest := range strings.Split(deleteTestObjects, "---") {
+				server.AddYAML(manifest)
+			}
+
+			// Provide the answer to the confirmation prompt on stdin
+			oldStdin := os.Stdin
+			defer func() { os.Stdin = oldStdin }()
+			r, w, err := os.Pipe()
+			require.NoError(t, err)
+			os.Stdin = r
+			_, err = w.WriteString(tt.answer)
+			require.NoError(t, err)
+			w.Close()
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(append([]string{"delete"}, tt.args...))
+			err = cmd.Execute()
+			output := capture.Stdout()
+			*configFlags = *client.NewConfigFlags()
+
+			exists := func(ref string) bool {
+				kind, name, _ := strings.Cut(ref, "/")
+				apiVersion := "v1"
+				if kind == "Deployment" {
+					apiVersion = "apps/v1"
+				}
+				return server.Object(apiVersion, kind, "project-a", name) != nil
+			}
+			for _, ref := range tt.kept {
+				assert.True(t, exists(ref), "%s was deleted", ref)
+			}
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, output, expected)
+			}
+			for _, ref := range tt.deleted {
+				assert.False(t, exists(ref), "%s was not deleted", ref)
+			}
+			if tt.wantCascade != "" {
+				for _, options := range server.Deletions() {
+					require.NotNil(t, options.PropagationPolicy)
+					assert.Equal(t, tt.wantCascade, *options.PropagationPolicy)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteCmdWait(t *testing.T) {
+	server := setupFakeAPIServer(t, "project-a")
+	server.AddYAML("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\n  namespace: project-a\n  finalizers:\n  - example.com/cleanup\n")
+
+	run := func(args ...string) error {
+		capture := testutil.NewCaptureOutput()
+		require.NoError(t, capture.Start(), "Failed to start output capture")
+		defer capture.Stop()
+
+		cmd := NewRootCmd()
+		cmd.SetArgs(append([]string{"delete", "configmap", "web"}, args...))
+		err := cmd.Execute()
+		*configFlags = *client.NewConfigFlags()
+		return err
+	}
+
+	// The finalizer keeps the object past the timeout
+	assert.Error(t, run("--timeout", "100ms", "--grace-period", "0"))
+	deletions := server.Deletions()
+	require.Len(t, deletions, 1)
+	require.NotNil(t, deletions[0].GracePeriodSeconds)
+	assert.Equal(t, int64(0), *deletions[0].GracePeriodSeconds)
+
+	// Without waiting the command returns at once
+	assert.NoError(t, run("--wait=false"))
+
+	// Waiting ends once the finalizer is done
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		server.DeleteObject("v1", "ConfigMap", "project-a", "web")
+	}()
+	assert.NoError(t, run("--timeout", "5s"))
+	assert.Nil(t, server.Object("v1", "ConfigMap", "project-a", "web"))
+}
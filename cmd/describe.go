@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/describe"
+	"github.com/withlin/oc-demo/pkg/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// describeOptions holds the flags of the describe command
+type describeOptions struct {
+	allNamespaces bool
+	selector      string
+}
+
+// NewDescribeCmd creates a new describe command
+func NewDescribeCmd() *cobra.Command {
+	o := &describeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "describe <type>[/<name>] [name...]",
+		Short: "Show details of a specific resource or group of resources",
+		Long: `Show details of a specific resource or group of resources.
+
+Print a detailed description of the selected resources, including related
+resources such as the endpoints of a service or the pods running on a node,
+followed by the events about them. Pods, deployments, services, nodes, config
+maps, secrets, routes, deployment configs, projects and build configs have a
+dedicated description; other kinds list all their fields. The values of
+secrets are never shown, only their size.`,
+		Example: `  # Describe a pod
+  skectl describe pod/nginx
+
+  # Describe all pods matching a label
+  skectl describe pods -l app=nginx
+
+  # Describe a route and the endpoints behind it
+  skectl describe route web
+
+  # Describe a node and the pods running on it
+  skectl describe node worker-1`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run(cmd.Context(), args)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "Describe the requested objects across all namespaces")
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "Selector (label query) to filter on, supports '=', '==', and '!='")
+
+	return cmd
+}
+
+// run fetches the requested objects and prints their descriptions separated by blank lines
+func (o *describeOptions) run(ctx context.Context, args []string) error {
+	refs, err := resource.ParseRefs(args)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if ref.Name != "" && o.selector != "" {
+			return fmt.Errorf("name cannot be provided when a selector is specified")
+		}
+	}
+
+	namespace, _, err := configFlags.ToNamespace()
+	if err != nil {
+		return err
+	}
+	if o.allNamespaces {
+		namespace = ""
+	}
+
+	mapper, err := configFlags.ToRESTMapper()
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := configFlags.ToDynamicClient()
+	if err != nil {
+		return err
+	}
+
+	var objects []unstructured.Unstructured
+	for _, ref := range refs {
+		mapping, err := resource.Mapping(mapper, ref.Type)
+		if err != nil {
+			return err
+		}
+		resourceClient := resource.ClientFor(dynamicClient, mapping, namespace)
+
+		if ref.Name != "" {
+			obj, err := resourceClient.Get(ctx, ref.Name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("failed to get %s %q: %w", mapping.Resource.Resource, ref.Name, err)
+			}
+			objects = append(objects, *obj)
+			continue
+		}
+
+		list, err := resourceClient.List(ctx, metav1.ListOptions{LabelSelector: o.selector})
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", mapping.Resource.Resource, err)
+		}
+		objects = append(objects, list.Items...)
+	}
+
+	if len(objects) == 0 {
+		if namespace == "" {
+			fmt.Fprintln(os.Stderr, "No resources found")
+		} else {
+			fmt.Fprintf(os.Stderr, "No resources found in %s namespace.\n", namespace)
+		}
+		return nil
+	}
+
+	for i := range objects {
+		description, err := describe.Describe(ctx, dynamicClient, &objects[i])
+		if err != nil {
+			return fmt.Errorf("failed to describe %s %q: %w", objects[i].GetKind(), objects[i].GetName(), err)
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(description)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDescribeCmd(t *testing.T) {
+	server := setupFakeAPIServer(t, "project-a")
+	server.AddResource("route.openshift.io/v1", metav1.APIResource{Name: "routes", SingularName: "route", Kind: "Route", Namespaced: true})
+	server.AddYAML(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: frontend
+  namespace: project-a
+  uid: frontend-uid
+  labels:
+    app: web
+spec:
+  containers:
+  - name: nginx
+    image: nginx:1.25
+status:
+  phase: Running
+`)
+	server.AddYAML(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: worker
+  namespace: project-a
+  labels:
+    app: batch
+status:
+  phase: Pending
+`)
+	server.AddYAML(`
+apiVersion: v1
+kind: Event
+metadata:
+  name: frontend.1
+  namespace: project-a
+involvedObject:
+  kind: Pod
+  name: frontend
+  namespace: project-a
+  uid: frontend-uid
+type: Normal
+reason: Pulled
+message: Container image "nginx:1.25" already present on machine
+`)
+	server.AddYAML(`
+apiVersion: route.openshift.io/v1
+kind: Route
+metadata:
+  name: web
+  namespace: project-a
+spec:
+  host: web.apps.example.com
+  to:
+    kind: Service
+    name: web
+`)
+
+	tests := []struct {
+		name        string
+		args        []string
+		expected    []string
+		unexpected  []string
+		wantRequest string
+		expectError bool
+	}{
+		{
+			name:        "pod by type and name",
+			args:        []string{"pod", "frontend"},
+			expected:    []string{"Name:", "frontend", "Image:", "nginx:1.25", "Status:", "Running", "Pulled", "already present on machine"},
+			unexpected:  []string{"worker"},
+			wantRequest: "GET /api/v1/namespaces/project-a/pods/frontend",
+		},
+		{
+			name:        "events of the object",
+			args:        []string{"pod/frontend"},
+			wantRequest: "GET /api/v1/namespaces/project-a/events?fieldSelector=involvedObject.kind%3DPod%2CinvolvedObject.name%3Dfrontend%2CinvolvedObject.namespace%3Dproject-a%2CinvolvedObject.uid%3Dfrontend-uid",
+		},
+		{
+			name:       "pods by label selector",
+			args:       []string{"pods", "-l", "app=batch"},
+			expected:   []string{"worker", "Pending", "Events:", "<none>"},
+			unexpected: []string{"frontend"},
+		},
+		{
+			name:     "all pods separated by blank lines",
+			args:     []string{"pods"},
+			expected: []string{"Name:", "frontend", "\n\nName:", "worker"},
+		},
+		{
+			name:     "openshift kind",
+			args:     []string{"route/web"},
+			expected: []string{"Requested Host:", "web.apps.example.com", "Service:", "web"},
+		},
+		{
+			name:        "unknown resource type",
+			args:        []string{"widgets", "gadget"},
+			expectError: true,
+		},
+		{
+			name:        "object not found",
+			args:        []string{"pod/missing"},
+			expectError: true,
+		},
+		{
+			name:        "name with selector",
+			args:        []string{"pod/worker", "-l", "app=web"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(append([]string{"describe"}, tt.args...))
+			err := cmd.Execute()
+			output := capture.Stdout()
+			*configFlags = *client.NewConfigFlags()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, output, expected)
+			}
+			for _, unexpected := range tt.unexpected {
+				assert.NotContains(t, output, unexpected)
+			}
+			if tt.wantRequest != "" {
+				assert.Contains(t, server.Requests(), tt.wantRequest)
+			}
+		})
+	}
+}
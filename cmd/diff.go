@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/diff"
+	"github.com/withlin/oc-demo/pkg/resource"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// diffOptions holds the flags of the diff command
+type diffOptions struct {
+	filenames      []string
+	recursive      bool
+	serverSide     bool
+	fieldManager   string
+	forceConflicts bool
+}
+
+// NewDiffCmd creates a new diff command
+func NewDiffCmd() *cobra.Command {
+	o := &diffOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "diff -f <file|dir|url|->",
+		Short: "Diff the live version against a would-be applied version",
+		Long: `Diff the configurations specified by file name or stdin between the current
+online configuration and the configuration as it would be if applied.
+
+Every object is applied on the server in dry-run mode and compared with the
+live object. Managed fields, the last applied configuration and the other
+fields the server updates on every write are left out.
+
+The output is a unified diff. Set KUBECTL_EXTERNAL_DIFF, or SKECTL_EXTERNAL_DIFF
+which takes precedence, to compare the LIVE and MERGED directories with another
+program, e.g. "colordiff -N -u". The arguments may only hold letters, digits,
+'-' and '='.
+
+Exit status:
+ 0 No differences were found.
+ 1 Differences were found.
+>1 skectl or the diff program failed.`,
+		Example: `  # Diff the objects of a file against the server
+  skectl diff -f deployment.yaml
+
+  # Diff every manifest of a directory and its subdirectories
+  skectl diff -f manifests/ -R
+
+  # Diff the manifest from the standard input with an external program
+  cat pod.json | KUBECTL_EXTERNAL_DIFF="meld" skectl diff -f -`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run(cmd.Context(), cmd.InOrStdin())
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&o.filenames, "filename", "f", nil, "The files, directories or URLs that contain the configuration to diff, - for the standard input")
+	cmd.Flags().BoolVarP(&o.recursive, "recursive", "R", false, "Process the directory used in -f, --filename recursively")
+	cmd.Flags().BoolVar(&o.serverSide, "server-side", true, "Diff against a server-side apply, falling back to a client-side merge when the server does not support it")
+	cmd.Flags().StringVar(&o.fieldManager, "field-manager", resource.DefaultFieldManager, "Name of the manager used to track field ownership")
+	cmd.Flags().BoolVar(&o.forceConflicts, "force-conflicts", true, "Take over the fields owned by other managers on server-side apply")
+
+	return cmd
+}
+
+// run diffs the objects of the manifests, returning an ExitError with code 1
+// when they differ and 2 on failure
+func (o *diffOptions) run(ctx context.Context, stdin io.Reader) error {
+	changed, err := o.diff(ctx, stdin)
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		// A failure never reads as no changes or changes, and a program
+		// killed by a signal has an exit code of -1
+		code := exitErr.ExitCode()
+		if code <= 1 {
+			code = 2
+		}
+		return &ExitError{Code: code, Err: err}
+	case err != nil:
+		return &ExitError{Code: 2, Err: err}
+	case changed:
+		return &ExitError{Code: 1}
+	}
+	return nil
+}
+
+// diff writes the live and merged objects and compares them, reporting
+// whether they differ
+func (o *diffOptions) diff(ctx context.Context, stdin io.Reader) (bool, error) {
+	program, err := diff.ExternalProgram()
+	if err != nil {
+		return false, err
+	}
+	objects, err := resource.ReadObjects(ctx, o.filenames, o.recursive, stdin)
+	if err != nil {
+		return false, err
+	}
+	if len(objects) == 0 {
+		return false, fmt.Errorf("no objects passed to diff")
+	}
+
+	namespace, enforceNamespace, err := configFlags.ToNamespace()
+	if err != nil {
+		return false, err
+	}
+	mapper, err := configFlags.ToRESTMapper()
+	if err != nil {
+		return false, err
+	}
+	dynamicClient, err := configFlags.ToDynamicClient()
+	if err != nil {
+		return false, err
+	}
+
+	applier := &resource.Applier{
+		Client:           dynamicClient,
+		Mapper:           mapper,
+		Namespace:        namespace,
+		EnforceNamespace: enforceNamespace,
+		ServerSide:       o.serverSide,
+		FieldManager:     o.fieldManager,
+		ForceConflicts:   o.forceConflicts && o.serverSide,
+		DryRun:           resource.DryRunServer,
+	}
+
+	differ, err := diff.NewDiffer()
+	if err != nil {
+		return false, err
+	}
+	defer differ.Close()
+
+	// Keep diffing after a failure and report every error at the end
+	var errs []error
+	for _, obj := range objects {
+		live, err := liveObject(ctx, applier, obj)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result, err := applier.Apply(ctx, obj)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := differ.Add(live, result.Object); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	changed, err := differ.Run(program, os.Stdout, os.Stderr)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return changed, errors.Join(errs...)
+}
+
+// liveObject returns the object as it is on the server, nil when it does not
+// exist
+func liveObject(ctx context.Context, applier *resource.Applier, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	mapping, err := applier.Prepare(obj)
+	if err != nil {
+		return nil, err
+	}
+	live, err := resource.ClientFor(applier.Client, mapping, obj.GetNamespace()).Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %w", mapping.Resource.Resource, obj.GetName(), err)
+	}
+	return live, nil
+}
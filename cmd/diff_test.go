@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/testutil"
+)
+
+func TestDiffCmd(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "web.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+data:
+  color: green
+`), 0644))
+	script := filepath.Join(dir, "fake-diff")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho external \"$@\"\nexit 3\n"), 0755))
+	killed := filepath.Join(dir, "killed-diff")
+	require.NoError(t, os.WriteFile(killed, []byte("#!/bin/sh\nkill -KILL $$\n"), 0755))
+
+	tests := []struct {
+		name     string
+		live     string
+		args     []string
+		env      string
+		noApply  bool
+		expected []string
+		exitCode int
+	}{
+		{
+			name:     "no differences",
+			live:     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\n  namespace: project-a\n  resourceVersion: \"12\"\ndata:\n  color: green\n",
+			args:     []string{"-f", manifest},
+			exitCode: 0,
+		},
+		{
+			name: "changed object",
+			live: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\n  namespace: project-a\ndata:\n  color: blue\n",
+			args: []string{"-f", manifest},
+			expected: []string{
+				"--- LIVE/v1.ConfigMap.project-a.web\n+++ MERGED/v1.ConfigMap.project-a.web\n",
+				"-  color: blue\n+  color: green\n",
+			},
+			exitCode: 1,
+		},
+		{
+			name:     "new object",
+			args:     []string{"-f", manifest},
+			expected: []string{"+kind: ConfigMap\n"},
+			exitCode: 1,
+		},
+		{
+			name:     "client-side fallback",
+			live:     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\n  namespace: project-a\ndata:\n  color: blue\n",
+			args:     []string{"-f", manifest},
+			noApply:  true,
+			expected: []string{"-  color: blue\n+  color: green\n"},
+			exitCode: 1,
+		},
+		{
+			name:     "external program failure",
+			args:     []string{"-f", manifest},
+			env:      script,
+			expected: []string{"external "},
+			exitCode: 3,
+		},
+		{
+			name:     "external program killed by a signal",
+			args:     []string{"-f", manifest},
+			env:      killed,
+			exitCode: 2,
+		},
+		{
+			name:     "missing file",
+			args:     []string{"-f", filepath.Join(dir, "missing.yaml")},
+			exitCode: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupFakeAPIServer(t, "project-a")
+			if tt.live != "" {
+				server.AddYAML(tt.live)
+			}
+			if tt.noApply {
+				server.DisableServerSideApply()
+			}
+			t.Setenv("SKECTL_EXTERNAL_DIFF", tt.env)
+			t.Setenv("KUBECTL_EXTERNAL_DIFF", "")
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(append([]string{"diff"}, tt.args...))
+			err := cmd.Execute()
+			output := capture.Stdout()
+			*configFlags = *client.NewConfigFlags()
+
+			assert.Equal(t, tt.exitCode, ExitCode(err), "error: %v", err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, output, expected)
+			}
+			if tt.exitCode == 0 {
+				assert.Empty(t, output)
+			}
+			// The live object is never changed
+			if tt.live == "" {
+				assert.Nil(t, server.Object("v1", "ConfigMap", "project-a", "web"))
+			}
+		})
+	}
+}
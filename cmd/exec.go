@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/resource"
+	"github.com/withlin/oc-demo/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// newExecutor creates the stream executor of a remote command, replaced in tests
+var newExecutor = func(config *rest.Config, method string, url *url.URL) (remotecommand.Executor, error) {
+	return remotecommand.NewSPDYExecutor(config, method, url)
+}
+
+// execOptions holds the flags of the exec and rsh commands
+type execOptions struct {
+	container string
+	stdin     bool
+	tty       bool
+	noTTY     bool
+
+	in  io.Reader
+	out io.Writer
+	err io.Writer
+}
+
+// NewExecCmd creates a new exec command
+func NewExecCmd() *cobra.Command {
+	o := &execOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "exec (<pod> | <type>/<name>) [-c <container>] [-i] [-t] -- <command> [args...]",
+		Short: "Execute a command in a container",
+		Long: `Execute a command in a container.
+
+With -t the command gets a terminal: the local terminal is put in raw mode and
+its size changes are sent to the container. A workload such as deploy/web runs
+the command in the first of its running pods. The exit code of the command is
+the exit code of skectl.`,
+		Example: `  # Print the date in the default container of a pod
+  skectl exec web-1 -- date
+
+  # Open an interactive shell in the nginx container of a pod
+  skectl exec -it web-1 -c nginx -- /bin/bash
+
+  # Pass a file to a command in a pod of a deployment
+  skectl exec -i deploy/web -- sh -c 'cat > /tmp/config' < config.yaml`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.ArgsLenAtDash() != 1 {
+				return fmt.Errorf("exec takes a single pod followed by -- and the command, e.g. skectl exec web-1 -- date")
+			}
+			if len(args) < 2 {
+				return fmt.Errorf("you must specify at least one command for the container")
+			}
+			o.in, o.out, o.err = os.Stdin, os.Stdout, os.Stderr
+			return o.run(cmd.Context(), args[0], args[1:])
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.container, "container", "c", "", "Container name, defaults to the default container of the pod")
+	cmd.Flags().BoolVarP(&o.stdin, "stdin", "i", false, "Pass stdin to the container")
+	cmd.Flags().BoolVarP(&o.tty, "tty", "t", false, "Stdin is a TTY")
+
+	return cmd
+}
+
+// NewRshCmd creates a new rsh command
+func NewRshCmd() *cobra.Command {
+	o := &execOptions{stdin: true}
+	var shell string
+
+	cmd := &cobra.Command{
+		Use:   "rsh [-c <container>] (<pod> | <type>/<name>) [command [args...]]",
+		Short: "Start a shell session in a container",
+		Long: `Start a shell session in a container.
+
+Open a remote shell session, by default /bin/sh, in a container of a pod or of
+the first running pod of a workload. A terminal is allocated when the standard
+input is one and no command is given; use -t to force it and -T to disable it.`,
+		Example: `  # Open a shell session on the first container of a pod
+  skectl rsh web-1
+
+  # Open a bash session in the first running pod of a deployment
+  skectl rsh --shell=/bin/bash deploy/web
+
+  # Run a command in a pod
+  skectl rsh web-1 cat /etc/hostname`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if o.tty && o.noTTY {
+				return fmt.Errorf("-t and -T cannot be combined")
+			}
+			command := args[1:]
+			if len(command) == 0 {
+				command = []string{shell}
+				o.tty = o.tty || (!o.noTTY && util.NewTTY(os.Stdin, os.Stdout, false).IsTerminalIn())
+			}
+			o.in, o.out, o.err = os.Stdin, os.Stdout, os.Stderr
+			return o.run(cmd.Context(), args[0], command)
+		},
+	}
+
+	// Everything after the pod is the remote command, including its flags
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().StringVarP(&o.container, "container", "c", "", "Container name, defaults to the default container of the pod")
+	cmd.Flags().BoolVarP(&o.tty, "tty", "t", false, "Force a pseudo-terminal to be allocated")
+	cmd.Flags().BoolVarP(&o.noTTY, "no-tty", "T", false, "Disable pseudo-terminal allocation")
+	cmd.Flags().StringVar(&shell, "shell", "/bin/sh", "Path to the shell command")
+
+	return cmd
+}
+
+// run executes the command in the container of the pod and streams its
+// standard streams, returning an ExitError with the exit code of the command
+func (o *execOptions) run(ctx context.Context, target string, command []string) error {
+	namespace, _, err := configFlags.ToNamespace()
+	if err != nil {
+		return err
+	}
+	pods, err := podsFor(ctx, namespace, target)
+	if err != nil {
+		return err
+	}
+	pod, err := runningPod(pods, target)
+	if err != nil {
+		return err
+	}
+
+	container := o.container
+	if container == "" {
+		name, defaulted, err := resource.DefaultContainer(pod)
+		if err != nil {
+			return err
+		}
+		if defaulted {
+			names := resource.ContainerNames(pod)[len(pod.Spec.InitContainers):]
+			fmt.Fprintf(o.err, "Defaulted container %q out of: %s\n", name, strings.Join(names, ", "))
+		}
+		container = name
+	}
+
+	tty := util.NewTTY(o.in, o.out, o.tty)
+	if o.tty && !o.stdin {
+		fmt.Fprintln(o.err, "Unable to use a TTY - input is not enabled, use -i")
+		tty.Raw = false
+	} else if o.tty && !tty.IsTerminalIn() {
+		fmt.Fprintln(o.err, "Unable to use a TTY - input is not a terminal or the right kind of file")
+		tty.Raw = false
+	}
+
+	clientSet, err := configFlags.ToClientSet()
+	if err != nil {
+		return err
+	}
+	config, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	request := clientSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     o.stdin,
+			Stdout:    true,
+			Stderr:    !tty.Raw,
+			TTY:       tty.Raw,
+		}, scheme.ParameterCodec)
+	executor, err := newExecutor(config, "POST", request.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	streams := remotecommand.StreamOptions{Stdout: o.out, Tty: tty.Raw}
+	if o.stdin {
+		streams.Stdin = o.in
+	}
+	if !tty.Raw {
+		streams.Stderr = o.err
+	}
+	if tty.Raw {
+		if sizes := tty.MonitorSize(); sizes != nil {
+			defer sizes.Stop()
+			streams.TerminalSizeQueue = sizes
+		}
+	}
+
+	err = tty.Safe(func() error {
+		return executor.StreamWithContext(ctx, streams)
+	})
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) && exitErr.Exited() {
+		return &ExitError{Code: exitErr.ExitStatus()}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to execute command in %s/%s: %w", pod.Name, container, err)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/testutil"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// fakeExecutor records the stream options and echoes the standard input
+type fakeExecutor struct {
+	url      *url.URL
+	options  remotecommand.StreamOptions
+	exitCode int
+}
+
+// Stream runs the fake command
+func (e *fakeExecutor) Stream(options remotecommand.StreamOptions) error {
+	return e.StreamWithContext(context.Background(), options)
+}
+
+// StreamWithContext runs the fake command
+func (e *fakeExecutor) StreamWithContext(ctx context.Context, options remotecommand.StreamOptions) error {
+	e.options = options
+	fmt.Fprintf(options.Stdout, "ran %s\n", strings.Join(e.url.Query()["command"], " "))
+	if options.Stdin != nil {
+		input, _ := io.ReadAll(options.Stdin)
+		fmt.Fprintf(options.Stdout, "input %s\n", input)
+	}
+	if e.exitCode != 0 {
+		return utilexec.CodeExitError{Err: fmt.Errorf("command terminated with exit code %d", e.exitCode), Code: e.exitCode}
+	}
+	return nil
+}
+
+func TestExecCmd(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		objects     []string
+		stdin       string
+		exitCode    int
+		expected    []string
+		wantPath    string
+		wantQuery   string
+		expectError bool
+		errContains string
+	}{
+		{
+			name:      "command in the default container",
+			args:      []string{"exec", "web-1", "--", "date", "-u"},
+			expected:  []string{"ran date -u\n"},
+			wantPath:  "/api/v1/namespaces/project-a/pods/web-1/exec",
+			wantQuery: "command=date&command=-u&container=nginx&stderr=true&stdout=true",
+		},
+		{
+			name:      "standard input in a pod of a deployment",
+			args:      []string{"exec", "-i", "deploy/web", "-c", "sidecar", "--", "cat"},
+			stdin:     "hello",
+			expected:  []string{"ran cat\n", "input hello\n"},
+			wantPath:  "/api/v1/namespaces/project-a/pods/web-1/exec",
+			wantQuery: "command=cat&container=sidecar&stderr=true&stdin=true&stdout=true",
+		},
+		{
+			name:      "tty without a terminal",
+			args:      []string{"exec", "-it", "web-1", "--", "sh"},
+			expected:  []string{"ran sh\n"},
+			wantPath:  "/api/v1/namespaces/project-a/pods/web-1/exec",
+			wantQuery: "command=sh&container=nginx&stderr=true&stdin=true&stdout=true",
+		},
+		{
+			name:      "running pod of a deployment listed after a failed one",
+			args:      []string{"exec", "deploy/web", "--", "date"},
+			objects:   []string{"apiVersion: v1\nkind: Pod\nmetadata:\n  name: web-0\n  namespace: project-a\n  labels:\n    app: web\nspec:\n  containers:\n  - name: nginx\nstatus:\n  phase: Failed\n"},
+			expected:  []string{"ran date\n"},
+			wantPath:  "/api/v1/namespaces/project-a/pods/web-1/exec",
+			wantQuery: "command=date&container=nginx&stderr=true&stdout=true",
+		},
+		{
+			name:        "no running pod of a deployment",
+			args:        []string{"exec", "deploy/batch", "--", "date"},
+			objects:     []string{"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: batch\n  namespace: project-a\nspec:\n  selector:\n    matchLabels:\n      app: batch\n", "apiVersion: v1\nkind: Pod\nmetadata:\n  name: batch-1\n  namespace: project-a\n  labels:\n    app: batch\nspec:\n  containers:\n  - name: job\nstatus:\n  phase: Pending\n", "apiVersion: v1\nkind: Pod\nmetadata:\n  name: batch-2\n  namespace: project-a\n  labels:\n    app: batch\nspec:\n  containers:\n  - name: job\nstatus:\n  phase: Succeeded\n"},
+			errContains: "no running pod found for deploy/batch",
+		},
+		{
+			name:     "exit code of the command",
+			args:     []string{"exec", "web-1", "--", "false"},
+			exitCode: 3,
+		},
+		{
+			name:      "rsh defaults to the shell",
+			args:      []string{"rsh", "web-1"},
+			expected:  []string{"ran /bin/sh\n"},
+			wantPath:  "/api/v1/namespaces/project-a/pods/web-1/exec",
+			wantQuery: "command=%2Fbin%2Fsh&container=nginx&stderr=true&stdin=true&stdout=true",
+		},
+		{
+			name:     "rsh with a command and its flags",
+			args:     []string{"rsh", "-c", "sidecar", "web-1", "ls", "-l"},
+			expected: []string{"ran ls -l\n"},
+		},
+		{
+			name:        "missing dash",
+			args:        []string{"exec", "web-1", "date"},
+			expectError: true,
+		},
+		{
+			name:        "completed pod",
+			args:        []string{"exec", "done-1", "--", "date"},
+			errContains: "pod done-1 is not running, current phase is Succeeded",
+		},
+		{
+			name:        "rsh with -t and -T",
+			args:        []string{"rsh", "-t", "-T", "web-1"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupFakeAPIServer(t, "project-a")
+			for _, manifest := range append(tt.objects, logsTestObjects...) {
+				server.AddYAML(manifest)
+			}
+			server.AddYAML("apiVersion: v1\nkind: Pod\nmetadata:\n  name: done-1\n  namespace: project-a\nspec:\n  containers:\n  - name: job\nstatus:\n  phase: Succeeded\n")
+
+			executor := &fakeExecutor{exitCode: tt.exitCode}
+			oldExecutor := newExecutor
+			defer func() { newExecutor = oldExecutor }()
+			newExecutor = func(config *rest.Config, method string, url *url.URL) (remotecommand.Executor, error) {
+				executor.url = url
+				return executor, nil
+			}
+
+			// Provide the input on stdin
+			oldStdin := os.Stdin
+			defer func() { os.Stdin = oldStdin }()
+			r, w, err := os.Pipe()
+			require.NoError(t, err)
+			os.Stdin = r
+			_, err = w.WriteString(tt.stdin)
+			require.NoError(t, err)
+			w.Close()
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(tt.args)
+			err = cmd.Execute()
+			output := capture.Stdout()
+			*configFlags = *client.NewConfigFlags()
+
+			if tt.expectError || tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			assert.Equal(t, tt.exitCode, ExitCode(err), "error: %v", err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, output, expected)
+			}
+			if tt.wantQuery != "" {
+				assert.Equal(t, tt.wantQuery, executor.url.RawQuery)
+				assert.Equal(t, tt.wantPath, executor.url.Path)
+				assert.False(t, executor.options.Tty)
+				assert.NotNil(t, executor.options.Stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ExitError makes the process exit with a given code. A nil Err exits
+// silently, for commands that report their result through the exit code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error returns the message of the wrapped error
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code for the error of a command, 0 without error
+// and 1 unless the error is an ExitError
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
+
+// silent reports whether the error only carries an exit code
+func silent(err error) bool {
+	var exitErr *ExitError
+	return errors.As(err, &exitErr) && exitErr.Err == nil
+}
@@ -0,0 +1,384 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/printers"
+	"github.com/withlin/oc-demo/pkg/resource"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/dynamic"
+)
+
+// getOptions holds the flags of the get command
+type getOptions struct {
+	printFlags    *printers.PrintFlags
+	allNamespaces bool
+	selector      string
+	fieldSelector string
+	watch         bool
+	watchOnly     bool
+}
+
+// NewGetCmd creates a new get command
+func NewGetCmd() *cobra.Command {
+	o := &getOptions{printFlags: printers.NewPrintFlags()}
+
+	cmd := &cobra.Command{
+		Use:   "get <type>[/<name>] [name...]",
+		Short: "Display one or many resources",
+		Long: `Display one or many resources.
+
+Resource types are resolved through the discovery API, so short names such as
+po or deploy, singular and plural names and kinds are all accepted, as well as
+fully qualified names like deployments.v1.apps. The columns are printed by the
+server, the same way kubectl does.`,
+		Example: `  # List all pods in the current namespace
+  skectl get pods
+
+  # List deployments in all namespaces
+  skectl get deploy -A
+
+  # Show a single pod
+  skectl get pod/nginx
+
+  # List pods matching a label and a field selector
+  skectl get po -l app=nginx --field-selector status.phase=Running
+
+  # Print a deployment as YAML
+  skectl get deploy/web -o yaml
+
+  # List pod names and images sorted by creation time
+  skectl get pods -o custom-columns=NAME:.metadata.name,IMAGES:.spec.containers[*].image --sort-by .metadata.creationTimestamp
+
+  # Watch the pods of a deployment after listing them
+  skectl get pods -l app=web -w
+
+  # Watch a single pod without listing it first
+  skectl get pod/web-1 --watch-only -o yaml`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run(cmd.Context(), args)
+		},
+	}
+
+	o.printFlags.AddFlags(cmd.Flags())
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "List the requested objects across all namespaces")
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "Selector (label query) to filter on, supports '=', '==', and '!='")
+	cmd.Flags().StringVar(&o.fieldSelector, "field-selector", "", "Selector (field query) to filter on, supports '=', '==', and '!='")
+	cmd.Flags().BoolVarP(&o.watch, "watch", "w", false, "After listing/getting the requested objects, watch for changes")
+	cmd.Flags().BoolVar(&o.watchOnly, "watch-only", false, "Watch for changes to the requested objects, without listing/getting first")
+
+	return cmd
+}
+
+// run fetches the requested resources and prints them in the output format
+func (o *getOptions) run(ctx context.Context, args []string) error {
+	printer, err := o.printFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+
+	refs, err := resource.ParseRefs(args)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if ref.Name != "" && (o.selector != "" || o.fieldSelector != "") {
+			return fmt.Errorf("name cannot be provided when a selector is specified")
+		}
+		if ref.Name != "" && o.allNamespaces {
+			return fmt.Errorf("a resource cannot be retrieved by name across all namespaces")
+		}
+	}
+
+	namespace, _, err := configFlags.ToNamespace()
+	if err != nil {
+		return err
+	}
+	if o.allNamespaces {
+		namespace = ""
+	}
+
+	mapper, err := configFlags.ToRESTMapper()
+	if err != nil {
+		return err
+	}
+
+	// Group the names by type, keeping the order of the arguments
+	var types []string
+	names := map[string][]string{}
+	for _, ref := range refs {
+		if _, exists := names[ref.Type]; !exists {
+			types = append(types, ref.Type)
+		}
+		if ref.Name != "" {
+			names[ref.Type] = append(names[ref.Type], ref.Name)
+		}
+	}
+
+	mappings := make([]*meta.RESTMapping, len(types))
+	for i, resourceType := range types {
+		if mappings[i], err = resource.Mapping(mapper, resourceType); err != nil {
+			return err
+		}
+	}
+
+	if o.watch || o.watchOnly {
+		if len(mappings) != 1 {
+			return fmt.Errorf("watch is only supported on a single resource type, got %d", len(mappings))
+		}
+		return o.runWatch(ctx, printer, mappings[0], namespace, names[types[0]])
+	}
+
+	var printed bool
+	if o.printFlags.IsTable() {
+		printed, err = o.printTables(ctx, mappings, types, names, namespace)
+	} else {
+		printed, err = o.printObjects(ctx, printer, mappings, types, names, namespace, len(refs) == 1 && refs[0].Name != "")
+	}
+	if err != nil {
+		return err
+	}
+
+	if !printed && refs[0].Name == "" {
+		if namespace == "" {
+			fmt.Fprintln(os.Stderr, "No resources found")
+		} else {
+			fmt.Fprintf(os.Stderr, "No resources found in %s namespace.\n", namespace)
+		}
+	}
+	return nil
+}
+
+// runWatch lists the objects of a single type, unless --watch-only is set, and
+// then streams their changes until interrupted
+func (o *getOptions) runWatch(ctx context.Context, printer printers.ResourcePrinter, mapping *meta.RESTMapping, namespace string, names []string) error {
+	options := metav1.ListOptions{LabelSelector: o.selector, FieldSelector: o.fieldSelector}
+	switch len(names) {
+	case 0:
+	case 1:
+		options.FieldSelector = fields.OneTermEqualSelector("metadata.name", names[0]).String()
+	default:
+		return fmt.Errorf("watch is only supported on individual objects and collections, got %d names", len(names))
+	}
+
+	var dynamicClient dynamic.Interface
+	var err error
+	if o.printFlags.IsTable() {
+		dynamicClient, err = configFlags.ToTableClient("")
+	} else {
+		dynamicClient, err = configFlags.ToDynamicClient()
+	}
+	if err != nil {
+		return err
+	}
+
+	watcher := &resource.Watcher{
+		Client:  resource.ClientFor(dynamicClient, mapping, namespace),
+		Options: options,
+	}
+	events, resourceVersion, err := watcher.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", mapping.Resource.Resource, err)
+	}
+
+	printEvent := o.eventPrinter(printer, o.allNamespaces && resource.Namespaced(mapping))
+	if !o.watchOnly {
+		for _, event := range events {
+			if err := printEvent(event); err != nil {
+				return err
+			}
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	return watcher.Run(ctx, resourceVersion, printEvent)
+}
+
+// eventPrinter returns a function printing watch events in the output format.
+// Tables get a leading EVENT column and the headers are printed once; JSON
+// and YAML print the events themselves; the other formats print the objects.
+func (o *getOptions) eventPrinter(printer printers.ResourcePrinter, withNamespace bool) func(resource.Event) error {
+	headers := !o.printFlags.NoHeaders
+
+	return func(event resource.Event) error {
+		if o.printFlags.IsTable() {
+			table := event.Table
+			if table == nil {
+				table = printers.DefaultTable([]unstructured.Unstructured{*event.Object})
+			}
+			tablePrinter := o.printFlags.ToTablePrinter()
+			tablePrinter.NoHeaders = !headers
+			tablePrinter.WithNamespace = withNamespace
+			headers = false
+			return tablePrinter.PrintTable(withEventColumn(table, event), os.Stdout)
+		}
+
+		switch o.printFlags.OutputFormat {
+		case "json", "yaml":
+			return printer.PrintObj(&unstructured.Unstructured{Object: map[string]interface{}{
+				"type":   string(event.Type),
+				"object": event.Object.Object,
+			}}, os.Stdout)
+		}
+		return printer.PrintObj(event.Object, os.Stdout)
+	}
+}
+
+// withEventColumn returns a copy of the table with the event type as first column
+func withEventColumn(table *metav1.Table, event resource.Event) *metav1.Table {
+	result := &metav1.Table{
+		ColumnDefinitions: append([]metav1.TableColumnDefinition{{Name: "Event", Type: "string"}}, table.ColumnDefinitions...),
+	}
+	for _, row := range table.Rows {
+		row.Cells = append([]interface{}{string(event.Type)}, row.Cells...)
+		result.Rows = append(result.Rows, row)
+	}
+	return result
+}
+
+// printTables prints the server-side table of each type, separated by blank lines
+func (o *getOptions) printTables(ctx context.Context, mappings []*meta.RESTMapping, types []string, names map[string][]string, namespace string) (bool, error) {
+	// Sorting by fields other than the metadata needs the full objects in the rows
+	var includeObject metav1.IncludeObjectPolicy
+	if o.printFlags.SortBy != "" {
+		includeObject = metav1.IncludeObject
+	}
+	tableClient, err := configFlags.ToTableClient(includeObject)
+	if err != nil {
+		return false, err
+	}
+
+	printed := false
+	for i, mapping := range mappings {
+		table, err := o.fetchTable(ctx, tableClient, mapping, namespace, names[types[i]])
+		if err != nil {
+			return printed, err
+		}
+		if len(table.Rows) == 0 {
+			continue
+		}
+
+		tablePrinter := o.printFlags.ToTablePrinter()
+		tablePrinter.WithNamespace = o.allNamespaces && resource.Namespaced(mapping)
+		printer, err := o.printFlags.WithSorting(tablePrinter)
+		if err != nil {
+			return printed, err
+		}
+
+		if printed {
+			fmt.Println()
+		}
+		if err := printer.PrintObj(table, os.Stdout); err != nil {
+			return printed, err
+		}
+		printed = true
+	}
+	return printed, nil
+}
+
+// printObjects prints the objects of every type as a single list, or the
+// object itself when a single object was requested by name
+func (o *getOptions) printObjects(ctx context.Context, printer printers.ResourcePrinter, mappings []*meta.RESTMapping, types []string, names map[string][]string, namespace string, single bool) (bool, error) {
+	dynamicClient, err := configFlags.ToDynamicClient()
+	if err != nil {
+		return false, err
+	}
+
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"metadata":   map[string]interface{}{"resourceVersion": ""},
+	}}
+	for i, mapping := range mappings {
+		items, err := o.fetchObjects(ctx, dynamicClient, mapping, namespace, names[types[i]])
+		if err != nil {
+			return false, err
+		}
+		list.Items = append(list.Items, items...)
+	}
+
+	if single {
+		return true, printer.PrintObj(&list.Items[0], os.Stdout)
+	}
+	return len(list.Items) > 0, printer.PrintObj(list, os.Stdout)
+}
+
+// fetchObjects lists the objects of a mapping, or gets the named ones
+func (o *getOptions) fetchObjects(ctx context.Context, dynamicClient dynamic.Interface, mapping *meta.RESTMapping, namespace string, names []string) ([]unstructured.Unstructured, error) {
+	resourceClient := resource.ClientFor(dynamicClient, mapping, namespace)
+
+	if len(names) == 0 {
+		list, err := resourceClient.List(ctx, metav1.ListOptions{
+			LabelSelector: o.selector,
+			FieldSelector: o.fieldSelector,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", mapping.Resource.Resource, err)
+		}
+		return list.Items, nil
+	}
+
+	var items []unstructured.Unstructured
+	for _, name := range names {
+		obj, err := resourceClient.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s %q: %w", mapping.Resource.Resource, name, err)
+		}
+		items = append(items, *obj)
+	}
+	return items, nil
+}
+
+// fetchTable lists the objects of a mapping, or gets the named ones, as a table
+func (o *getOptions) fetchTable(ctx context.Context, tableClient dynamic.Interface, mapping *meta.RESTMapping, namespace string, names []string) (*metav1.Table, error) {
+	resourceClient := resource.ClientFor(tableClient, mapping, namespace)
+
+	if len(names) == 0 {
+		list, err := resourceClient.List(ctx, metav1.ListOptions{
+			LabelSelector: o.selector,
+			FieldSelector: o.fieldSelector,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", mapping.Resource.Resource, err)
+		}
+		return toTable(list.Object, list.Items)
+	}
+
+	table := &metav1.Table{}
+	for _, name := range names {
+		obj, err := resourceClient.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s %q: %w", mapping.Resource.Resource, name, err)
+		}
+		objTable, err := toTable(obj.Object, []unstructured.Unstructured{*obj})
+		if err != nil {
+			return nil, err
+		}
+		table.ColumnDefinitions = objTable.ColumnDefinitions
+		table.Rows = append(table.Rows, objTable.Rows...)
+	}
+	return table, nil
+}
+
+// toTable returns the server-side table of a response, or a default table of
+// its objects when the server does not support server-side printing
+func toTable(obj map[string]interface{}, items []unstructured.Unstructured) (*metav1.Table, error) {
+	table, ok, err := client.AsTable(obj)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		table = printers.DefaultTable(items)
+	}
+	return table, nil
+}
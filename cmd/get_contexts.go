@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/printers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// NewGetContextsCmd creates a new get-contexts command
+func NewGetContextsCmd() *cobra.Command {
+	printFlags := printers.NewPrintFlags()
+
+	cmd := &cobra.Command{
+		Use:   "get-contexts [name...]",
+		Short: "Describe one or many contexts",
+		Example: `  # List all the contexts in your kubeconfig file
+  skectl get-contexts
+
+  # List only the context names
+  skectl get-contexts -o name
+
+  # List the contexts sorted by cluster
+  skectl get-contexts --sort-by .context.cluster`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := printFlags.ToPrinter()
+			if err != nil {
+				return err
+			}
+
+			// Load kubeconfig
+			_, config, err := loadKubeconfig()
+			if err != nil {
+				return err
+			}
+
+			// Select the requested contexts, or all of them
+			names := args
+			if len(names) == 0 {
+				for name := range config.Contexts {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+			}
+			for _, name := range names {
+				if _, exists := config.Contexts[name]; !exists {
+					return fmt.Errorf("context %q does not exist", name)
+				}
+			}
+
+			if printFlags.IsTable() {
+				return printer.PrintObj(contextsTable(config, names), os.Stdout)
+			}
+			return printer.PrintObj(contextsList(config, names), os.Stdout)
+		},
+	}
+
+	printFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// namedContext returns a context in its kubeconfig form, {name, context}
+func namedContext(name string, context *api.Context) map[string]interface{} {
+	return map[string]interface{}{
+		"name": name,
+		"context": map[string]interface{}{
+			"cluster":   context.Cluster,
+			"user":      context.AuthInfo,
+			"namespace": context.Namespace,
+		},
+	}
+}
+
+// contextsList returns the contexts as a list for the object printers
+func contextsList(config *api.Config, names []string) *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+	}}
+	for _, name := range names {
+		list.Items = append(list.Items, unstructured.Unstructured{Object: namedContext(name, config.Contexts[name])})
+	}
+	return list
+}
+
+// contextsTable returns the contexts as a table marking the current context
+func contextsTable(config *api.Config, names []string) *metav1.Table {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Current", Type: "string"},
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Cluster", Type: "string"},
+			{Name: "AuthInfo", Type: "string"},
+			{Name: "Namespace", Type: "string"},
+		},
+	}
+	for _, name := range names {
+		context := config.Contexts[name]
+		current := ""
+		if name == config.CurrentContext {
+			current = "*"
+		}
+		raw, _ := json.Marshal(namedContext(name, context))
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells:  []interface{}{current, name, context.Cluster, context.AuthInfo, context.Namespace},
+			Object: runtime.RawExtension{Raw: raw},
+		})
+	}
+	return table
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/testutil"
+)
+
+func TestGetContextsCmd(t *testing.T) {
+	setupTestKubeconfig(t)
+
+	tests := []struct {
+		name        string
+		args        []string
+		expected    []string
+		unexpected  []string
+		expectError bool
+	}{
+		{
+			name: "list all contexts",
+			args: []string{},
+			expected: []string{
+				"CURRENT   NAME       CLUSTER     AUTHINFO              NAMESPACE",
+				"*         context1   cluster-a   developer/cluster-a   project-a",
+				"          context2   cluster-b   developer/cluster-b",
+			},
+		},
+		{
+			name:       "list selected context",
+			args:       []string{"context2"},
+			expected:   []string{"context2"},
+			unexpected: []string{"context1"},
+		},
+		{
+			name:     "list context names",
+			args:     []string{"-o", "name"},
+			expected: []string{"context1\ncontext2\n"},
+		},
+		{
+			name:     "list contexts without headers sorted by cluster",
+			args:     []string{"--no-headers", "--sort-by", ".context.cluster"},
+			expected: []string{"*   context1   cluster-a   developer/cluster-a   project-a\n    context2   cluster-b"},
+		},
+		{
+			name:     "list context namespaces with jsonpath",
+			args:     []string{"-o", "jsonpath={.items[*].context.namespace}"},
+			expected: []string{"project-a "},
+		},
+		{
+			name:     "print context as yaml",
+			args:     []string{"context2", "-o", "yaml"},
+			expected: []string{"- context:\n    cluster: cluster-b\n", "  name: context2\n"},
+		},
+		{
+			name:        "unknown context",
+			args:        []string{"unknown"},
+			expectError: true,
+		},
+		{
+			name:        "unsupported output format",
+			args:        []string{"-o", "xml"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewGetContextsCmd()
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			output := capture.Stdout()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, output, expected)
+			}
+			for _, unexpected := range tt.unexpected {
+				assert.NotContains(t, output, unexpected)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/testutil"
+)
+
+func TestGetCmd(t *testing.T) {
+	server := setupFakeAPIServer(t, "project-a")
+	server.AddYAML(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: frontend
+  namespace: project-a
+  labels:
+    app: web
+`)
+	server.AddYAML(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: worker
+  namespace: project-a
+  labels:
+    app: batch
+`)
+	server.AddYAML(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: database
+  namespace: project-b
+`)
+	server.AddYAML(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+  namespace: project-a
+`)
+
+	tests := []struct {
+		name        string
+		args        []string
+		expected    []string
+		unexpected  []string
+		wantRequest string
+		expectError bool
+	}{
+		{
+			name:        "list by plural name",
+			args:        []string{"pods"},
+			expected:    []string{"NAME\n", "frontend\n", "worker\n"},
+			unexpected:  []string{"database", "NAMESPACE"},
+			wantRequest: "GET /api/v1/namespaces/project-a/pods",
+		},
+		{
+			name:     "list by short name",
+			args:     []string{"po"},
+			expected: []string{"frontend\n", "worker\n"},
+		},
+		{
+			name:        "list by kind in another API group",
+			args:        []string{"Deployment"},
+			expected:    []string{"frontend\n"},
+			wantRequest: "GET /apis/apps/v1/namespaces/project-a/deployments",
+		},
+		{
+			name:        "get by singular name and object name",
+			args:        []string{"pod/worker"},
+			expected:    []string{"worker\n"},
+			unexpected:  []string{"frontend"},
+			wantRequest: "GET /api/v1/namespaces/project-a/pods/worker",
+		},
+		{
+			name:     "get several types",
+			args:     []string{"pods,deploy", "frontend"},
+			expected: []string{"NAME\nfrontend\n\nNAME\nfrontend\n"},
+		},
+		{
+			name:        "namespace flag",
+			args:        []string{"-n", "project-b", "pods"},
+			expected:    []string{"database\n"},
+			unexpected:  []string{"frontend"},
+			wantRequest: "GET /api/v1/namespaces/project-b/pods",
+		},
+		{
+			name:        "all namespaces",
+			args:        []string{"pods", "-A"},
+			expected:    []string{"NAMESPACE", "project-a   frontend", "project-b   database"},
+			wantRequest: "GET /api/v1/pods",
+		},
+		{
+			name:        "label selector",
+			args:        []string{"pods", "-l", "app=web"},
+			expected:    []string{"frontend\n"},
+			unexpected:  []string{"worker"},
+			wantRequest: "GET /api/v1/namespaces/project-a/pods?labelSelector=app%3Dweb",
+		},
+		{
+			name:        "field selector",
+			args:        []string{"pods", "--field-selector", "metadata.name=worker"},
+			expected:    []string{"worker\n"},
+			unexpected:  []string{"frontend"},
+			wantRequest: "GET /api/v1/namespaces/project-a/pods?fieldSelector=metadata.name%3Dworker",
+		},
+		{
+			name:     "name output",
+			args:     []string{"pods,deploy", "-o", "name"},
+			expected: []string{"pod/frontend\npod/worker\ndeployment.apps/frontend\n"},
+		},
+		{
+			name:     "json output of a single object",
+			args:     []string{"pod/worker", "-o", "json"},
+			expected: []string{"{\n    \"apiVersion\": \"v1\",\n    \"kind\": \"Pod\",", "\"name\": \"worker\""},
+		},
+		{
+			name:     "yaml output of a list",
+			args:     []string{"pods", "-o", "yaml"},
+			expected: []string{"apiVersion: v1\nitems:\n", "  kind: Pod\n", "kind: List\n"},
+		},
+		{
+			name:     "jsonpath output",
+			args:     []string{"pods", "-o", "jsonpath={.items[*].metadata.labels.app}"},
+			expected: []string{"web batch"},
+		},
+		{
+			name:     "sorted table without headers",
+			args:     []string{"pods", "--sort-by", ".metadata.labels.app", "--no-headers"},
+			expected: []string{"worker\nfrontend\n"},
+		},
+		{
+			name:        "sorted table requests full objects",
+			args:        []string{"pods", "--sort-by", ".metadata.name"},
+			wantRequest: "GET /api/v1/namespaces/project-a/pods?includeObject=Object",
+		},
+		{
+			name:     "wide output",
+			args:     []string{"pods", "-o", "wide"},
+			expected: []string{"NAME       LABELS\n", "frontend   app=web\n"},
+		},
+		{
+			name:        "unknown output format",
+			args:        []string{"pods", "-o", "xml"},
+			expectError: true,
+		},
+		{
+			name:        "unknown resource type",
+			args:        []string{"widgets"},
+			expectError: true,
+		},
+		{
+			name:        "object not found",
+			args:        []string{"pod/missing"},
+			expectError: true,
+		},
+		{
+			name:        "name with selector",
+			args:        []string{"pod/worker", "-l", "app=web"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(append([]string{"get"}, tt.args...))
+			err := cmd.Execute()
+			output := capture.Stdout()
+			*configFlags = *client.NewConfigFlags()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, output, expected)
+			}
+			for _, unexpected := range tt.unexpected {
+				assert.NotContains(t, output, unexpected)
+			}
+			if tt.wantRequest != "" {
+				assert.Contains(t, server.Requests(), tt.wantRequest)
+			}
+		})
+	}
+}
+
+func TestGetCmdNoResources(t *testing.T) {
+	setupFakeAPIServer(t, "empty")
+
+	capture := testutil.NewCaptureOutput()
+	require.NoError(t, capture.Start(), "Failed to start output capture")
+	defer capture.Stop()
+
+	cmd := NewGetCmd()
+	cmd.SetArgs([]string{"pods"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "No resources found in empty namespace.\n", capture.Stderr())
+}
+
+func TestGetCmdWatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name: "watch table",
+			args: []string{"pods", "-w", "-l", "app=web"},
+			expected: []string{
+				"EVENT   NAME\nADDED   frontend\n",
+				"ADDED   backend\n",
+				"MODIFIED   frontend\n",
+				"DELETED   backend\n",
+			},
+			unexpected: []string{"worker"},
+		},
+		{
+			name:       "watch only as json events",
+			args:       []string{"pods", "--watch-only", "-o", "json"},
+			expected:   []string{"\"type\": \"ADDED\"", "\"type\": \"MODIFIED\"", "\"type\": \"DELETED\"", "\"name\": \"backend\""},
+			unexpected: []string{"\"resourceVersion\": \"1\""},
+		},
+		{
+			name:       "watch single object by name",
+			args:       []string{"pod/frontend", "-w", "-o", "name"},
+			expected:   []string{"pod/frontend\npod/frontend\n"},
+			unexpected: []string{"backend"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupFakeAPIServer(t, "project-a")
+			server.AddYAML("{apiVersion: v1, kind: Pod, metadata: {name: frontend, namespace: project-a, labels: {app: web}}}")
+			server.AddYAML("{apiVersion: v1, kind: Pod, metadata: {name: worker, namespace: project-a, labels: {app: batch}}}")
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			done := make(chan error, 1)
+			go func() {
+				cmd := NewRootCmd()
+				cmd.SetArgs(append([]string{"get"}, tt.args...))
+				done <- cmd.ExecuteContext(ctx)
+			}()
+
+			waitForRequests(t, server, "watch=true", 1)
+			server.AddYAML("{apiVersion: v1, kind: Pod, metadata: {name: backend, namespace: project-a, labels: {app: web}}}")
+			server.AddYAML("{apiVersion: v1, kind: Pod, metadata: {name: worker, namespace: project-a, labels: {app: batch, tier: jobs}}}")
+			server.AddYAML("{apiVersion: v1, kind: Pod, metadata: {name: frontend, namespace: project-a, labels: {app: web, tier: ui}}}")
+			server.DeleteObject("v1", "Pod", "project-a", "backend")
+
+			// Reopening the closed watch shows the events were consumed
+			server.CloseWatches()
+			waitForRequests(t, server, "watch=true", 2)
+			cancel()
+			require.NoError(t, <-done)
+			*configFlags = *client.NewConfigFlags()
+
+			output := capture.Stdout()
+			for _, expected := range tt.expected {
+				assert.Contains(t, output, expected)
+			}
+			for _, unexpected := range tt.unexpected {
+				assert.NotContains(t, output, unexpected)
+			}
+		})
+	}
+}
+
+// waitForRequests waits until the server received count requests containing the text
+func waitForRequests(t *testing.T, server *testutil.FakeAPIServer, text string, count int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		matched := 0
+		for _, request := range server.Requests() {
+			if strings.Contains(request, text) {
+				matched++
+			}
+		}
+		return matched >= count
+	}, 5*time.Second, 10*time.Millisecond, "fewer than %d requests containing %q", count, text)
+}
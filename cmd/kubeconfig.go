@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// loadKubeconfig loads the kubeconfig merged across every file of the KUBECONFIG
+// path list (or ~/.kube/config when unset), together with the access used to
+// write changes back. The first file to set an entry wins, as in kubectl.
+func loadKubeconfig() (clientcmd.ConfigAccess, *api.Config, error) {
+	configAccess := configFlags.ConfigAccess()
+	rawConfig, err := configAccess.GetStartingConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	return configAccess, rawConfig, nil
+}
+
+// saveKubeconfig writes the changes back following the kubectl semantics: each
+// modified entry goes to the file it was loaded from, while the current context
+// and new entries go to the first existing file of the KUBECONFIG list
+func saveKubeconfig(configAccess clientcmd.ConfigAccess, rawConfig *api.Config) error {
+	if err := clientcmd.ModifyConfig(configAccess, *rawConfig, true); err != nil {
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	return nil
+}
+
+// currentContext returns the context selected by the --context flag or the
+// current context, together with its cluster and user
+func currentContext(rawConfig *api.Config) (*api.Context, *api.Cluster, *api.AuthInfo, error) {
+	contextName := rawConfig.CurrentContext
+	if configFlags.Context != "" {
+		contextName = configFlags.Context
+	}
+
+	context, exists := rawConfig.Contexts[contextName]
+	if !exists {
+		if configFlags.Context != "" {
+			return nil, nil, nil, fmt.Errorf("context %q does not exist", contextName)
+		}
+		return nil, nil, nil, fmt.Errorf("no current context is set, you are not logged in")
+	}
+
+	cluster, exists := rawConfig.Clusters[context.Cluster]
+	if !exists {
+		return nil, nil, nil, fmt.Errorf("cluster %q of the current context does not exist", context.Cluster)
+	}
+
+	authInfo, exists := rawConfig.AuthInfos[context.AuthInfo]
+	if !exists {
+		authInfo = api.NewAuthInfo()
+	}
+
+	return context, cluster, authInfo, nil
+}
+
+// toRESTConfig returns the REST config for API clients, with the global flags applied
+func toRESTConfig() (*rest.Config, error) {
+	return configFlags.ToRESTConfig()
+}
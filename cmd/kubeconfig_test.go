@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/testutil"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// setupTestKubeconfig writes a kubeconfig holding two contexts, points
+// KUBECONFIG at it and returns its path
+func setupTestKubeconfig(t *testing.T) string {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	kubeconfigPath := filepath.Join(tmpDir, "config")
+	t.Setenv("KUBECONFIG", kubeconfigPath)
+
+	config := api.NewConfig()
+	config.Clusters["cluster-a"] = &api.Cluster{Server: "https://cluster-a.example.com:6443"}
+	config.Clusters["cluster-b"] = &api.Cluster{Server: "https://cluster-b.example.com:6443"}
+	config.AuthInfos["developer/cluster-a"] = &api.AuthInfo{Token: "token-a"}
+	config.AuthInfos["developer/cluster-b"] = &api.AuthInfo{Token: "token-b"}
+	config.Contexts["context1"] = &api.Context{Cluster: "cluster-a", AuthInfo: "developer/cluster-a", Namespace: "project-a"}
+	config.Contexts["context2"] = &api.Context{Cluster: "cluster-b", AuthInfo: "developer/cluster-b"}
+	config.CurrentContext = "context1"
+	require.NoError(t, clientcmd.WriteToFile(*config, kubeconfigPath))
+
+	return kubeconfigPath
+}
+
+// setupFakeAPIServer starts a fake API server, points KUBECONFIG at a
+// kubeconfig logged in to it with the given namespace and returns the server
+func setupFakeAPIServer(t *testing.T, namespace string) *testutil.FakeAPIServer {
+	t.Helper()
+
+	server := testutil.NewFakeAPIServer()
+	t.Cleanup(server.Close)
+
+	kubeconfigPath := filepath.Join(t.TempDir(), "config")
+	t.Setenv("KUBECONFIG", kubeconfigPath)
+	require.NoError(t, server.WriteKubeconfig(kubeconfigPath, namespace))
+
+	return server
+}
+
+func TestLoadKubeconfig(t *testing.T) {
+	t.Run("loads the kubeconfig", func(t *testing.T) {
+		setupTestKubeconfig(t)
+
+		_, config, err := loadKubeconfig()
+		require.NoError(t, err)
+		assert.Len(t, config.Contexts, 2)
+		assert.Equal(t, "context1", config.CurrentContext)
+	})
+
+	t.Run("missing kubeconfig is empty", func(t *testing.T) {
+		t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+
+		_, config, err := loadKubeconfig()
+		require.NoError(t, err)
+		assert.Empty(t, config.Contexts)
+	})
+
+	t.Run("invalid kubeconfig fails", func(t *testing.T) {
+		kubeconfigPath := filepath.Join(t.TempDir(), "config")
+		require.NoError(t, os.WriteFile(kubeconfigPath, []byte("not: [valid"), 0600))
+		t.Setenv("KUBECONFIG", kubeconfigPath)
+
+		_, _, err := loadKubeconfig()
+		assert.Error(t, err)
+	})
+}
+
+func TestCurrentContext(t *testing.T) {
+	setupTestKubeconfig(t)
+	_, config, err := loadKubeconfig()
+	require.NoError(t, err)
+
+	context, cluster, authInfo, err := currentContext(config)
+	require.NoError(t, err)
+	assert.Equal(t, "project-a", context.Namespace)
+	assert.Equal(t, "https://cluster-a.example.com:6443", cluster.Server)
+	assert.Equal(t, "token-a", authInfo.Token)
+
+	config.CurrentContext = "unknown"
+	_, _, _, err = currentContext(config)
+	assert.Error(t, err)
+}
+
+// setupTestKubeconfigList writes one kubeconfig file per context, points
+// KUBECONFIG at the list of files and returns their paths. Only the first
+// file sets the current context.
+func setupTestKubeconfigList(t *testing.T, contexts ...string) []string {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	var paths []string
+	for i, name := range contexts {
+		config := api.NewConfig()
+		config.Clusters["cluster-"+name] = &api.Cluster{Server: "https://" + name + ".example.com:6443"}
+		config.AuthInfos["user-"+name] = &api.AuthInfo{Token: "token-" + name}
+		config.Contexts[name] = &api.Context{Cluster: "cluster-" + name, AuthInfo: "user-" + name}
+		if i == 0 {
+			config.CurrentContext = name
+		}
+
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, clientcmd.WriteToFile(*config, path))
+		paths = append(paths, path)
+	}
+	t.Setenv("KUBECONFIG", strings.Join(paths, string(filepath.ListSeparator)))
+
+	return paths
+}
+
+func TestSaveKubeconfigList(t *testing.T) {
+	paths := setupTestKubeconfigList(t, "a", "b", "c")
+
+	configAccess, config, err := loadKubeconfig()
+	require.NoError(t, err)
+
+	// The merged view holds the entries of every file
+	assert.Len(t, config.Contexts, 3)
+	assert.Equal(t, paths[1], config.Contexts["b"].LocationOfOrigin)
+
+	// Modify a context from the second file, add a new one and switch to it
+	config.Contexts["b"].Namespace = "project-b"
+	config.Contexts["new"] = &api.Context{Cluster: "cluster-c", AuthInfo: "user-c"}
+	config.CurrentContext = "new"
+	require.NoError(t, saveKubeconfig(configAccess, config))
+
+	first, err := clientcmd.LoadFromFile(paths[0])
+	require.NoError(t, err)
+	second, err := clientcmd.LoadFromFile(paths[1])
+	require.NoError(t, err)
+	third, err := clientcmd.LoadFromFile(paths[2])
+	require.NoError(t, err)
+
+	// The current context and new entries go to the first file
+	assert.Equal(t, "new", first.CurrentContext)
+	assert.Contains(t, first.Contexts, "new")
+	assert.Contains(t, first.Contexts, "a")
+
+	// The modified context stays in the file it came from
+	assert.Equal(t, "project-b", second.Contexts["b"].Namespace)
+	assert.NotContains(t, first.Contexts, "b")
+	assert.Empty(t, second.CurrentContext)
+
+	// Untouched files stay untouched
+	assert.Len(t, third.Contexts, 1)
+	assert.Empty(t, third.Contexts["c"].Namespace)
+}
@@ -1,21 +1,46 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/withlin/oc-demo/pkg/auth"
+	"github.com/withlin/oc-demo/pkg/kubeconfig"
+	"github.com/withlin/oc-demo/pkg/project"
 	"github.com/withlin/oc-demo/pkg/util"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
+// tokenEnvVar is the environment variable holding a bearer token for login
+const tokenEnvVar = "SKECTL_TOKEN"
+
+// stdinIsTerminal reports whether the standard input is a terminal to prompt
+// on, replaced in tests
+var stdinIsTerminal = func() bool {
+	return util.NewTTY(os.Stdin, os.Stdout, false).IsTerminalIn()
+}
+
 var (
-	username string
-	password string
-	server   string
+	username              string
+	password              string
+	token                 string
+	server                string
+	insecureSkipTLSVerify bool
+	certificateAuthority  string
+	execCommand           string
+	execArgs              []string
+	execEnv               []string
+	execAPIVersion        string
 )
 
 // NewLoginCmd creates a new login command
@@ -23,12 +48,23 @@ func NewLoginCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "login [flags] <server>",
 		Short: "Log in to a server",
-		Long:  "Log in to a server using username and password authentication",
+		Long: `Log in to a server using username and password, bearer token or credential
+plugin authentication.
+
+With --exec-command the kubeconfig user runs the credential plugin, a local
+binary printing a client.authentication.k8s.io ExecCredential, to get a token
+whenever one is needed instead of storing a static token.`,
 		Example: `  # Log in to a server with username
   skectl login https://api.example.com -u admin
   
   # Log in to a server with username and password
-  skectl login https://api.example.com -u admin -p password123`,
+  skectl login https://api.example.com -u admin -p password123
+
+  # Log in to a server with a bearer token
+  skectl login https://api.example.com --token=sha256~xxxx
+
+  # Log in to a server with a credential plugin
+  skectl login https://api.example.com --exec-command=vault-token --exec-arg=--role=dev --exec-env=VAULT_ADDR=https://vault.example.com`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 {
 				return fmt.Errorf("server URL is required")
@@ -40,103 +76,410 @@ func NewLoginCmd() *cobra.Command {
 				return fmt.Errorf("server URL cannot be empty")
 			}
 
-			// Get username if not provided
-			if username == "" {
-				fmt.Print("Enter username: ")
-				var err error
-				username, err = util.ReadInput("")
-				if err != nil {
-					return fmt.Errorf("failed to read username: %w", err)
-				}
-				if username == "" {
-					return fmt.Errorf("username cannot be empty")
-				}
+			if execCommand != "" && (token != "" || username != "" || password != "") {
+				return fmt.Errorf("--exec-command cannot be combined with --token, --username or --password")
 			}
-
-			// Get password if not provided
-			if password == "" {
-				var err error
-				password, err = util.ReadPassword("Enter password: ")
-				if err != nil {
-					return fmt.Errorf("failed to read password: %w", err)
-				}
-				if password == "" {
-					return fmt.Errorf("password cannot be empty")
-				}
+			if execCommand == "" && (len(execArgs) > 0 || len(execEnv) > 0) {
+				return fmt.Errorf("--exec-arg and --exec-env require --exec-command")
 			}
 
-			// Create authenticator
-			config := &auth.Config{
-				Server: server,
+			// Fall back to the token from the environment when no credentials were given
+			if token == "" && username == "" && password == "" && execCommand == "" {
+				token = os.Getenv(tokenEnvVar)
 			}
-			authenticator, err := auth.NewAuthenticator(config)
+
+			// Load the existing kubeconfig to reuse previously saved TLS settings
+			configAccess, rawConfig, err := loadKubeconfig()
 			if err != nil {
-				return fmt.Errorf("failed to create authenticator: %w", err)
+				return err
 			}
 
-			// Authenticate user
-			authToken, err := authenticator.Authenticate(username, password)
+			clusterName, err := kubeconfig.ClusterNickname(server)
 			if err != nil {
-				return fmt.Errorf("authentication failed: %w", err)
+				return err
+			}
+			cluster, exists := rawConfig.Clusters[clusterName]
+			if !exists {
+				cluster = api.NewCluster()
+			}
+			cluster.Server = server
+			if err := applyTLSFlags(cluster); err != nil {
+				return err
 			}
 
-			// Create kubeconfig
-			kubeconfig := api.NewConfig()
+			// Create authenticator configuration
+			config := newAuthConfig(cluster)
 
-			// Create cluster
-			cluster := api.NewCluster()
-			cluster.Server = server
-			cluster.InsecureSkipTLSVerify = true
-			kubeconfig.Clusters[server] = cluster
-
-			// Create auth info
-			authInfo := api.NewAuthInfo()
-			authInfo.Token = authToken
-			kubeconfig.AuthInfos[server] = authInfo
-
-			// Create context
-			context := api.NewContext()
-			context.Cluster = server
-			context.AuthInfo = server
-			kubeconfig.Contexts[server] = context
-
-			// Set current context
-			kubeconfig.CurrentContext = server
-
-			// Get kubeconfig path
-			kubeconfigPath := os.Getenv("KUBECONFIG")
-			if kubeconfigPath == "" {
-				homeDir, err := os.UserHomeDir()
+			// Ask whether to trust the server when its certificate cannot be verified
+			if err := confirmServerCertificate(config, cluster, util.NewInputReader()); err != nil {
+				return err
+			}
+
+			credentials := api.NewAuthInfo()
+			switch {
+			case execCommand != "":
+				// Validate the token of the credential plugin against the server,
+				// the plugin being run by the client-go exec transport
+				if credentials.Exec, err = newExecConfig(); err != nil {
+					return err
+				}
+				user, err := currentUser(cmd.Context(), cluster, credentials)
+				if err != nil {
+					return fmt.Errorf("credential plugin token validation failed: %w", err)
+				}
+				username = user.Username
+			case token != "":
+				// Validate the token against the server
+				credentials.Token = token
+				user, err := currentUser(cmd.Context(), cluster, credentials)
 				if err != nil {
-					return fmt.Errorf("failed to get home directory: %w", err)
+					return fmt.Errorf("token validation failed: %w", err)
+				}
+				username = user.Username
+			default:
+				if credentials.Token, err = loginWithCredentials(config); err != nil {
+					return err
 				}
-				kubeconfigPath = filepath.Join(homeDir, ".kube", "config")
 			}
 
-			// Create directory if not exists
-			if err := os.MkdirAll(filepath.Dir(kubeconfigPath), 0755); err != nil {
-				return fmt.Errorf("failed to create kubeconfig directory: %w", err)
+			fmt.Printf("Successfully logged in as %s to %s\n", username, server)
+
+			// Pick the project, keeping the one previously used by this user
+			restConfig, err := newRESTConfig(cluster, credentials)
+			if err != nil {
+				return err
+			}
+			// Projects are listed as the user of the --as flag, if any
+			restConfig.Impersonate = configFlags.ImpersonationConfig()
+			namespace := previousNamespace(rawConfig, clusterName, kubeconfig.UserNickname(username, clusterName))
+			namespace, err = selectProject(cmd.Context(), restConfig, namespace, util.NewInputReader())
+			if err != nil {
+				return err
 			}
 
-			// Write kubeconfig
-			if err := clientcmd.WriteToFile(*kubeconfig, kubeconfigPath); err != nil {
-				return fmt.Errorf("failed to write kubeconfig: %w", err)
+			// Merge the login result into the existing kubeconfig
+			contextName, err := updateKubeconfig(configAccess, rawConfig, clusterName, cluster, credentials, namespace)
+			if err != nil {
+				return err
 			}
 
-			fmt.Printf("Successfully logged in as %s to %s\n", username, server)
+			fmt.Printf("Using project %q\n", namespace)
+			fmt.Printf("Using context %q\n", contextName)
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&username, "username", "u", "", "Username for authentication")
 	cmd.Flags().StringVarP(&password, "password", "p", "", "Password for authentication")
-	cmd.Flags().Bool("insecure-skip-tls-verify", false, "Skip TLS certificate verification")
+	cmd.Flags().StringVar(&token, "token", "", "Bearer token for authentication (defaults to $"+tokenEnvVar+")")
+	cmd.Flags().BoolVar(&insecureSkipTLSVerify, "insecure-skip-tls-verify", false, "Skip TLS certificate verification, making HTTPS connections insecure")
+	cmd.Flags().StringVar(&certificateAuthority, "certificate-authority", "", "Path to a cert file for the certificate authority")
+	cmd.Flags().StringVar(&execCommand, "exec-command", "", "Credential plugin to run for a token, saved in the kubeconfig instead of a token")
+	cmd.Flags().StringArrayVar(&execArgs, "exec-arg", nil, "Argument of the credential plugin, can be repeated")
+	cmd.Flags().StringArrayVar(&execEnv, "exec-env", nil, "NAME=VALUE environment variable of the credential plugin, can be repeated")
+	cmd.Flags().StringVar(&execAPIVersion, "exec-api-version", auth.DefaultExecAPIVersion, "ExecCredential API version of the credential plugin")
 
 	return cmd
 }
 
-var loginCmd = NewLoginCmd()
+// loginWithCredentials prompts for missing credentials and exchanges them for a token
+func loginWithCredentials(config *auth.Config) (string, error) {
+	// Get username if not provided
+	if username == "" {
+		fmt.Print("Enter username: ")
+		var err error
+		username, err = util.ReadInput("")
+		if err != nil {
+			return "", fmt.Errorf("failed to read username: %w", err)
+		}
+		if username == "" {
+			return "", fmt.Errorf("username cannot be empty")
+		}
+	}
+
+	// Get password if not provided
+	if password == "" {
+		var err error
+		password, err = util.ReadPassword("Enter password: ")
+		if err != nil {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		if password == "" {
+			return "", fmt.Errorf("password cannot be empty")
+		}
+	}
+
+	// Create authenticator, preferring OpenShift OAuth when the server supports it
+	authenticator, err := auth.DetectAuthenticator(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to create authenticator: %w", err)
+	}
+
+	// Authenticate user
+	authToken, err := authenticator.Authenticate(username, password)
+	if err != nil {
+		return "", fmt.Errorf("authentication failed: %w", err)
+	}
+
+	return authToken, nil
+}
+
+// newExecConfig returns the kubeconfig exec user of the --exec flags. A command
+// given as a path is made absolute so the kubeconfig works from any directory.
+func newExecConfig() (*api.ExecConfig, error) {
+	env, err := auth.ParseExecEnv(execEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	command := execCommand
+	if strings.ContainsRune(command, filepath.Separator) {
+		if command, err = filepath.Abs(command); err != nil {
+			return nil, fmt.Errorf("failed to resolve credential plugin path: %w", err)
+		}
+	}
+
+	return &api.ExecConfig{
+		Command:         command,
+		Args:            execArgs,
+		Env:             env,
+		APIVersion:      execAPIVersion,
+		InteractiveMode: api.IfAvailableExecInteractiveMode,
+	}, nil
+}
+
+// newAuthConfig creates the authenticator configuration for a kubeconfig cluster
+func newAuthConfig(cluster *api.Cluster) *auth.Config {
+	config := auth.DefaultConfig()
+	config.Server = cluster.Server
+	config.InsecureSkipVerify = cluster.InsecureSkipTLSVerify
+	config.CAData = cluster.CertificateAuthorityData
+	return config
+}
+
+// applyTLSFlags applies the TLS flags to the cluster, embedding the certificate
+// authority so the kubeconfig does not depend on the file staying in place
+func applyTLSFlags(cluster *api.Cluster) error {
+	if insecureSkipTLSVerify && certificateAuthority != "" {
+		return fmt.Errorf("--insecure-skip-tls-verify and --certificate-authority are mutually exclusive")
+	}
+
+	switch {
+	case insecureSkipTLSVerify:
+		cluster.InsecureSkipTLSVerify = true
+		cluster.CertificateAuthority = ""
+		cluster.CertificateAuthorityData = nil
+	case certificateAuthority != "":
+		caData, err := os.ReadFile(certificateAuthority)
+		if err != nil {
+			return fmt.Errorf("failed to read certificate authority: %w", err)
+		}
+		cluster.InsecureSkipTLSVerify = false
+		cluster.CertificateAuthority = ""
+		cluster.CertificateAuthorityData = caData
+	case cluster.CertificateAuthority != "":
+		// Embed a previously referenced certificate authority file
+		caData, err := os.ReadFile(cluster.CertificateAuthority)
+		if err != nil {
+			return fmt.Errorf("failed to read certificate authority: %w", err)
+		}
+		cluster.CertificateAuthority = ""
+		cluster.CertificateAuthorityData = caData
+	}
 
-func init() {
-	rootCmd.AddCommand(loginCmd)
-} 
\ No newline at end of file
+	return nil
+}
+
+// confirmServerCertificate verifies the server certificate and, when it is signed
+// by an unknown authority, asks the user whether to use an insecure connection.
+// The answer is saved in the cluster so later logins do not ask again. There is
+// no prompt when a certificate authority is configured: it must sign the server
+// certificate.
+func confirmServerCertificate(config *auth.Config, cluster *api.Cluster, reader util.InputReader) error {
+	err := auth.VerifyServerCertificate(config)
+	if err == nil {
+		return nil
+	}
+
+	var untrusted *auth.UntrustedCertificateError
+	if !errors.As(err, &untrusted) {
+		return err
+	}
+	if len(config.CAData) > 0 {
+		return fmt.Errorf("server certificate is not signed by the configured certificate authority: %w", err)
+	}
+
+	fmt.Println("The server uses a certificate signed by an unknown authority.")
+	fmt.Printf("Certificate fingerprint (SHA-256): %s\n", auth.Fingerprint(untrusted.Certificate))
+	fmt.Println("You can bypass the certificate check, but any data you send to the server could be intercepted by others.")
+
+	trust, err := util.Confirm(reader, "Use insecure connections? (y/n): ")
+	if err != nil {
+		return fmt.Errorf("failed to read answer: %w", err)
+	}
+	if !trust {
+		return fmt.Errorf("server certificate is not trusted, use --certificate-authority to provide a trusted CA")
+	}
+
+	config.InsecureSkipVerify = true
+	config.CAData = nil
+	cluster.InsecureSkipTLSVerify = true
+	cluster.CertificateAuthorityData = nil
+	return nil
+}
+
+// updateKubeconfig upserts the cluster, user and context of the project for the
+// logged in user into the loaded kubeconfig, leaving all other entries
+// untouched. Entries are named after the OpenShift scheme so several users and
+// projects per cluster can live side by side. The user gets the token or the
+// credential plugin of the credentials, replacing the previous one. It returns
+// the name of the new current context.
+func updateKubeconfig(configAccess clientcmd.ConfigAccess, rawConfig *api.Config, clusterName string, cluster *api.Cluster, credentials *api.AuthInfo, namespace string) (string, error) {
+	// Update or create cluster
+	rawConfig.Clusters[clusterName] = cluster
+
+	// Update or create auth info
+	userName := kubeconfig.UserNickname(username, clusterName)
+	authInfo, exists := rawConfig.AuthInfos[userName]
+	if !exists {
+		authInfo = api.NewAuthInfo()
+	}
+	authInfo.Token = credentials.Token
+	authInfo.Exec = credentials.Exec
+	rawConfig.AuthInfos[userName] = authInfo
+
+	// Update or create context
+	contextName := kubeconfig.ContextNickname(namespace, clusterName, username)
+	context, exists := rawConfig.Contexts[contextName]
+	if !exists {
+		context = api.NewContext()
+	}
+	context.Cluster = clusterName
+	context.AuthInfo = userName
+	context.Namespace = namespace
+	rawConfig.Contexts[contextName] = context
+
+	// Set current context
+	rawConfig.CurrentContext = contextName
+
+	// Write each stanza back to the file it was loaded from
+	if err := saveKubeconfig(configAccess, rawConfig); err != nil {
+		return "", err
+	}
+
+	return contextName, nil
+}
+
+// newRESTConfig returns the REST config for API clients of the cluster using
+// the credentials
+func newRESTConfig(cluster *api.Cluster, credentials *api.AuthInfo) (*rest.Config, error) {
+	config := api.NewConfig()
+	config.Clusters["cluster"] = cluster
+	config.AuthInfos["user"] = credentials
+	config.Contexts["context"] = &api.Context{Cluster: "cluster", AuthInfo: "user"}
+	restConfig, err := clientcmd.NewNonInteractiveClientConfig(*config, "context", &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build client configuration: %w", err)
+	}
+	return restConfig, nil
+}
+
+// currentUser validates the credentials against the cluster and returns their user
+func currentUser(ctx context.Context, cluster *api.Cluster, credentials *api.AuthInfo) (*auth.UserInfo, error) {
+	restConfig, err := newRESTConfig(cluster, credentials)
+	if err != nil {
+		return nil, err
+	}
+	return auth.WhoAmI(ctx, restConfig)
+}
+
+// selectProject prints the projects the user can access and returns the one to
+// use: the only project, the one chosen at the prompt when the standard input
+// is a terminal, or else the current project when it is still accessible. The
+// current project is kept when the projects cannot be listed.
+func selectProject(ctx context.Context, restConfig *rest.Config, current string, reader util.InputReader) (string, error) {
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	projects, err := project.List(ctx, client)
+	if err != nil {
+		if !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		return current, nil
+	}
+
+	switch len(projects) {
+	case 0:
+		fmt.Println("You don't have any projects. You can try to create a new project, by running")
+		fmt.Println()
+		fmt.Println("    skectl new-project <projectname>")
+		fmt.Println()
+		return current, nil
+	case 1:
+		fmt.Printf("You have one project on this server: %q\n", projects[0].Name)
+		return projects[0].Name, nil
+	}
+
+	accessible := func(name string) bool {
+		for _, p := range projects {
+			if p.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+	if !accessible(current) {
+		current = projects[0].Name
+	}
+
+	fmt.Println()
+	fmt.Println("You have access to the following projects and can switch between them with 'skectl project <projectname>':")
+	fmt.Println()
+	printProjects(projects, current)
+	fmt.Println()
+	if !stdinIsTerminal() {
+		return current, nil
+	}
+
+	for {
+		answer, err := reader.ReadLine(fmt.Sprintf("Select a project [%s]: ", current))
+		if err != nil {
+			return "", fmt.Errorf("failed to read project: %w", err)
+		}
+		if answer == "" {
+			return current, nil
+		}
+		if accessible(answer) {
+			return answer, nil
+		}
+		fmt.Printf("Project %q is not in the list.\n", answer)
+	}
+}
+
+// previousNamespace returns the namespace of the current context when it belongs
+// to the same cluster and user, then that of any other matching context, and
+// falls back to the default namespace
+func previousNamespace(rawConfig *api.Config, clusterName, userName string) string {
+	matches := func(context *api.Context) bool {
+		return context.Cluster == clusterName && context.AuthInfo == userName && context.Namespace != ""
+	}
+
+	if current, exists := rawConfig.Contexts[rawConfig.CurrentContext]; exists && matches(current) {
+		return current.Namespace
+	}
+
+	names := make([]string, 0, len(rawConfig.Contexts))
+	for name := range rawConfig.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if matches(rawConfig.Contexts[name]) {
+			return rawConfig.Contexts[name].Namespace
+		}
+	}
+
+	return kubeconfig.DefaultNamespace
+}
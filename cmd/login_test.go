@@ -1,12 +1,25 @@
 package cmd
 
 import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/auth"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/kubeconfig"
+	"github.com/withlin/oc-demo/pkg/testutil"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
 )
 
 func TestLoginCmd(t *testing.T) {
@@ -20,6 +33,10 @@ func TestLoginCmd(t *testing.T) {
 	os.Setenv("KUBECONFIG", kubeconfigPath)
 	defer os.Unsetenv("KUBECONFIG")
 
+	// Create test API server accepting a single token
+	apiServer := newTokenServer("test-token")
+	defer apiServer.Close()
+
 	tests := []struct {
 		name        string
 		args        []string
@@ -28,9 +45,20 @@ func TestLoginCmd(t *testing.T) {
 	}{
 		{
 			name:        "login success with token",
-			args:        []string{"--token", "test-token", "https://api.test.com:6443"},
+			args:        []string{"--insecure-skip-tls-verify", "--token", "test-token", apiServer.URL},
+			expectError: false,
+		},
+		{
+			name:        "login success with token from environment",
+			args:        []string{"--insecure-skip-tls-verify", apiServer.URL},
+			token:       "test-token",
 			expectError: false,
 		},
+		{
+			name:        "login failed with invalid token",
+			args:        []string{"--insecure-skip-tls-verify", "--token", "invalid-token", apiServer.URL},
+			expectError: true,
+		},
 		{
 			name:        "login failed without auth",
 			args:        []string{"https://api.test.com:6443"},
@@ -41,10 +69,13 @@ func TestLoginCmd(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Reset flags
-			token = ""
-			username = ""
-			password = ""
-			server = ""
+			resetLoginFlags()
+
+			// Set token environment variable
+			if tt.token != "" {
+				os.Setenv(tokenEnvVar, tt.token)
+				defer os.Unsetenv(tokenEnvVar)
+			}
 
 			// Delete existing kubeconfig file
 			_ = os.Remove(kubeconfigPath)
@@ -64,4 +95,562 @@ func TestLoginCmd(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+}
+
+// newTokenServer creates a test API server serving the current user for the given
+// token. It serves TLS since client-go only sends credentials over TLS.
+func newTokenServer(validToken string) *httptest.Server {
+	return httptest.NewTLSServer(tokenHandler(validToken))
+}
+
+// tokenHandler serves the current user for the given token
+func tokenHandler(validToken string) http.Handler {
+	return usersHandler(map[string]string{validToken: "developer"})
+}
+
+// usersHandler serves the current user for each token in the token to user map
+func usersHandler(users map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := users[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/apis/user.openshift.io/v1/users/~" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"metadata": map[string]string{"name": user},
+		})
+	})
+}
+
+func TestLoginCmdMergesKubeconfig(t *testing.T) {
+	// Create temporary directory for test
+	tmpDir, err := os.MkdirTemp("", "skectl-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	// Create test API server accepting a single token
+	apiServer := newTokenServer("test-token")
+	defer apiServer.Close()
+
+	// Create existing kubeconfig holding multiple clusters
+	kubeconfigPath := filepath.Join(tmpDir, "config")
+	existing := api.NewConfig()
+	for _, name := range []string{"cluster-a", "cluster-b"} {
+		existing.Clusters[name] = &api.Cluster{Server: "https://" + name + ".example.com:6443"}
+		existing.AuthInfos[name] = &api.AuthInfo{Token: name + "-token"}
+		existing.Contexts[name] = &api.Context{Cluster: name, AuthInfo: name, Namespace: "default"}
+	}
+	clusterName, err := kubeconfig.ClusterNickname(apiServer.URL)
+	require.NoError(t, err)
+	userName := "developer/" + clusterName
+	contextName := "myproject/" + clusterName + "/developer"
+	existing.Clusters[clusterName] = &api.Cluster{Server: apiServer.URL}
+	existing.AuthInfos[userName] = &api.AuthInfo{Token: "stale-token"}
+	existing.Contexts[contextName] = &api.Context{Cluster: clusterName, AuthInfo: userName, Namespace: "myproject"}
+	existing.CurrentContext = "cluster-a"
+	require.NoError(t, clientcmd.WriteToFile(*existing, kubeconfigPath))
+
+	os.Setenv("KUBECONFIG", kubeconfigPath)
+	defer os.Unsetenv("KUBECONFIG")
+
+	// Reset flags
+	resetLoginFlags()
+
+	cmd := NewLoginCmd()
+	cmd.SetArgs([]string{"--insecure-skip-tls-verify", "--token", "test-token", apiServer.URL})
+	require.NoError(t, cmd.Execute())
+
+	config, err := clientcmd.LoadFromFile(kubeconfigPath)
+	require.NoError(t, err)
+
+	// Other clusters are untouched
+	for _, name := range []string{"cluster-a", "cluster-b"} {
+		assert.Equal(t, "https://"+name+".example.com:6443", config.Clusters[name].Server)
+		assert.Equal(t, name+"-token", config.AuthInfos[name].Token)
+		assert.Equal(t, name, config.Contexts[name].Cluster)
+	}
+
+	// Entries for this server are updated in place
+	assert.Equal(t, "test-token", config.AuthInfos[userName].Token)
+	assert.Equal(t, "myproject", config.Contexts[contextName].Namespace)
+	assert.Equal(t, contextName, config.CurrentContext)
+}
+
+func TestLoginCmdMergesKubeconfigList(t *testing.T) {
+	// Create temporary directory for test
+	tmpDir, err := os.MkdirTemp("", "skectl-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	// Create test API server accepting a single token
+	apiServer := newTokenServer("test-token")
+	defer apiServer.Close()
+
+	// Create one kubeconfig file per existing cluster
+	var paths []string
+	for _, name := range []string{"cluster-a", "cluster-b"} {
+		config := api.NewConfig()
+		config.Clusters[name] = &api.Cluster{Server: "https://" + name + ".example.com:6443"}
+		config.AuthInfos[name] = &api.AuthInfo{Token: name + "-token"}
+		config.Contexts[name] = &api.Context{Cluster: name, AuthInfo: name}
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, clientcmd.WriteToFile(*config, path))
+		paths = append(paths, path)
+	}
+
+	os.Setenv("KUBECONFIG", strings.Join(paths, string(filepath.ListSeparator)))
+	defer os.Unsetenv("KUBECONFIG")
+
+	// Reset flags
+	resetLoginFlags()
+
+	cmd := NewLoginCmd()
+	cmd.SetArgs([]string{"--insecure-skip-tls-verify", "--token", "test-token", apiServer.URL})
+	require.NoError(t, cmd.Execute())
+
+	// Every file keeps its own cluster
+	for i, name := range []string{"cluster-a", "cluster-b"} {
+		config, err := clientcmd.LoadFromFile(paths[i])
+		require.NoError(t, err)
+		assert.Contains(t, config.Clusters, name)
+		assert.Contains(t, config.AuthInfos, name)
+		assert.Contains(t, config.Contexts, name)
+	}
+
+	// The merged view holds all clusters
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	merged, err := rules.Load()
+	require.NoError(t, err)
+	clusterName, err := kubeconfig.ClusterNickname(apiServer.URL)
+	require.NoError(t, err)
+	assert.Len(t, merged.Clusters, 3)
+	assert.Equal(t, "default/"+clusterName+"/developer", merged.CurrentContext)
+}
+
+// resetLoginFlags resets the login flags shared between command instances
+func resetLoginFlags() {
+	token = ""
+	username = ""
+	password = ""
+	server = ""
+	insecureSkipTLSVerify = false
+	certificateAuthority = ""
+	execCommand = ""
+	execArgs = nil
+	execEnv = nil
+	execAPIVersion = auth.DefaultExecAPIVersion
+}
+
+// execPlugin prints the token of $PLUGIN_TOKEN when called with --role=dev
+const execPlugin = `#!/bin/sh
+[ "$1" = "--role=dev" ] || exit 1
+printf '{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"%s"}}\n' "$PLUGIN_TOKEN"
+`
+
+// setupExecPluginServer starts a fake API server only accepting the token of
+// the credential plugin, and returns it with the path of the plugin
+func setupExecPluginServer(t *testing.T) (*testutil.FakeAPIServer, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake credential plugin is a shell script")
+	}
+
+	plugin := filepath.Join(t.TempDir(), "credential-plugin")
+	require.NoError(t, os.WriteFile(plugin, []byte(execPlugin), 0700))
+
+	server := setupFakeAPIServer(t, "project-a")
+	authorized := func(handler http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer exec-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			handler(w, r)
+		}
+	}
+	server.Handle(http.MethodGet, "/apis/user.openshift.io/v1/users/~", authorized(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"metadata": map[string]string{"name": "developer"},
+		})
+	}))
+	server.Handle(http.MethodGet, "/apis/project.openshift.io/v1/projects", authorized(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"apiVersion": "project.openshift.io/v1",
+			"kind":       "ProjectList",
+			"items":      []interface{}{map[string]interface{}{"metadata": map[string]string{"name": "project-a"}}},
+		})
+	}))
+	return server, plugin
+}
+
+func TestLoginCmdExecPlugin(t *testing.T) {
+	server, plugin := setupExecPluginServer(t)
+	defer func() { *configFlags = *client.NewConfigFlags() }()
+
+	oldTerminal := stdinIsTerminal
+	defer func() { stdinIsTerminal = oldTerminal }()
+	stdinIsTerminal = func() bool { return false }
+
+	capture := testutil.NewCaptureOutput()
+	require.NoError(t, capture.Start(), "Failed to start output capture")
+	defer capture.Stop()
+
+	resetLoginFlags()
+	cmd := NewLoginCmd()
+	cmd.SetArgs([]string{"--insecure-skip-tls-verify", "--exec-command", plugin, "--exec-arg=--role=dev", "--exec-env", "PLUGIN_TOKEN=exec-token", server.URL})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, capture.Stdout(), "Successfully logged in as developer")
+
+	// The user runs the plugin instead of holding a token
+	config, err := clientcmd.LoadFromFile(os.Getenv("KUBECONFIG"))
+	require.NoError(t, err)
+	clusterName, err := kubeconfig.ClusterNickname(server.URL)
+	require.NoError(t, err)
+	authInfo := config.AuthInfos["developer/"+clusterName]
+	require.NotNil(t, authInfo)
+	assert.Empty(t, authInfo.Token)
+	require.NotNil(t, authInfo.Exec)
+	assert.Equal(t, plugin, authInfo.Exec.Command)
+	assert.Equal(t, []string{"--role=dev"}, authInfo.Exec.Args)
+	assert.Equal(t, []api.ExecEnvVar{{Name: "PLUGIN_TOKEN", Value: "exec-token"}}, authInfo.Exec.Env)
+	assert.Equal(t, "client.authentication.k8s.io/v1", authInfo.Exec.APIVersion)
+	assert.Equal(t, api.IfAvailableExecInteractiveMode, authInfo.Exec.InteractiveMode)
+
+	// Both the authenticator and the API clients get their token from the plugin
+	for _, tt := range []struct {
+		args     []string
+		expected string
+	}{
+		{args: []string{"whoami"}, expected: "developer\n"},
+		{args: []string{"whoami", "--show-token"}, expected: "exec-token\n"},
+		{args: []string{"projects", "-q"}, expected: "project-a\n"},
+	} {
+		capture := testutil.NewCaptureOutput()
+		require.NoError(t, capture.Start(), "Failed to start output capture")
+		*configFlags = *client.NewConfigFlags()
+		cmd := NewRootCmd()
+		cmd.SetArgs(tt.args)
+		err := cmd.Execute()
+		output := capture.Stdout()
+		capture.Stop()
+		require.NoError(t, err, "skectl %v", tt.args)
+		assert.Equal(t, tt.expected, output, "skectl %v", tt.args)
+	}
+
+	// Logging out removes the plugin from the user
+	*configFlags = *client.NewConfigFlags()
+	cmd = NewLogoutCmd()
+	cmd.SetArgs(nil)
+	require.NoError(t, cmd.Execute())
+	config, err = clientcmd.LoadFromFile(os.Getenv("KUBECONFIG"))
+	require.NoError(t, err)
+	assert.Nil(t, config.AuthInfos["developer/"+clusterName].Exec)
+}
+
+func TestLoginCmdExecPluginErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		withPlugin  bool
+		errContains string
+	}{
+		{
+			name:        "plugin combined with a token",
+			args:        []string{"--token", "sha256~token"},
+			withPlugin:  true,
+			errContains: "cannot be combined",
+		},
+		{
+			name:        "plugin arguments without a plugin",
+			args:        []string{"--exec-arg", "get-token"},
+			errContains: "require --exec-command",
+		},
+		{
+			name:        "invalid plugin environment variable",
+			args:        []string{"--exec-env", "PLUGIN_TOKEN"},
+			withPlugin:  true,
+			errContains: "invalid environment variable",
+		},
+		{
+			name:        "failing plugin",
+			args:        []string{"--exec-arg=--role=admin"},
+			withPlugin:  true,
+			errContains: "credential plugin",
+		},
+		{
+			name:        "token rejected by the server",
+			args:        []string{"--exec-arg=--role=dev", "--exec-env", "PLUGIN_TOKEN=other-token"},
+			withPlugin:  true,
+			errContains: "credential plugin token validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, plugin := setupExecPluginServer(t)
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			args := append([]string{"--insecure-skip-tls-verify"}, tt.args...)
+			if tt.withPlugin {
+				args = append(args, "--exec-command", plugin)
+			}
+			resetLoginFlags()
+			cmd := NewLoginCmd()
+			cmd.SetArgs(append(args, server.URL))
+			err := cmd.Execute()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestLoginCmdTLS(t *testing.T) {
+	// Create temporary directory for test
+	tmpDir, err := os.MkdirTemp("", "skectl-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	// Set KUBECONFIG environment variable
+	kubeconfigPath := filepath.Join(tmpDir, "config")
+	os.Setenv("KUBECONFIG", kubeconfigPath)
+	defer os.Unsetenv("KUBECONFIG")
+
+	// Create TLS test API server with a self-signed certificate
+	apiServer := httptest.NewTLSServer(tokenHandler("test-token"))
+	defer apiServer.Close()
+
+	// Write the server certificate as a certificate authority file
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: apiServer.Certificate().Raw})
+	caPath := filepath.Join(tmpDir, "ca.crt")
+	require.NoError(t, os.WriteFile(caPath, caData, 0600))
+
+	// Write a certificate authority which did not sign the server certificate
+	otherCAData, _ := newSelfSignedCertificate(t, "other-ca")
+	otherCAPath := filepath.Join(tmpDir, "other-ca.crt")
+	require.NoError(t, os.WriteFile(otherCAPath, otherCAData, 0600))
+
+	tests := []struct {
+		name         string
+		args         []string
+		answer       string
+		keepConfig   bool
+		expectError  bool
+		wantInsecure bool
+		wantCAData   []byte
+	}{
+		{
+			name:        "untrusted certificate rejected",
+			args:        []string{"--token", "test-token", apiServer.URL},
+			answer:      "n\n",
+			expectError: true,
+		},
+		{
+			name:         "untrusted certificate accepted",
+			args:         []string{"--token", "test-token", apiServer.URL},
+			answer:       "y\n",
+			wantInsecure: true,
+		},
+		{
+			name:         "saved answer is reused",
+			args:         []string{"--token", "test-token", apiServer.URL},
+			keepConfig:   true,
+			wantInsecure: true,
+		},
+		{
+			name:         "insecure flag skips verification",
+			args:         []string{"--insecure-skip-tls-verify", "--token", "test-token", apiServer.URL},
+			wantInsecure: true,
+		},
+		{
+			name:       "certificate authority is embedded",
+			args:       []string{"--certificate-authority", caPath, "--token", "test-token", apiServer.URL},
+			wantCAData: caData,
+		},
+		{
+			name:        "certificate authority not signing the server certificate",
+			args:        []string{"--certificate-authority", otherCAPath, "--token", "test-token", apiServer.URL},
+			answer:      "y\n",
+			expectError: true,
+		},
+		{
+			name:        "conflicting TLS flags",
+			args:        []string{"--insecure-skip-tls-verify", "--certificate-authority", caPath, "--token", "test-token", apiServer.URL},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Reset flags
+			resetLoginFlags()
+
+			// Delete existing kubeconfig file
+			if !tt.keepConfig {
+				_ = os.Remove(kubeconfigPath)
+			}
+
+			// Provide the answer to the trust prompt on stdin
+			oldStdin := os.Stdin
+			defer func() { os.Stdin = oldStdin }()
+			r, w, err := os.Pipe()
+			require.NoError(t, err)
+			os.Stdin = r
+			_, err = w.WriteString(tt.answer)
+			require.NoError(t, err)
+			w.Close()
+
+			// Execute command
+			cmd := NewLoginCmd()
+			cmd.SetArgs(tt.args)
+			err = cmd.Execute()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			config, err := clientcmd.LoadFromFile(kubeconfigPath)
+			require.NoError(t, err)
+			clusterName, err := kubeconfig.ClusterNickname(apiServer.URL)
+			require.NoError(t, err)
+			cluster := config.Clusters[clusterName]
+			require.NotNil(t, cluster)
+			assert.Equal(t, tt.wantInsecure, cluster.InsecureSkipTLSVerify)
+			assert.Equal(t, tt.wantCAData, cluster.CertificateAuthorityData)
+		})
+	}
+}
+
+func TestLoginCmdMultipleUsers(t *testing.T) {
+	// Create temporary directory for test
+	tmpDir, err := os.MkdirTemp("", "skectl-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	// Set KUBECONFIG environment variable
+	kubeconfigPath := filepath.Join(tmpDir, "config")
+	os.Setenv("KUBECONFIG", kubeconfigPath)
+	defer os.Unsetenv("KUBECONFIG")
+
+	// Create test API server knowing two users
+	apiServer := httptest.NewTLSServer(usersHandler(map[string]string{
+		"developer-token": "developer",
+		"admin-token":     "admin",
+	}))
+	defer apiServer.Close()
+
+	for _, userToken := range []string{"developer-token", "admin-token"} {
+		resetLoginFlags()
+		cmd := NewLoginCmd()
+		cmd.SetArgs([]string{"--insecure-skip-tls-verify", "--token", userToken, apiServer.URL})
+		require.NoError(t, cmd.Execute())
+	}
+
+	config, err := clientcmd.LoadFromFile(kubeconfigPath)
+	require.NoError(t, err)
+
+	clusterName, err := kubeconfig.ClusterNickname(apiServer.URL)
+	require.NoError(t, err)
+
+	// Both identities share the cluster but keep their own user and context
+	assert.Len(t, config.Clusters, 1)
+	assert.Equal(t, "developer-token", config.AuthInfos["developer/"+clusterName].Token)
+	assert.Equal(t, "admin-token", config.AuthInfos["admin/"+clusterName].Token)
+	assert.Equal(t, "developer/"+clusterName, config.Contexts["default/"+clusterName+"/developer"].AuthInfo)
+	assert.Equal(t, "admin/"+clusterName, config.Contexts["default/"+clusterName+"/admin"].AuthInfo)
+	assert.Equal(t, "default/"+clusterName+"/admin", config.CurrentContext)
+}
+
+func TestLoginCmdSelectsProject(t *testing.T) {
+	tests := []struct {
+		name        string
+		terminal    bool
+		answer      string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "project chosen at the prompt",
+			terminal: true,
+			answer:   "project-b\n",
+			expected: "project-b",
+		},
+		{
+			name:     "empty answer keeps the first accessible project",
+			terminal: true,
+			answer:   "\n",
+			expected: "project-a",
+		},
+		{
+			name:     "no prompt without a terminal",
+			expected: "project-a",
+		},
+		{
+			name:        "unanswered prompt",
+			terminal:    true,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupFakeAPIServer(t, "project-a")
+			addProjects(server, true)
+			server.Handle(http.MethodGet, "/apis/user.openshift.io/v1/users/~", func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"metadata": map[string]string{"name": "developer"},
+				})
+			})
+
+			oldTerminal := stdinIsTerminal
+			defer func() { stdinIsTerminal = oldTerminal }()
+			stdinIsTerminal = func() bool { return tt.terminal }
+
+			// Provide the answer to the project prompt on stdin
+			oldStdin := os.Stdin
+			defer func() { os.Stdin = oldStdin }()
+			r, w, err := os.Pipe()
+			require.NoError(t, err)
+			os.Stdin = r
+			_, err = w.WriteString(tt.answer)
+			require.NoError(t, err)
+			w.Close()
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			resetLoginFlags()
+			cmd := NewLoginCmd()
+			cmd.SetArgs([]string{"--insecure-skip-tls-verify", "--token", testutil.FakeToken, server.URL})
+			err = cmd.Execute()
+			output := capture.Stdout()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, output, "  * project-a - Project A\n    project-b\n")
+			assert.Contains(t, output, fmt.Sprintf("Using project %q", tt.expected))
+			assert.Equal(t, tt.terminal, strings.Contains(output, "Select a project [project-a]: "))
+
+			config, err := clientcmd.LoadFromFile(os.Getenv("KUBECONFIG"))
+			require.NoError(t, err)
+			clusterName, err := kubeconfig.ClusterNickname(server.URL)
+			require.NoError(t, err)
+			contextName := tt.expected + "/" + clusterName + "/developer"
+			assert.Equal(t, contextName, config.CurrentContext)
+			assert.Equal(t, tt.expected, config.Contexts[contextName].Namespace)
+		})
+	}
+}
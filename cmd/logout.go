@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/auth"
+)
+
+// NewLogoutCmd creates a new logout command
+func NewLogoutCmd() *cobra.Command {
+	var revokePath string
+
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "End the current server session",
+		Long: `End the current server session.
+
+The token of the current context is revoked on the server and removed from the
+kubeconfig. The credential plugin of a user logged in with --exec-command is
+removed too, the credentials it issues are left to the plugin. The cluster and
+context entries are kept so you can log in again.`,
+		Example: `  # Log out of the current session
+  skectl logout
+
+  # Log out using a custom revocation endpoint of the JSON auth backend
+  skectl logout --revoke-path=/api/v1/logout`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Load kubeconfig
+			configAccess, rawConfig, err := loadKubeconfig()
+			if err != nil {
+				return err
+			}
+
+			// Find the token of the current context
+			context, cluster, authInfo, err := currentContext(rawConfig)
+			if err != nil {
+				return err
+			}
+			if authInfo.Token == "" && authInfo.Exec == nil {
+				return fmt.Errorf("you are not logged in")
+			}
+
+			// Revoke the token on the server, a failure must not keep the token locally
+			if authInfo.Token != "" {
+				config := newAuthConfig(cluster)
+				config.RevokePath = revokePath
+				if err := revokeToken(config, authInfo.Token); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to revoke token on the server: %v\n", err)
+				}
+			}
+
+			// Remove the credentials but keep the cluster and context entries
+			authInfo.Token = ""
+			authInfo.Exec = nil
+			if err := saveKubeconfig(configAccess, rawConfig); err != nil {
+				return err
+			}
+
+			user := strings.TrimSuffix(context.AuthInfo, "/"+context.Cluster)
+			fmt.Printf("Logged %q out on %q\n", user, cluster.Server)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&revokePath, "revoke-path", auth.DefaultConfig().RevokePath, "Path of the token revocation endpoint of the JSON auth backend")
+
+	return cmd
+}
+
+// revokeToken revokes the token using the authenticator detected for the server
+func revokeToken(config *auth.Config, token string) error {
+	authenticator, err := auth.DetectAuthenticator(config)
+	if err != nil {
+		return err
+	}
+
+	revoker, ok := authenticator.(auth.Revoker)
+	if !ok {
+		return auth.ErrRevocationNotSupported
+	}
+
+	return revoker.Revoke(token)
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/auth"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestLogoutCmd(t *testing.T) {
+	// Create temporary directory for test
+	tmpDir, err := os.MkdirTemp("", "skectl-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	// Set KUBECONFIG environment variable
+	kubeconfigPath := filepath.Join(tmpDir, "config")
+	os.Setenv("KUBECONFIG", kubeconfigPath)
+	defer os.Unsetenv("KUBECONFIG")
+
+	tests := []struct {
+		name        string
+		openshift   bool
+		token       string
+		wantRequest string
+		expectError bool
+	}{
+		{
+			name:        "revoke OAuth access token",
+			openshift:   true,
+			token:       "sha256~secret",
+			wantRequest: http.MethodDelete + " " + auth.OAuthAccessTokensPath + "/",
+		},
+		{
+			name:        "revoke token on JSON backend",
+			openshift:   false,
+			token:       "json-token",
+			wantRequest: http.MethodPost + " /auth/revoke",
+		},
+		{
+			name:        "not logged in",
+			token:       "",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			var apiServer *httptest.Server
+			apiServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == auth.OAuthMetadataPath {
+					if !tt.openshift {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					json.NewEncoder(w).Encode(auth.OAuthMetadata{
+						Issuer:                apiServer.URL,
+						AuthorizationEndpoint: apiServer.URL + "/oauth/authorize",
+					})
+					return
+				}
+				assert.Equal(t, "Bearer "+tt.token, r.Header.Get("Authorization"))
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer apiServer.Close()
+
+			// Create kubeconfig with a logged in context
+			config := api.NewConfig()
+			config.Clusters["cluster"] = &api.Cluster{Server: apiServer.URL}
+			config.AuthInfos["developer/cluster"] = &api.AuthInfo{Token: tt.token}
+			config.Contexts["default/cluster/developer"] = &api.Context{Cluster: "cluster", AuthInfo: "developer/cluster", Namespace: "default"}
+			config.CurrentContext = "default/cluster/developer"
+			require.NoError(t, clientcmd.WriteToFile(*config, kubeconfigPath))
+
+			// Execute command
+			cmd := NewLogoutCmd()
+			cmd.SetArgs([]string{})
+			err := cmd.Execute()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			// Verify the token was revoked on the server
+			require.Len(t, requests, 1)
+			assert.True(t, strings.HasPrefix(requests[0], tt.wantRequest), "unexpected request %s", requests[0])
+
+			// Verify the token was removed but the entries were kept
+			newConfig, err := clientcmd.LoadFromFile(kubeconfigPath)
+			require.NoError(t, err)
+			assert.Empty(t, newConfig.AuthInfos["developer/cluster"].Token)
+			assert.Contains(t, newConfig.Clusters, "cluster")
+			assert.Contains(t, newConfig.Contexts, "default/cluster/developer")
+			assert.Equal(t, "default/cluster/developer", newConfig.CurrentContext)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/logs"
+	"github.com/withlin/oc-demo/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// logsOptions holds the flags of the logs command
+type logsOptions struct {
+	follow         bool
+	container      string
+	allContainers  bool
+	previous       bool
+	since          time.Duration
+	tail           int64
+	timestamps     bool
+	selector       string
+	maxLogRequests int
+	prefix         bool
+}
+
+// NewLogsCmd creates a new logs command
+func NewLogsCmd() *cobra.Command {
+	o := &logsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "logs (<pod> | <type>/<name> | -l <selector>) [container]",
+		Short: "Print the logs for a container in a pod",
+		Long: `Print the logs for a container in a pod.
+
+A workload such as deploy/web or svc/web selects the pods matching its
+selector. When several pods or containers are read, their logs are streamed at
+once and every line starts with [pod/container]. The number of streams open at
+the same time is capped by --max-log-requests.
+
+Without -c, the container named by the kubectl.kubernetes.io/default-container
+annotation, or else the first container, is read.`,
+		Example: `  # Print the logs of a pod with a single container
+  skectl logs web-1
+
+  # Follow the logs of the nginx container of a pod
+  skectl logs pod/web-1 -c nginx -f
+
+  # Print the last 20 lines of every container of the pods of a deployment
+  skectl logs deploy/web --all-containers --tail=20
+
+  # Follow the logs of the last hour of the pods labeled app=web with timestamps
+  skectl logs -l app=web -f --since=1h --timestamps
+
+  # Print the logs of the previous instance of a restarted container
+  skectl logs web-1 --previous`,
+		Args: cobra.RangeArgs(0, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run(cmd.Context(), args)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.follow, "follow", "f", false, "Specify if the logs should be streamed")
+	cmd.Flags().StringVarP(&o.container, "container", "c", "", "Print the logs of this container")
+	cmd.Flags().BoolVar(&o.allContainers, "all-containers", false, "Get all containers' logs in the pods")
+	cmd.Flags().BoolVarP(&o.previous, "previous", "p", false, "Print the logs for the previous instance of the container")
+	cmd.Flags().DurationVar(&o.since, "since", 0, "Only return logs newer than a relative duration like 5s, 2m, or 3h")
+	cmd.Flags().Int64Var(&o.tail, "tail", -1, "Lines of recent log file to display, -1 to show all")
+	cmd.Flags().BoolVar(&o.timestamps, "timestamps", false, "Include timestamps on each line in the log output")
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "Selector (label query) to filter on, supports '=', '==', and '!='")
+	cmd.Flags().IntVar(&o.maxLogRequests, "max-log-requests", logs.DefaultMaxStreams, "Maximum number of concurrent logs to follow")
+	cmd.Flags().BoolVar(&o.prefix, "prefix", false, "Prefix each log line with the pod and container name, even for a single container")
+
+	return cmd
+}
+
+// run reads the logs of the selected containers
+func (o *logsOptions) run(ctx context.Context, args []string) error {
+	if o.selector != "" && len(args) > 0 {
+		return fmt.Errorf("only a selector (-l) or a pod name is allowed")
+	}
+	if o.selector == "" && len(args) == 0 {
+		return fmt.Errorf("a pod name, a type/name pair or a selector (-l) is required")
+	}
+	if len(args) == 2 {
+		if o.container != "" {
+			return fmt.Errorf("only one of -c or an inline container name is allowed")
+		}
+		o.container = args[1]
+	}
+	if o.container != "" && o.allContainers {
+		return fmt.Errorf("--all-containers cannot be combined with a container name")
+	}
+	if o.since < 0 {
+		return fmt.Errorf("--since must be greater than 0")
+	}
+
+	namespace, _, err := configFlags.ToNamespace()
+	if err != nil {
+		return err
+	}
+	clientSet, err := configFlags.ToClientSet()
+	if err != nil {
+		return err
+	}
+
+	var pods []corev1.Pod
+	if o.selector != "" {
+		list, err := clientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: o.selector})
+		if err != nil {
+			return fmt.Errorf("failed to list pods: %w", err)
+		}
+		if len(list.Items) == 0 {
+			fmt.Fprintf(os.Stderr, "No resources found in %s namespace.\n", namespace)
+			return nil
+		}
+		pods = list.Items
+	} else if pods, err = podsFor(ctx, namespace, args[0]); err != nil {
+		return err
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	sources, err := logs.Sources(pods, o.container, o.allContainers, func(pod, container string, names []string) {
+		fmt.Fprintf(os.Stderr, "Defaulted container %q out of: %s\n", container, strings.Join(names, ", "))
+	})
+	if err != nil {
+		return err
+	}
+
+	options := corev1.PodLogOptions{
+		Follow:     o.follow,
+		Previous:   o.previous,
+		Timestamps: o.timestamps,
+	}
+	if o.since > 0 {
+		seconds := int64((o.since + time.Second - 1) / time.Second)
+		options.SinceSeconds = &seconds
+	}
+	if o.tail >= 0 {
+		options.TailLines = &o.tail
+	}
+	streamer := &logs.Streamer{
+		Client:     clientSet,
+		Namespace:  namespace,
+		Options:    options,
+		MaxStreams: o.maxLogRequests,
+		Prefix:     o.prefix || len(sources) > 1,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	return streamer.Run(ctx, sources, os.Stdout)
+}
+
+// podsFor returns the pod named by the argument, or the pods selected by the
+// workload or service of a <type>/<name> argument
+func podsFor(ctx context.Context, namespace, arg string) ([]corev1.Pod, error) {
+	clientSet, err := configFlags.ToClientSet()
+	if err != nil {
+		return nil, err
+	}
+	resourceType, name, found := strings.Cut(arg, "/")
+	if !found {
+		resourceType, name = "pods", arg
+	}
+	if name == "" {
+		return nil, fmt.Errorf("arguments in resource/name form must have a single resource and name, got %q", arg)
+	}
+
+	mapper, err := configFlags.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := resource.Mapping(mapper, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.GroupVersionKind.GroupKind().String() == "Pod" {
+		pod, err := clientSet.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod %q: %w", name, err)
+		}
+		return []corev1.Pod{*pod}, nil
+	}
+
+	dynamicClient, err := configFlags.ToDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	obj, err := resource.ClientFor(dynamicClient, mapping, namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %q: %w", mapping.Resource.Resource, name, err)
+	}
+	selector, err := resource.PodSelector(obj)
+	if err != nil {
+		return nil, err
+	}
+	list, err := clientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("no pods found for %s %q", resource.QualifiedKind(mapping), name)
+	}
+	return list.Items, nil
+}
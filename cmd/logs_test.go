@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/testutil"
+)
+
+// logsTestObjects are the pods and the deployment of the logs tests, the web
+// pods are running
+var logsTestObjects = []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web-1
+  namespace: project-a
+  labels:
+    app: web
+spec:
+  containers:
+  - name: nginx
+  - name: sidecar
+status:
+  phase: Running
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web-2
+  namespace: project-a
+  labels:
+    app: web
+spec:
+  containers:
+  - name: nginx
+  - name: sidecar
+status:
+  phase: Running
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: db-1
+  namespace: project-a
+  labels:
+    app: db
+spec:
+  containers:
+  - name: postgres
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: project-a
+spec:
+  selector:
+    matchLabels:
+      app: web
+`}
+
+func TestLogsCmd(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		expected    []string
+		unexpected  []string
+		wantRequest string
+		expectError bool
+	}{
+		{
+			name:       "single container pod",
+			args:       []string{"db-1"},
+			expected:   []string{"db-1 postgres line\n"},
+			unexpected: []string{"["},
+		},
+		{
+			name:     "default container",
+			args:     []string{"pod/web-1"},
+			expected: []string{"web-1 nginx line\n"},
+		},
+		{
+			name:        "inline container with options",
+			args:        []string{"web-1", "sidecar", "--tail=5", "--since=90s", "--timestamps", "--previous"},
+			expected:    []string{"web-1 sidecar line\n"},
+			wantRequest: "GET /api/v1/namespaces/project-a/pods/web-1/log?container=sidecar&previous=true&sinceSeconds=90&tailLines=5&timestamps=true",
+		},
+		{
+			name:     "deployment pods",
+			args:     []string{"deploy/web", "-c", "nginx"},
+			expected: []string{"[web-1/nginx] web-1 nginx line\n", "[web-2/nginx] web-2 nginx line\n"},
+		},
+		{
+			name: "selector with all containers",
+			args: []string{"-l", "app=web", "--all-containers", "-f"},
+			expected: []string{
+				"[web-1/nginx] web-1 nginx line\n", "[web-1/sidecar] web-1 sidecar line\n",
+				"[web-2/nginx] web-2 nginx line\n", "[web-2/sidecar] web-2 sidecar line\n",
+			},
+			unexpected:  []string{"postgres"},
+			wantRequest: "GET /api/v1/namespaces/project-a/pods/web-2/log?container=sidecar&follow=true",
+		},
+		{
+			name:        "too many streams to follow",
+			args:        []string{"-l", "app=web", "--all-containers", "-f", "--max-log-requests=3"},
+			expectError: true,
+		},
+		{
+			name:        "unknown container",
+			args:        []string{"web-1", "-c", "db"},
+			expectError: true,
+		},
+		{
+			name:        "selector and name",
+			args:        []string{"web-1", "-l", "app=web"},
+			expectError: true,
+		},
+		{
+			name:        "missing pod",
+			args:        []string{"web-3"},
+			expectError: true,
+		},
+		{
+			name:        "no arguments",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupFakeAPIServer(t, "project-a")
+			for _, manifest := range logsTestObjects {
+				server.AddYAML(manifest)
+			}
+			for _, pod := range []string{"web-1", "web-2", "db-1"} {
+				pod := pod
+				server.Handle(http.MethodGet, "/api/v1/namespaces/project-a/pods/"+pod+"/log", func(w http.ResponseWriter, r *http.Request) {
+					fmt.Fprintf(w, "%s %s line\n", pod, r.URL.Query().Get("container"))
+				})
+			}
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(append([]string{"logs"}, tt.args...))
+			err := cmd.Execute()
+			output := capture.Stdout()
+			*configFlags = *client.NewConfigFlags()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, output, expected)
+			}
+			for _, unexpected := range tt.unexpected {
+				assert.NotContains(t, output, unexpected)
+			}
+			assert.Equal(t, len(tt.expected), strings.Count(output, "\n"))
+			if tt.wantRequest != "" {
+				assert.Contains(t, server.Requests(), tt.wantRequest)
+			}
+		})
+	}
+}
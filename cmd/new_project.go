@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/auth"
+	"github.com/withlin/oc-demo/pkg/project"
+)
+
+// NewNewProjectCmd creates a new new-project command
+func NewNewProjectCmd() *cobra.Command {
+	request := project.Request{}
+	var skipConfigWrite bool
+
+	cmd := &cobra.Command{
+		Use:   "new-project <name>",
+		Short: "Request a new project",
+		Long: `Request a new project and switch to it.
+
+On OpenShift a ProjectRequest is created, which makes you an admin of the new
+project. On Kubernetes a namespace is created instead and the admin cluster
+role is bound to you in it. The current context then uses the new project, the
+same way as 'skectl project'.`,
+		Example: `  # Create a new project with a display name and a description
+  skectl new-project web-team-dev --display-name="Web Team Development" --description="Development project for the web team"
+
+  # Create a new project without switching to it
+  skectl new-project web-team-dev --skip-config-write`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			request.Name = args[0]
+
+			restConfig, err := toRESTConfig()
+			if err != nil {
+				return err
+			}
+			dynamicClient, err := configFlags.ToDynamicClient()
+			if err != nil {
+				return err
+			}
+
+			// The namespace of Kubernetes servers is bound to the current user
+			admin := func() (string, error) {
+				user, err := auth.WhoAmI(cmd.Context(), restConfig)
+				if err != nil {
+					return "", fmt.Errorf("failed to get current user: %w", err)
+				}
+				return user.Username, nil
+			}
+			if err := project.Create(cmd.Context(), dynamicClient, request, admin); err != nil {
+				return err
+			}
+			if skipConfigWrite {
+				fmt.Printf("Project %q created on server %q.\n", request.Name, restConfig.Host)
+				return nil
+			}
+
+			if err := useProject(request.Name); err != nil {
+				return err
+			}
+
+			fmt.Printf("Now using project %q on server %q.\n", request.Name, restConfig.Host)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&request.DisplayName, "display-name", "", "Project display name")
+	cmd.Flags().StringVar(&request.Description, "description", "", "Project description")
+	cmd.Flags().BoolVar(&skipConfigWrite, "skip-config-write", false, "If true, the project will not be set as the project of the current context")
+
+	return cmd
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestNewProjectCmd(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		openshift       bool
+		anonymous       bool
+		rejectBinding   bool
+		expectedContext string
+		expectedAdmin   string
+		expectError     bool
+	}{
+		{
+			name:            "project request on openshift",
+			args:            []string{"new-project", "web-dev", "--display-name=Web Dev", "--description=Web development"},
+			openshift:       true,
+			expectedContext: "web-dev/cluster/developer",
+		},
+		{
+			name:            "namespace and role binding on kubernetes",
+			args:            []string{"new-project", "web-dev", "--display-name=Web Dev", "--description=Web development"},
+			expectedContext: "web-dev/cluster/developer",
+			expectedAdmin:   "developer",
+		},
+		{
+			name:            "role binding of the impersonated user",
+			args:            []string{"new-project", "web-dev", "--display-name=Web Dev", "--description=Web development", "--as", "bob"},
+			expectedContext: "web-dev/cluster/developer",
+			expectedAdmin:   "bob",
+		},
+		{
+			name:            "skip the context switch",
+			args:            []string{"new-project", "web-dev", "--skip-config-write"},
+			openshift:       true,
+			expectedContext: "project-a/cluster/developer",
+		},
+		{
+			name:        "no user to bind on kubernetes",
+			args:        []string{"new-project", "web-dev"},
+			anonymous:   true,
+			expectError: true,
+		},
+		{
+			name:          "role binding rejected on kubernetes",
+			args:          []string{"new-project", "web-dev"},
+			rejectBinding: true,
+			expectError:   true,
+		},
+		{
+			name:        "existing namespace",
+			args:        []string{"new-project", "project-a"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupFakeAPIServer(t, "project-a")
+			addProjects(server, tt.openshift)
+			if tt.openshift {
+				server.AddResource("project.openshift.io/v1", metav1.APIResource{Name: "projectrequests", SingularName: "projectrequest", Kind: "ProjectRequest"})
+			}
+			server.AddResource("rbac.authorization.k8s.io/v1", metav1.APIResource{Name: "rolebindings", SingularName: "rolebinding", Kind: "RoleBinding", Namespaced: true})
+			server.Handle(http.MethodGet, "/apis/user.openshift.io/v1/users/~", func(w http.ResponseWriter, r *http.Request) {
+				user := "developer"
+				if impersonated := r.Header.Get("Impersonate-User"); impersonated != "" {
+					user = impersonated
+				}
+				if tt.anonymous {
+					user = ""
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"metadata": map[string]string{"name": user},
+				})
+			})
+
+			if tt.rejectBinding {
+				server.Handle(http.MethodPost, "/apis/rbac.authorization.k8s.io/v1/namespaces/web-dev/rolebindings", func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusForbidden)
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "Status",
+						"status":     "Failure",
+						"reason":     "Forbidden",
+						"code":       http.StatusForbidden,
+					})
+				})
+			}
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			*configFlags = *client.NewConfigFlags()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				if tt.rejectBinding {
+					assert.Contains(t, err.Error(), "failed to bind the admin role")
+				}
+				if tt.anonymous || tt.rejectBinding {
+					assert.Nil(t, server.Object("v1", "Namespace", "", "web-dev"))
+				}
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.openshift {
+				request := server.Object("project.openshift.io/v1", "ProjectRequest", "", "web-dev")
+				require.NotNil(t, request)
+				assert.Nil(t, server.Object("v1", "Namespace", "", "web-dev"))
+				if len(tt.args) > 3 {
+					assert.Equal(t, "Web Dev", request.Object["displayName"])
+					assert.Equal(t, "Web development", request.Object["description"])
+				}
+			} else {
+				namespace := server.Object("v1", "Namespace", "", "web-dev")
+				require.NotNil(t, namespace)
+				assert.Equal(t, "Web Dev", namespace.GetAnnotations()["openshift.io/display-name"])
+				assert.Equal(t, "Web development", namespace.GetAnnotations()["openshift.io/description"])
+
+				binding := server.Object("rbac.authorization.k8s.io/v1", "RoleBinding", "web-dev", "admin")
+				require.NotNil(t, binding)
+				role, _, _ := unstructured.NestedString(binding.Object, "roleRef", "name")
+				assert.Equal(t, "admin", role)
+				subjects, _, _ := unstructured.NestedSlice(binding.Object, "subjects")
+				require.Len(t, subjects, 1)
+				assert.Equal(t, tt.expectedAdmin, subjects[0].(map[string]interface{})["name"])
+			}
+
+			config, err := clientcmd.LoadFromFile(os.Getenv("KUBECONFIG"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedContext, config.CurrentContext)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/portforward"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// newDialer creates the connection dialer of a port forward, replaced in tests
+var newDialer = func(config *rest.Config, url *url.URL) (httpstream.Dialer, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, err
+	}
+	return spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", url), nil
+}
+
+// portForwardOptions holds the flags of the port-forward command
+type portForwardOptions struct {
+	addresses []string
+}
+
+// NewPortForwardCmd creates a new port-forward command
+func NewPortForwardCmd() *cobra.Command {
+	o := &portForwardOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "port-forward (<pod> | <type>/<name>) [LOCAL_PORT:]REMOTE_PORT [...[LOCAL_PORT_N:]REMOTE_PORT_N]",
+		Short: "Forward one or more local ports to a pod",
+		Long: `Forward one or more local ports to a pod.
+
+A workload such as deploy/web forwards to one of its running pods. A service
+such as svc/web forwards to a running pod matching its selector, and the remote
+ports are ports of the service, sent to their target port in the pod.
+
+When the pod is deleted or restarted, or the connection to it is lost, the
+forward reconnects to a running pod on the same local ports. It stops on an
+interrupt.`,
+		Example: `  # Listen on ports 5000 and 6000 locally, forwarding to the same ports in the pod
+  skectl port-forward web-1 5000 6000
+
+  # Listen on port 8080 locally, forwarding to port 80 of a pod of the deployment
+  skectl port-forward deploy/web 8080:80
+
+  # Listen on a random local port, forwarding to the postgres port of the service
+  skectl port-forward svc/db :5432
+
+  # Listen on port 8888 on every address, forwarding to port 5000 in the pod
+  skectl port-forward --address 0.0.0.0 pod/web-1 8888:5000`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+			return o.run(ctx, args[0], args[1:])
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.addresses, "address", []string{"localhost"}, "Addresses to listen on (comma separated), only IP addresses or localhost")
+
+	return cmd
+}
+
+// run forwards the ports to the pod of the target until the context is done
+func (o *portForwardOptions) run(ctx context.Context, target string, args []string) error {
+	ports, err := portforward.ParsePorts(args)
+	if err != nil {
+		return err
+	}
+	namespace, _, err := configFlags.ToNamespace()
+	if err != nil {
+		return err
+	}
+	clientSet, err := configFlags.ToClientSet()
+	if err != nil {
+		return err
+	}
+	config, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	forwarder := &portforward.Forwarder{
+		Client:    clientSet,
+		Addresses: o.addresses,
+		Ports:     ports,
+		Out:       os.Stdout,
+		ErrOut:    os.Stderr,
+		Dialer: func(pod *corev1.Pod) (httpstream.Dialer, error) {
+			url := clientSet.CoreV1().RESTClient().Post().
+				Resource("pods").
+				Namespace(pod.Namespace).
+				Name(pod.Name).
+				SubResource("portforward").
+				URL()
+			dialer, err := newDialer(config, url)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create dialer: %w", err)
+			}
+			return dialer, nil
+		},
+	}
+
+	resourceType, name, _ := strings.Cut(target, "/")
+	if name != "" && isService(resourceType) {
+		service, err := clientSet.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get service %q: %w", name, err)
+		}
+		forwarder.Service = service
+	}
+
+	forwarder.Resolve = func(ctx context.Context) (*corev1.Pod, error) {
+		pods, err := podsFor(ctx, namespace, target)
+		if err != nil {
+			return nil, err
+		}
+		return runningPod(pods, target)
+	}
+
+	return forwarder.Run(ctx)
+}
+
+// runningPod returns the first running pod of the pods of the target, failing
+// when none is running
+func runningPod(pods []corev1.Pod, target string) (*corev1.Pod, error) {
+	for i := range pods {
+		if portforward.Running(&pods[i]) {
+			return &pods[i], nil
+		}
+	}
+	if len(pods) == 1 {
+		return nil, fmt.Errorf("pod %s is not running, current phase is %s", pods[0].Name, pods[0].Status.Phase)
+	}
+	return nil, fmt.Errorf("no running pod found for %s", target)
+}
+
+// isService reports whether the resource type names services
+func isService(resourceType string) bool {
+	switch strings.ToLower(resourceType) {
+	case "svc", "service", "services":
+		return true
+	}
+	return false
+}
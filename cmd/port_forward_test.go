@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/testutil"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/rest"
+)
+
+// fakeDialer refuses every connection
+type fakeDialer struct{}
+
+// Dial fails to open the connection
+func (fakeDialer) Dial(protocols ...string) (httpstream.Connection, string, error) {
+	return nil, "", errors.New("connection refused by the fake dialer")
+}
+
+// portForwardTestObjects are a running pod with named ports and its service
+var portForwardTestObjects = []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: api-1
+  namespace: project-a
+  labels:
+    app: api
+spec:
+  containers:
+  - name: server
+    ports:
+    - name: http
+      containerPort: 8080
+status:
+  phase: Running
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  namespace: project-a
+spec:
+  selector:
+    app: api
+  ports:
+  - name: web
+    port: 80
+    targetPort: http
+`}
+
+func TestPortForwardCmd(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantPath      string
+		expectedError string
+	}{
+		{
+			name:          "service port resolved to the target port of a pod",
+			args:          []string{"port-forward", "svc/api", "8080:80"},
+			wantPath:      "/api/v1/namespaces/project-a/pods/api-1/portforward",
+			expectedError: "connection refused by the fake dialer",
+		},
+		{
+			name:          "pod port by name",
+			args:          []string{"port-forward", "pod/api-1", ":http", "--address", "127.0.0.1"},
+			wantPath:      "/api/v1/namespaces/project-a/pods/api-1/portforward",
+			expectedError: "connection refused by the fake dialer",
+		},
+		{
+			name:          "unknown service port",
+			args:          []string{"port-forward", "svc/api", "8080"},
+			expectedError: "service api does not have a port 8080",
+		},
+		{
+			name:          "pod that is not running",
+			args:          []string{"port-forward", "db-1", "5432"},
+			expectedError: "pod db-1 is not running",
+		},
+		{
+			name:          "invalid port",
+			args:          []string{"port-forward", "api-1", "http"},
+			expectedError: "invalid port",
+		},
+		{
+			name:          "missing ports",
+			args:          []string{"port-forward", "api-1"},
+			expectedError: "requires at least 2 arg(s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupFakeAPIServer(t, "project-a")
+			for _, manifest := range append(logsTestObjects, portForwardTestObjects...) {
+				server.AddYAML(manifest)
+			}
+
+			var dialed *url.URL
+			oldDialer := newDialer
+			defer func() { newDialer = oldDialer }()
+			newDialer = func(config *rest.Config, url *url.URL) (httpstream.Dialer, error) {
+				dialed = url
+				return fakeDialer{}, nil
+			}
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			*configFlags = *client.NewConfigFlags()
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+			if tt.wantPath != "" {
+				require.NotNil(t, dialed)
+				assert.Equal(t, tt.wantPath, dialed.Path)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/kubeconfig"
+	"github.com/withlin/oc-demo/pkg/project"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// NewProjectCmd creates a new project command
+func NewProjectCmd() *cobra.Command {
+	var short bool
+
+	cmd := &cobra.Command{
+		Use:   "project [name]",
+		Short: "Switch to another project",
+		Long: `Switch to another project and make it the default in your configuration.
+
+Without a name the current project is displayed. A context named after the
+OpenShift scheme, such as myproject/api-example-com:6443/developer, is kept as
+it is and the context of the new project is used instead; any other context
+gets the new namespace.`,
+		Example: `  # Switch to the myapp project
+  skectl project myapp
+
+  # Display the current project
+  skectl project`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			restConfig, err := toRESTConfig()
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				namespace, _, err := configFlags.ToNamespace()
+				if err != nil {
+					return err
+				}
+				if short {
+					fmt.Println(namespace)
+				} else {
+					fmt.Printf("Using project %q on server %q.\n", namespace, restConfig.Host)
+				}
+				return nil
+			}
+			name := args[0]
+
+			dynamicClient, err := configFlags.ToDynamicClient()
+			if err != nil {
+				return err
+			}
+			if _, err := project.Get(cmd.Context(), dynamicClient, name); err != nil {
+				if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+					return fmt.Errorf("you are not a member of project %q, run 'skectl projects' to list the projects you can access", name)
+				}
+				return fmt.Errorf("failed to get project %q: %w", name, err)
+			}
+
+			if err := useProject(name); err != nil {
+				return err
+			}
+
+			if short {
+				fmt.Println(name)
+			} else {
+				fmt.Printf("Now using project %q on server %q.\n", name, restConfig.Host)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&short, "short", "q", false, "If true, display only the project name")
+
+	return cmd
+}
+
+// useProject switches the context selected by the --context flag, or the
+// current context, to the project and makes the resulting context current
+func useProject(name string) error {
+	configAccess, rawConfig, err := loadKubeconfig()
+	if err != nil {
+		return err
+	}
+	contextName := rawConfig.CurrentContext
+	if configFlags.Context != "" {
+		contextName = configFlags.Context
+	}
+	contextName, err = kubeconfig.SetNamespace(rawConfig, contextName, name)
+	if err != nil {
+		return err
+	}
+	rawConfig.CurrentContext = contextName
+	return saveKubeconfig(configAccess, rawConfig)
+}
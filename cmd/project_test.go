@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/testutil"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestProjectCmd(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		openshift       bool
+		expected        string
+		expectedContext string
+		expectError     bool
+	}{
+		{
+			name:            "switch to an openshift project",
+			args:            []string{"project", "project-b"},
+			openshift:       true,
+			expected:        `Now using project "project-b" on server`,
+			expectedContext: "project-b/cluster/developer",
+		},
+		{
+			name:            "switch to a kubernetes namespace",
+			args:            []string{"project", "project-b"},
+			expected:        `Now using project "project-b" on server`,
+			expectedContext: "project-b/cluster/developer",
+		},
+		{
+			name:            "display the current project",
+			args:            []string{"project"},
+			openshift:       true,
+			expected:        `Using project "project-a" on server`,
+			expectedContext: "project-a/cluster/developer",
+		},
+		{
+			name:            "current project name only",
+			args:            []string{"project", "-q"},
+			expected:        "project-a\n",
+			expectedContext: "project-a/cluster/developer",
+		},
+		{
+			name:        "project the user is not a member of",
+			args:        []string{"project", "kube-system"},
+			openshift:   true,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupFakeAPIServer(t, "project-a")
+			addProjects(server, tt.openshift)
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			output := capture.Stdout()
+			*configFlags = *client.NewConfigFlags()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, output, tt.expected)
+
+			config, err := clientcmd.LoadFromFile(os.Getenv("KUBECONFIG"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedContext, config.CurrentContext)
+			assert.Equal(t, "project-a", config.Contexts["project-a/cluster/developer"].Namespace)
+			if context, exists := config.Contexts[tt.expectedContext]; assert.True(t, exists) {
+				assert.Equal(t, "developer/cluster", context.AuthInfo)
+			}
+		})
+	}
+}
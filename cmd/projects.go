@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/project"
+)
+
+// NewProjectsCmd creates a new projects command
+func NewProjectsCmd() *cobra.Command {
+	var short bool
+
+	cmd := &cobra.Command{
+		Use:   "projects",
+		Short: "Display existing projects",
+		Long: `Display the projects you can access on the current server.
+
+On OpenShift the projects you are a member of are listed; on Kubernetes the
+namespaces are listed instead. The project of the current context is marked
+with an asterisk.`,
+		Example: `  # List the projects you can access
+  skectl projects
+
+  # Print the names of the projects only
+  skectl projects -q`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dynamicClient, err := configFlags.ToDynamicClient()
+			if err != nil {
+				return err
+			}
+			projects, err := project.List(cmd.Context(), dynamicClient)
+			if err != nil {
+				return err
+			}
+
+			if short {
+				for _, p := range projects {
+					fmt.Println(p.Name)
+				}
+				return nil
+			}
+
+			restConfig, err := toRESTConfig()
+			if err != nil {
+				return err
+			}
+			current, _, err := configFlags.ToNamespace()
+			if err != nil {
+				return err
+			}
+
+			switch len(projects) {
+			case 0:
+				fmt.Println("You are not a member of any projects.")
+				return nil
+			case 1:
+				fmt.Printf("You have one project on this server: %q.\n\n", projects[0].Name)
+			default:
+				fmt.Println("You have access to the following projects and can switch between them with 'skectl project <projectname>':")
+				fmt.Println()
+				printProjects(projects, current)
+				fmt.Println()
+			}
+			fmt.Printf("Using project %q on server %q.\n", current, restConfig.Host)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&short, "short", "q", false, "If true, display only the project names")
+
+	return cmd
+}
+
+// printProjects prints the projects, marking the current one with an asterisk
+func printProjects(projects []project.Project, current string) {
+	for _, p := range projects {
+		marker := " "
+		if p.Name == current {
+			marker = "*"
+		}
+		fmt.Printf("  %s %s\n", marker, p)
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// addProjects registers the OpenShift project API with two projects, or adds
+// the same projects as namespaces
+func addProjects(server *testutil.FakeAPIServer, openshift bool) {
+	if !openshift {
+		server.AddYAML("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: project-b\nstatus:\n  phase: Active\n")
+		server.AddYAML("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: project-a\nstatus:\n  phase: Active\n")
+		return
+	}
+	server.AddResource("project.openshift.io/v1", metav1.APIResource{Name: "projects", SingularName: "project", Kind: "Project"})
+	server.AddYAML("apiVersion: project.openshift.io/v1\nkind: Project\nmetadata:\n  name: project-b\nstatus:\n  phase: Active\n")
+	server.AddYAML("apiVersion: project.openshift.io/v1\nkind: Project\nmetadata:\n  name: project-a\n  annotations:\n    openshift.io/display-name: Project A\nstatus:\n  phase: Active\n")
+	// Namespaces the user is not a member of are not projects
+	server.AddYAML("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: kube-system\n")
+}
+
+func TestProjectsCmd(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		openshift  bool
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:      "openshift projects",
+			args:      []string{"projects"},
+			openshift: true,
+			expected: []string{
+				"You have access to the following projects",
+				"  * project-a - Project A\n    project-b\n",
+				`Using project "project-a" on server "https://`,
+			},
+			unexpected: []string{"kube-system"},
+		},
+		{
+			name:     "kubernetes namespaces",
+			args:     []string{"projects"},
+			expected: []string{"  * project-a\n    project-b\n"},
+		},
+		{
+			name:       "names only",
+			args:       []string{"projects", "-q"},
+			openshift:  true,
+			expected:   []string{"project-a\nproject-b\n"},
+			unexpected: []string{"Using project"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupFakeAPIServer(t, "project-a")
+			addProjects(server, tt.openshift)
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			output := capture.Stdout()
+			*configFlags = *client.NewConfigFlags()
+
+			require.NoError(t, err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, output, expected)
+			}
+			for _, unexpected := range tt.unexpected {
+				assert.NotContains(t, output, unexpected)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// NewRenameContextCmd creates a new rename-context command
+func NewRenameContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename-context <old-name> <new-name>",
+		Short: "Rename a context in the kubeconfig",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("old and new context names are required")
+			}
+			oldName, newName := args[0], args[1]
+
+			// Load kubeconfig
+			configAccess, config, err := loadKubeconfig()
+			if err != nil {
+				return err
+			}
+
+			// Check if contexts exist
+			context, exists := config.Contexts[oldName]
+			if !exists {
+				return fmt.Errorf("context %q does not exist", oldName)
+			}
+			if _, exists := config.Contexts[newName]; exists {
+				return fmt.Errorf("context %q already exists", newName)
+			}
+
+			// Rename context, keeping it in the file it was loaded from
+			config.Contexts[newName] = context
+			delete(config.Contexts, oldName)
+			if config.CurrentContext == oldName {
+				config.CurrentContext = newName
+			}
+
+			// Save kubeconfig
+			if err := saveKubeconfig(configAccess, config); err != nil {
+				return err
+			}
+
+			fmt.Printf("Context %q renamed to %q\n", oldName, newName)
+			return nil
+		},
+	}
+
+	return cmd
+}
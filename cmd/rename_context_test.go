@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestRenameContextCmd(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		expectError bool
+		wantCurrent string
+	}{
+		{
+			name:        "rename current context",
+			args:        []string{"context1", "renamed"},
+			wantCurrent: "renamed",
+		},
+		{
+			name:        "rename other context",
+			args:        []string{"context2", "renamed"},
+			wantCurrent: "context1",
+		},
+		{
+			name:        "rename to existing context",
+			args:        []string{"context1", "context2"},
+			expectError: true,
+		},
+		{
+			name:        "rename non-existent context",
+			args:        []string{"unknown", "renamed"},
+			expectError: true,
+		},
+		{
+			name:        "missing new name",
+			args:        []string{"context1"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeconfigPath := setupTestKubeconfig(t)
+
+			cmd := NewRenameContextCmd()
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			config, err := clientcmd.LoadFromFile(kubeconfigPath)
+			require.NoError(t, err)
+			assert.NotContains(t, config.Contexts, tt.args[0])
+			assert.Contains(t, config.Contexts, tt.args[1])
+			assert.Equal(t, tt.wantCurrent, config.CurrentContext)
+		})
+	}
+}
+
+func TestRenameContextCmdKubeconfigList(t *testing.T) {
+	paths := setupTestKubeconfigList(t, "a", "b")
+
+	cmd := NewRenameContextCmd()
+	cmd.SetArgs([]string{"b", "renamed"})
+	require.NoError(t, cmd.Execute())
+
+	// The renamed context stays in the file it came from
+	first, err := clientcmd.LoadFromFile(paths[0])
+	require.NoError(t, err)
+	second, err := clientcmd.LoadFromFile(paths[1])
+	require.NoError(t, err)
+	assert.NotContains(t, first.Contexts, "renamed")
+	assert.Contains(t, second.Contexts, "renamed")
+	assert.NotContains(t, second.Contexts, "b")
+}
@@ -3,44 +3,130 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/client"
+	"k8s.io/client-go/rest"
 )
 
+// configFlags holds the global flags overriding the kubeconfig for one invocation
+var configFlags = client.NewConfigFlags()
+
 // NewRootCmd creates a new root command
 func NewRootCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "skectl",
 		Short: "skectl is a command line tool similar to OpenShift CLI",
 		Long: `skectl is a command line tool that provides similar functionality to OpenShift CLI (oc).
-It supports cluster login and context management.
+It supports cluster login, context management and resource management.
 
 Available Commands:
-  login       Log in to a server
-  use-context Switch to a different context
+  login           Log in to a server
+  logout          End the current server session
+  whoami          Show the current user
+  auth            Inspect authorization
+
+Project Commands:
+  projects        Display existing projects
+  project         Switch to another project
+  new-project     Request a new project
+  delete-project  Delete a project
+
+Resource Commands:
+  get             Display one or many resources
+  describe        Show details of a specific resource or group of resources
+  apply           Apply a configuration to a resource by file name or stdin
+  diff            Diff the live version against a would-be applied version
+  delete          Delete resources by file names, resources and names, or by label selector
+
+Troubleshooting Commands:
+  logs            Print the logs for a container in a pod
+  exec            Execute a command in a container
+  rsh             Start a shell session in a container
+  port-forward    Forward one or more local ports to a pod
+
+Context Commands:
+  get-contexts    Describe one or many contexts
+  current-context Display the current context
+  use-context     Switch to a different context
+  set-context     Set a context entry in the kubeconfig
+  rename-context  Rename a context in the kubeconfig
+  delete-context  Delete the specified context from the kubeconfig
 
 Use "skectl <command> --help" for more information about a command.`,
 		SilenceErrors: true,
 		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := configFlags.Validate(); err != nil {
+				return err
+			}
+			if banner := impersonationBanner(configFlags.ImpersonationConfig()); banner != "" {
+				fmt.Fprintln(os.Stderr, banner)
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
 	}
 
+	// Add global flags shared by all subcommands
+	configFlags.AddFlags(cmd.PersistentFlags())
+
 	// Add subcommands
-	cmd.AddCommand(loginCmd)
-	cmd.AddCommand(useContextCmd)
+	cmd.AddCommand(NewLoginCmd())
+	cmd.AddCommand(NewLogoutCmd())
+	cmd.AddCommand(NewWhoAmICmd())
+	cmd.AddCommand(NewAuthCmd())
+	cmd.AddCommand(NewProjectsCmd())
+	cmd.AddCommand(NewProjectCmd())
+	cmd.AddCommand(NewNewProjectCmd())
+	cmd.AddCommand(NewDeleteProjectCmd())
+	cmd.AddCommand(NewGetCmd())
+	cmd.AddCommand(NewDescribeCmd())
+	cmd.AddCommand(NewApplyCmd())
+	cmd.AddCommand(NewDiffCmd())
+	cmd.AddCommand(NewDeleteCmd())
+	cmd.AddCommand(NewLogsCmd())
+	cmd.AddCommand(NewExecCmd())
+	cmd.AddCommand(NewRshCmd())
+	cmd.AddCommand(NewPortForwardCmd())
+	cmd.AddCommand(NewGetContextsCmd())
+	cmd.AddCommand(NewCurrentContextCmd())
+	cmd.AddCommand(NewUseContextCmd())
+	cmd.AddCommand(NewSetContextCmd())
+	cmd.AddCommand(NewRenameContextCmd())
+	cmd.AddCommand(NewDeleteContextCmd())
 
 	return cmd
 }
 
+// impersonationBanner returns the warning printed before the requests made as
+// another user, empty when no user is impersonated
+func impersonationBanner(impersonate rest.ImpersonationConfig) string {
+	if impersonate.UserName == "" {
+		return ""
+	}
+	identity := fmt.Sprintf("user %q", impersonate.UserName)
+	if len(impersonate.Groups) > 0 {
+		identity += fmt.Sprintf(" in groups %s", strings.Join(impersonate.Groups, ", "))
+	}
+	if impersonate.UID != "" {
+		identity += fmt.Sprintf(" with UID %q", impersonate.UID)
+	}
+	return fmt.Sprintf("Warning: impersonating %s, every request is made with the permissions of this identity", identity)
+}
+
 var rootCmd = NewRootCmd()
 
 // Execute executes the root command
 func Execute() error {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		if !silent(err) {
+			fmt.Fprintln(os.Stderr, err)
+		}
 		return err
 	}
 	return nil
-} 
\ No newline at end of file
+}
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"encoding/json"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
 	"github.com/withlin/oc-demo/pkg/testutil"
 )
 
@@ -29,7 +33,28 @@ func TestRootCmd(t *testing.T) {
 	expectedSubstrings := []string{
 		"skectl",
 		"login",
+		"logout",
 		"use-context",
+		"whoami",
+		"auth",
+		"projects",
+		"project",
+		"new-project",
+		"delete-project",
+		"get",
+		"describe",
+		"apply",
+		"diff",
+		"delete",
+		"logs",
+		"exec",
+		"rsh",
+		"port-forward",
+		"get-contexts",
+		"current-context",
+		"set-context",
+		"rename-context",
+		"delete-context",
 		"Use \"skectl <command> --help\" for more information",
 	}
 
@@ -97,4 +122,135 @@ func TestExecute(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+}
+
+func TestRootCmdGlobalFlags(t *testing.T) {
+	kubeconfigPath := setupTestKubeconfig(t)
+	t.Setenv("KUBECONFIG", "")
+	defer func() { *configFlags = *client.NewConfigFlags() }()
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "kubeconfig flag",
+			args:     []string{"--kubeconfig", kubeconfigPath, "current-context"},
+			expected: "context1\n",
+		},
+		{
+			name:     "context flag",
+			args:     []string{"--kubeconfig", kubeconfigPath, "--context", "context2", "whoami", "--show-context"},
+			expected: "context2\n",
+		},
+		{
+			name:     "context flag resolves server",
+			args:     []string{"--kubeconfig", kubeconfigPath, "--context", "context2", "whoami", "--show-server"},
+			expected: "https://cluster-b.example.com:6443\n",
+		},
+		{
+			name:     "server and token flags",
+			args:     []string{"--kubeconfig", kubeconfigPath, "--server", "https://other.example.com", "--token", "other-token", "whoami", "--show-token"},
+			expected: "other-token\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*configFlags = *client.NewConfigFlags()
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(tt.args)
+			require.NoError(t, cmd.Execute())
+			assert.Equal(t, tt.expected, capture.Stdout())
+		})
+	}
+}
+
+func TestRootCmdImpersonation(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantHeaders http.Header
+		wantBanner  string
+		errContains string
+	}{
+		{
+			name:        "no impersonation",
+			args:        []string{"projects", "-q"},
+			wantHeaders: http.Header{},
+		},
+		{
+			name: "user",
+			args: []string{"projects", "-q", "--as", "developer"},
+			wantHeaders: http.Header{
+				"Impersonate-User": {"developer"},
+			},
+			wantBanner: "Warning: impersonating user \"developer\", every request is made with the permissions of this identity\n",
+		},
+		{
+			name: "user, groups and UID",
+			args: []string{"--as", "developer", "--as-group", "dev", "--as-group", "qa", "--as-uid", "1234", "projects", "-q"},
+			wantHeaders: http.Header{
+				"Impersonate-User":  {"developer"},
+				"Impersonate-Group": {"dev", "qa"},
+				"Impersonate-Uid":   {"1234"},
+			},
+			wantBanner: "Warning: impersonating user \"developer\" in groups dev, qa with UID \"1234\", every request is made with the permissions of this identity\n",
+		},
+		{
+			name:        "groups without user",
+			args:        []string{"projects", "--as-group", "dev"},
+			errContains: "without impersonating a user",
+		},
+		{
+			name:        "UID without user on a command not calling the server",
+			args:        []string{"current-context", "--as-uid", "1234"},
+			errContains: "without impersonating a user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupFakeAPIServer(t, "project-a")
+			headers := http.Header{}
+			server.Handle(http.MethodGet, "/apis/project.openshift.io/v1/projects", func(w http.ResponseWriter, r *http.Request) {
+				for key, values := range r.Header {
+					if strings.HasPrefix(key, "Impersonate-") {
+						headers[key] = values
+					}
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"apiVersion": "project.openshift.io/v1",
+					"kind":       "ProjectList",
+					"items":      []interface{}{map[string]interface{}{"metadata": map[string]string{"name": "project-a"}}},
+				})
+			})
+
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			*configFlags = *client.NewConfigFlags()
+
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "project-a\n", capture.Stdout())
+			assert.Equal(t, tt.wantBanner, capture.Stderr())
+			assert.Equal(t, tt.wantHeaders, headers)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// NewSetContextCmd creates a new set-context command
+func NewSetContextCmd() *cobra.Command {
+	var (
+		namespace string
+		cluster   string
+		user      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set-context [name]",
+		Short: "Set a context entry in the kubeconfig",
+		Long: `Set a context entry in the kubeconfig.
+
+Specifying a name that already exists will merge new fields on top of existing
+values for those fields. Without a name the current context is modified.`,
+		Example: `  # Change the namespace of the current context
+  skectl set-context --namespace=myproject
+
+  # Create a context for the admin user on a cluster
+  skectl set-context admin --cluster=api-example-com:6443 --user=admin/api-example-com:6443`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Load kubeconfig
+			configAccess, config, err := loadKubeconfig()
+			if err != nil {
+				return err
+			}
+
+			// Default to the current context
+			contextName := config.CurrentContext
+			if len(args) > 0 {
+				contextName = args[0]
+			}
+			if contextName == "" {
+				return fmt.Errorf("context name is required when no current context is set")
+			}
+
+			context, exists := config.Contexts[contextName]
+			if !exists {
+				context = api.NewContext()
+			}
+			if cmd.Flags().Changed("namespace") {
+				context.Namespace = namespace
+			}
+			if cmd.Flags().Changed("cluster") {
+				context.Cluster = cluster
+			}
+			if cmd.Flags().Changed("user") {
+				context.AuthInfo = user
+			}
+			config.Contexts[contextName] = context
+
+			// Save kubeconfig
+			if err := saveKubeconfig(configAccess, config); err != nil {
+				return err
+			}
+
+			if exists {
+				fmt.Printf("Context %q modified.\n", contextName)
+			} else {
+				fmt.Printf("Context %q created.\n", contextName)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace of the context")
+	cmd.Flags().StringVar(&cluster, "cluster", "", "Cluster of the context")
+	cmd.Flags().StringVar(&user, "user", "", "User of the context")
+
+	return cmd
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestSetContextCmd(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		context       string
+		wantNamespace string
+		wantCluster   string
+		wantUser      string
+	}{
+		{
+			name:          "change namespace of current context",
+			args:          []string{"--namespace", "other"},
+			context:       "context1",
+			wantNamespace: "other",
+			wantCluster:   "cluster-a",
+			wantUser:      "developer/cluster-a",
+		},
+		{
+			name:          "change cluster of named context",
+			args:          []string{"context2", "--cluster", "cluster-a"},
+			context:       "context2",
+			wantNamespace: "",
+			wantCluster:   "cluster-a",
+			wantUser:      "developer/cluster-b",
+		},
+		{
+			name:          "create context",
+			args:          []string{"new", "--cluster", "cluster-b", "--user", "developer/cluster-b", "-n", "project-b"},
+			context:       "new",
+			wantNamespace: "project-b",
+			wantCluster:   "cluster-b",
+			wantUser:      "developer/cluster-b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeconfigPath := setupTestKubeconfig(t)
+
+			cmd := NewSetContextCmd()
+			cmd.SetArgs(tt.args)
+			require.NoError(t, cmd.Execute())
+
+			config, err := clientcmd.LoadFromFile(kubeconfigPath)
+			require.NoError(t, err)
+			context := config.Contexts[tt.context]
+			require.NotNil(t, context)
+			assert.Equal(t, tt.wantNamespace, context.Namespace)
+			assert.Equal(t, tt.wantCluster, context.Cluster)
+			assert.Equal(t, tt.wantUser, context.AuthInfo)
+			assert.Equal(t, "context1", config.CurrentContext)
+		})
+	}
+}
@@ -2,11 +2,8 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 
 	"github.com/spf13/cobra"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 // NewUseContextCmd creates a new use-context command
@@ -20,20 +17,10 @@ func NewUseContextCmd() *cobra.Command {
 			}
 			contextName := args[0]
 
-			// Get kubeconfig path
-			kubeconfigPath := os.Getenv("KUBECONFIG")
-			if kubeconfigPath == "" {
-				homeDir, err := os.UserHomeDir()
-				if err != nil {
-					return fmt.Errorf("failed to get home directory: %w", err)
-				}
-				kubeconfigPath = filepath.Join(homeDir, ".kube", "config")
-			}
-
 			// Load kubeconfig
-			config, err := clientcmd.LoadFromFile(kubeconfigPath)
+			configAccess, config, err := loadKubeconfig()
 			if err != nil {
-				return fmt.Errorf("failed to load kubeconfig: %w", err)
+				return err
 			}
 
 			// Check if context exists
@@ -45,8 +32,8 @@ func NewUseContextCmd() *cobra.Command {
 			config.CurrentContext = contextName
 
 			// Save kubeconfig
-			if err := clientcmd.WriteToFile(*config, kubeconfigPath); err != nil {
-				return fmt.Errorf("failed to write kubeconfig: %w", err)
+			if err := saveKubeconfig(configAccess, config); err != nil {
+				return err
 			}
 
 			fmt.Printf("Switched to context %q\n", contextName)
@@ -56,9 +43,3 @@ func NewUseContextCmd() *cobra.Command {
 
 	return cmd
 }
-
-var useContextCmd = NewUseContextCmd()
-
-func init() {
-	rootCmd.AddCommand(useContextCmd)
-} 
\ No newline at end of file
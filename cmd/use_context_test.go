@@ -65,4 +65,25 @@ func TestUseContextCmd(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+}
+
+func TestUseContextCmdKubeconfigList(t *testing.T) {
+	paths := setupTestKubeconfigList(t, "a", "b", "c")
+
+	cmd := NewUseContextCmd()
+	cmd.SetArgs([]string{"c"})
+	require.NoError(t, cmd.Execute())
+
+	// The current context is written to the first file only
+	first, err := clientcmd.LoadFromFile(paths[0])
+	require.NoError(t, err)
+	assert.Equal(t, "c", first.CurrentContext)
+	assert.Len(t, first.Contexts, 1)
+
+	for _, path := range paths[1:] {
+		config, err := clientcmd.LoadFromFile(path)
+		require.NoError(t, err)
+		assert.Empty(t, config.CurrentContext)
+		assert.Len(t, config.Contexts, 1)
+	}
+}
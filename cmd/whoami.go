@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/withlin/oc-demo/pkg/auth"
+	"github.com/withlin/oc-demo/pkg/printers"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// NewWhoAmICmd creates a new whoami command
+func NewWhoAmICmd() *cobra.Command {
+	var (
+		showToken   bool
+		showServer  bool
+		showContext bool
+		showConsole bool
+	)
+	printFlags := printers.NewPrintFlags()
+
+	cmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Show the current user",
+		Long: `Show the user the current context is logged in as.
+
+The identity is looked up on the server using the OpenShift user API, falling
+back to SelfSubjectReview, with any credentials of the kubeconfig: tokens,
+client certificates or credential plugins. When the token is a JWT its issuer,
+expiry and groups are shown as well.`,
+		Example: `  # Show the current user
+  skectl whoami
+
+  # Show the token of the current context
+  skectl whoami --show-token
+
+  # Show the web console URL of the current server
+  skectl whoami --show-console
+
+  # Show the user with its UID and groups as YAML
+  skectl whoami -o yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if showContext {
+				contextName, err := configFlags.CurrentContextName()
+				if err != nil {
+					return err
+				}
+				fmt.Println(contextName)
+				return nil
+			}
+
+			// Resolve the server and token, honoring the global flags
+			restConfig, err := toRESTConfig()
+			if err != nil {
+				return err
+			}
+			if showServer {
+				fmt.Println(restConfig.Host)
+				return nil
+			}
+
+			switch {
+			case showToken:
+				token, err := auth.BearerToken(restConfig)
+				if err != nil {
+					return fmt.Errorf("no token is available for the current context: %w", err)
+				}
+				fmt.Println(token)
+				return nil
+			case showConsole:
+				consoleURL, err := auth.ConsoleURL(cmd.Context(), restConfig)
+				if err != nil {
+					return err
+				}
+				fmt.Println(consoleURL)
+				return nil
+			}
+
+			var printer printers.ResourcePrinter
+			if printFlags.OutputFormat != "" {
+				if printer, err = printFlags.ToPrinter(); err != nil {
+					return err
+				}
+			}
+
+			user, err := auth.WhoAmI(cmd.Context(), restConfig)
+			if err != nil {
+				return fmt.Errorf("failed to get current user: %w", err)
+			}
+			if printer != nil {
+				return printer.PrintObj(userObject(user), os.Stdout)
+			}
+			fmt.Println(user.Username)
+
+			// Show token details when the token is a JWT of the user, not of
+			// the user impersonating it
+			if restConfig.Impersonate.UserName != "" {
+				return nil
+			}
+			token, err := auth.BearerToken(restConfig)
+			if err != nil {
+				return nil
+			}
+			claims, err := auth.ParseJWTClaims(token)
+			if errors.Is(err, auth.ErrNotJWT) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			printTokenClaims(claims, user)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&showToken, "show-token", "t", false, "Print the token the current session is using")
+	cmd.Flags().BoolVar(&showServer, "show-server", false, "Print the server the current context is connected to")
+	cmd.Flags().BoolVarP(&showContext, "show-context", "c", false, "Print the current context name")
+	cmd.Flags().BoolVar(&showConsole, "show-console", false, "Print the web console URL of the current server")
+	printFlags.AddFlags(cmd.Flags())
+	cmd.MarkFlagsMutuallyExclusive("show-token", "show-server", "show-context", "show-console", "output")
+
+	return cmd
+}
+
+// userObject returns the user as an OpenShift User object for the printers
+func userObject(user *auth.UserInfo) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "user.openshift.io/v1",
+		"kind":       "User",
+		"metadata":   map[string]interface{}{"name": user.Username},
+	}}
+	if user.UID != "" {
+		obj.SetUID(types.UID(user.UID))
+	}
+	groups := make([]interface{}, len(user.Groups))
+	for i, group := range user.Groups {
+		groups[i] = group
+	}
+	obj.Object["groups"] = groups
+	return obj
+}
+
+// printTokenClaims prints the issuer, expiry and groups of a JWT
+func printTokenClaims(claims *auth.TokenClaims, user *auth.UserInfo) {
+	if claims.Issuer != "" {
+		fmt.Printf("Issuer:  %s\n", claims.Issuer)
+	}
+
+	if !claims.ExpiresAt.IsZero() {
+		remaining := time.Until(claims.ExpiresAt).Round(time.Second)
+		status := fmt.Sprintf("in %s", remaining)
+		if remaining <= 0 {
+			status = "expired"
+		}
+		fmt.Printf("Expires: %s (%s)\n", claims.ExpiresAt.UTC().Format(time.RFC3339), status)
+	}
+
+	// Service account tokens carry no groups claim, use the groups known to the server
+	groups := claims.Groups
+	if len(groups) == 0 {
+		groups = user.Groups
+	}
+	if len(groups) > 0 {
+		fmt.Printf("Groups:  %s\n", strings.Join(groups, ", "))
+	}
+}
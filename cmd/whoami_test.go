@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/withlin/oc-demo/pkg/client"
+	"github.com/withlin/oc-demo/pkg/testutil"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestWhoAmICmd(t *testing.T) {
+	// Create temporary directory for test
+	tmpDir, err := os.MkdirTemp("", "skectl-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	// Set KUBECONFIG environment variable
+	kubeconfigPath := filepath.Join(tmpDir, "config")
+	os.Setenv("KUBECONFIG", kubeconfigPath)
+	defer os.Unsetenv("KUBECONFIG")
+
+	// Create a service account style JWT
+	jwtToken := "eyJhbGciOiJSUzI1NiJ9." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"https://issuer.example.com","exp":1893456000,"groups":["deployers"]}`)) +
+		".signature"
+
+	// Create TLS test API server knowing an opaque and a JWT token
+	apiServer := httptest.NewTLSServer(usersHandler(map[string]string{
+		"opaque-token": "developer",
+		jwtToken:       "system:serviceaccount:ci:deployer",
+	}))
+	defer apiServer.Close()
+
+	tests := []struct {
+		name        string
+		args        []string
+		token       string
+		expected    []string
+		expectError bool
+	}{
+		{
+			name:     "show user",
+			args:     []string{},
+			token:    "opaque-token",
+			expected: []string{"developer\n"},
+		},
+		{
+			name:  "show JWT claims",
+			args:  []string{},
+			token: jwtToken,
+			expected: []string{
+				"system:serviceaccount:ci:deployer\n",
+				"Issuer:  https://issuer.example.com",
+				"Expires: 2030-01-01T00:00:00Z",
+				"Groups:  deployers",
+			},
+		},
+		{
+			name:     "show token",
+			args:     []string{"--show-token"},
+			token:    "opaque-token",
+			expected: []string{"opaque-token\n"},
+		},
+		{
+			name:     "show server",
+			args:     []string{"--show-server"},
+			token:    "opaque-token",
+			expected: []string{apiServer.URL + "\n"},
+		},
+		{
+			name:     "show context",
+			args:     []string{"--show-context"},
+			token:    "opaque-token",
+			expected: []string{"default/cluster/developer\n"},
+		},
+		{
+			name:     "show user as yaml",
+			args:     []string{"-o", "yaml"},
+			token:    "opaque-token",
+			expected: []string{"apiVersion: user.openshift.io/v1\n", "kind: User\n", "  name: developer\n"},
+		},
+		{
+			name:     "show user with jsonpath",
+			args:     []string{"-o", "jsonpath={.metadata.name}"},
+			token:    "opaque-token",
+			expected: []string{"developer"},
+		},
+		{
+			name:        "output with show flag",
+			args:        []string{"-o", "yaml", "--show-token"},
+			token:       "opaque-token",
+			expectError: true,
+		},
+		{
+			name:        "invalid token",
+			args:        []string{},
+			token:       "invalid-token",
+			expectError: true,
+		},
+		{
+			name:        "not logged in",
+			args:        []string{},
+			token:       "",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create kubeconfig with a logged in context
+			config := api.NewConfig()
+			config.Clusters["cluster"] = &api.Cluster{Server: apiServer.URL, InsecureSkipTLSVerify: true}
+			config.AuthInfos["developer/cluster"] = &api.AuthInfo{Token: tt.token}
+			config.Contexts["default/cluster/developer"] = &api.Context{Cluster: "cluster", AuthInfo: "developer/cluster"}
+			config.CurrentContext = "default/cluster/developer"
+			require.NoError(t, clientcmd.WriteToFile(*config, kubeconfigPath))
+
+			// Create output capturer
+			capture := testutil.NewCaptureOutput()
+			require.NoError(t, capture.Start(), "Failed to start output capture")
+			defer capture.Stop()
+
+			// Execute command
+			cmd := NewWhoAmICmd()
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			output := capture.Stdout()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, output, expected)
+			}
+		})
+	}
+}
+
+func TestWhoAmICmdImpersonation(t *testing.T) {
+	// Create temporary directory for test
+	tmpDir, err := os.MkdirTemp("", "skectl-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	// Set KUBECONFIG environment variable
+	kubeconfigPath := filepath.Join(tmpDir, "config")
+	os.Setenv("KUBECONFIG", kubeconfigPath)
+	defer os.Unsetenv("KUBECONFIG")
+
+	// Create a JWT whose claims must not be shown for the impersonated user
+	jwtToken := "eyJhbGciOiJSUzI1NiJ9." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"https://issuer.example.com","groups":["admins"]}`)) +
+		".signature"
+
+	// Create TLS test API server answering as the impersonated user
+	users := usersHandler(map[string]string{jwtToken: "admin"})
+	apiServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user := r.Header.Get("Impersonate-User"); user != "" && r.Header.Get("Authorization") == "Bearer "+jwtToken {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"metadata": map[string]string{"name": user},
+			})
+			return
+		}
+		users.ServeHTTP(w, r)
+	}))
+	defer apiServer.Close()
+
+	config := api.NewConfig()
+	config.Clusters["cluster"] = &api.Cluster{Server: apiServer.URL, InsecureSkipTLSVerify: true}
+	config.AuthInfos["admin/cluster"] = &api.AuthInfo{Token: jwtToken}
+	config.Contexts["default/cluster/admin"] = &api.Context{Cluster: "cluster", AuthInfo: "admin/cluster"}
+	config.CurrentContext = "default/cluster/admin"
+	require.NoError(t, clientcmd.WriteToFile(*config, kubeconfigPath))
+
+	capture := testutil.NewCaptureOutput()
+	require.NoError(t, capture.Start(), "Failed to start output capture")
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"whoami", "--as", "bob"})
+	err = cmd.Execute()
+	output := capture.Stdout()
+	capture.Stop()
+	*configFlags = *client.NewConfigFlags()
+
+	require.NoError(t, err)
+	assert.Equal(t, "bob\n", output)
+}
+
+func TestWhoAmICmdClientCertificate(t *testing.T) {
+	// Create temporary directory for test
+	tmpDir, err := os.MkdirTemp("", "skectl-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	// Set KUBECONFIG environment variable
+	kubeconfigPath := filepath.Join(tmpDir, "config")
+	os.Setenv("KUBECONFIG", kubeconfigPath)
+	defer os.Unsetenv("KUBECONFIG")
+
+	// Create a self-signed client certificate for the user
+	certPEM, keyPEM := newSelfSignedCertificate(t, "cert-user")
+
+	// Create TLS test API server naming the user after its client certificate
+	apiServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 || r.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"metadata": map[string]string{"name": r.TLS.PeerCertificates[0].Subject.CommonName},
+		})
+	}))
+	apiServer.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	apiServer.StartTLS()
+	defer apiServer.Close()
+
+	// Create kubeconfig with a context authenticating with the certificate
+	config := api.NewConfig()
+	config.Clusters["cluster"] = &api.Cluster{Server: apiServer.URL, InsecureSkipTLSVerify: true}
+	config.AuthInfos["cert-user/cluster"] = &api.AuthInfo{
+		ClientCertificateData: certPEM,
+		ClientKeyData:         keyPEM,
+	}
+	config.Contexts["default/cluster/cert-user"] = &api.Context{Cluster: "cluster", AuthInfo: "cert-user/cluster"}
+	config.CurrentContext = "default/cluster/cert-user"
+	require.NoError(t, clientcmd.WriteToFile(*config, kubeconfigPath))
+
+	capture := testutil.NewCaptureOutput()
+	require.NoError(t, capture.Start(), "Failed to start output capture")
+	cmd := NewWhoAmICmd()
+	cmd.SetArgs([]string{})
+	err = cmd.Execute()
+	output := capture.Stdout()
+	capture.Stop()
+	require.NoError(t, err)
+	assert.Equal(t, "cert-user\n", output)
+
+	// There is no token to show
+	cmd = NewWhoAmICmd()
+	cmd.SetArgs([]string{"--show-token"})
+	assert.Error(t, cmd.Execute())
+}
+
+// newSelfSignedCertificate returns a PEM encoded self-signed certificate for the
+// common name, usable by clients and servers, and its private key
+func newSelfSignedCertificate(t *testing.T, commonName string) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
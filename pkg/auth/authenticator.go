@@ -2,7 +2,6 @@ package auth
 
 import (
 	"bytes"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -45,17 +44,22 @@ type Config struct {
 	Server string
 	// AuthPath is the path of the authentication endpoint
 	AuthPath string
+	// RevokePath is the path of the token revocation endpoint
+	RevokePath string
 	// Timeout is the HTTP request timeout
 	Timeout time.Duration
 	// InsecureSkipVerify indicates whether to skip TLS verification
 	InsecureSkipVerify bool
+	// CAData holds PEM-encoded certificate authorities trusted in addition to the system roots
+	CAData []byte
 }
 
 // DefaultConfig returns default configuration
 func DefaultConfig() *Config {
 	return &Config{
-		AuthPath: "/auth",
-		Timeout:  10 * time.Second,
+		AuthPath:   "/auth",
+		RevokePath: "/auth/revoke",
+		Timeout:    10 * time.Second,
 	}
 }
 
@@ -67,6 +71,19 @@ type httpAuthenticator struct {
 
 // NewAuthenticator creates a new authenticator
 func NewAuthenticator(config *Config) (Authenticator, error) {
+	config, err := normalizeConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpAuthenticator{
+		config: config,
+		client: newHTTPClient(config),
+	}, nil
+}
+
+// normalizeConfig validates the configuration and normalizes the server URL
+func normalizeConfig(config *Config) (*Config, error) {
 	if config == nil {
 		config = DefaultConfig()
 	}
@@ -89,27 +106,33 @@ func NewAuthenticator(config *Config) (Authenticator, error) {
 	// Remove trailing slash from URL
 	config.Server = strings.TrimRight(serverURL.String(), "/")
 
-	// Ensure AuthPath starts with slash
+	// Ensure AuthPath and RevokePath start with slash
 	if config.AuthPath != "" && !strings.HasPrefix(config.AuthPath, "/") {
 		config.AuthPath = "/" + config.AuthPath
 	}
+	if config.RevokePath != "" && !strings.HasPrefix(config.RevokePath, "/") {
+		config.RevokePath = "/" + config.RevokePath
+	}
+
+	// Ensure certificate authority data is usable
+	if err := validateCAData(config.CAData); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
 
+// newHTTPClient creates an HTTP client honoring the TLS and timeout settings
+func newHTTPClient(config *Config) *http.Client {
 	// Create HTTP client with custom transport
 	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: config.InsecureSkipVerify,
-		},
+		TLSClientConfig: newTLSConfig(config),
 	}
 
-	client := &http.Client{
+	return &http.Client{
 		Timeout:   config.Timeout,
 		Transport: transport,
 	}
-
-	return &httpAuthenticator{
-		config: config,
-		client: client,
-	}, nil
 }
 
 // Authenticate implements authentication method
@@ -166,18 +189,20 @@ func (a *httpAuthenticator) Authenticate(username, password string) (string, err
 
 	// Parse response
 	var authResp AuthResponse
-	if err := json.Unmarshal(body, &authResp); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
-	}
+	decodeErr := json.Unmarshal(body, &authResp)
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
-		if authResp.Error != "" {
+		if decodeErr == nil && authResp.Error != "" {
 			return "", fmt.Errorf("authentication failed: %s", authResp.Error)
 		}
 		return "", fmt.Errorf("authentication failed with status code: %d", resp.StatusCode)
 	}
 
+	if decodeErr != nil {
+		return "", fmt.Errorf("failed to decode response: %w", decodeErr)
+	}
+
 	if authResp.Token == "" {
 		return "", ErrEmptyToken
 	}
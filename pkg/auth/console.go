@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"k8s.io/client-go/rest"
+)
+
+// ConsolePublicConfigMapPath is the config map publishing the OpenShift web console URL
+const ConsolePublicConfigMapPath = "/api/v1/namespaces/openshift-config-managed/configmaps/console-public"
+
+// ErrConsoleNotFound indicates the server does not publish a web console URL
+var ErrConsoleNotFound = errors.New("web console URL is not available on this server")
+
+// consolePublicConfigMap defines the subset of the console-public config map we need
+type consolePublicConfigMap struct {
+	Data struct {
+		ConsoleURL string `json:"consoleURL"`
+	} `json:"data"`
+}
+
+// ConsoleURL returns the public URL of the OpenShift web console, read with the
+// credentials of the REST config
+func ConsoleURL(ctx context.Context, config *rest.Config) (string, error) {
+	client, err := newRESTHTTPClient(config)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, config.Host+ConsolePublicConfigMapPath, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var configMap consolePublicConfigMap
+	err = doRequest(client, req, &configMap)
+	if errors.Is(err, errNotServed) {
+		return "", ErrConsoleNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if configMap.Data.ConsoleURL == "" {
+		return "", ErrConsoleNotFound
+	}
+
+	return configMap.Data.ConsoleURL, nil
+}
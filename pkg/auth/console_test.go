@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestConsoleURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		consoleURL  string
+		status      int
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:       "console published",
+			consoleURL: "https://console.apps.example.com",
+			status:     http.StatusOK,
+		},
+		{
+			name:        "console not installed",
+			status:      http.StatusNotFound,
+			wantErr:     true,
+			errContains: "web console URL is not available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != ConsolePublicConfigMapPath {
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"data": map[string]string{"consoleURL": tt.consoleURL},
+				})
+			}))
+			defer server.Close()
+
+			consoleURL, err := ConsoleURL(context.Background(), &rest.Config{Host: server.URL, BearerToken: "valid-token"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ConsoleURL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("ConsoleURL() error = %v, want error containing %v", err, tt.errContains)
+			}
+			if !tt.wantErr && consoleURL != tt.consoleURL {
+				t.Errorf("ConsoleURL() = %v, want %v", consoleURL, tt.consoleURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// DefaultExecAPIVersion is the ExecCredential version requested from credential
+// plugins. The plugins themselves are run by the client-go exec transport of
+// the REST config, so every request made with the config gets a fresh token.
+const DefaultExecAPIVersion = "client.authentication.k8s.io/v1"
+
+// ParseExecEnv parses NAME=VALUE pairs into plugin environment variables
+func ParseExecEnv(pairs []string) ([]api.ExecEnvVar, error) {
+	env := make([]api.ExecEnvVar, 0, len(pairs))
+	for _, pair := range pairs {
+		name, value, found := strings.Cut(pair, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid environment variable %q, expected NAME=VALUE", pair)
+		}
+		env = append(env, api.ExecEnvVar{Name: name, Value: value})
+	}
+	return env, nil
+}
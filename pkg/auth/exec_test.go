@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// fakePlugin answers with the version requested in KUBERNETES_EXEC_INFO and a
+// token made of its first argument and $TOKEN_SUFFIX, or fails as asked
+const fakePlugin = `#!/bin/sh
+case "$1" in
+fail)
+	echo "plugin failure" >&2
+	exit 1
+	;;
+no-token)
+	echo '{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{}}'
+	exit 0
+	;;
+garbage)
+	echo 'not json'
+	exit 0
+	;;
+esac
+case "$KUBERNETES_EXEC_INFO" in
+*'"apiVersion":"client.authentication.k8s.io/v1beta1"'*) version=client.authentication.k8s.io/v1beta1 ;;
+*'"apiVersion":"client.authentication.k8s.io/v1"'*) version=client.authentication.k8s.io/v1 ;;
+*) version=unknown ;;
+esac
+printf '{"apiVersion":"%s","kind":"ExecCredential","status":{"token":"%s%s"}}\n' "$version" "$1" "$TOKEN_SUFFIX"
+`
+
+// writeFakePlugin writes the fake plugin script and returns its path
+func writeFakePlugin(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake credential plugin is a shell script")
+	}
+
+	path := filepath.Join(t.TempDir(), "fake-plugin")
+	if err := os.WriteFile(path, []byte(fakePlugin), 0700); err != nil {
+		t.Fatalf("failed to write fake plugin: %v", err)
+	}
+	return path
+}
+
+func TestBearerTokenExecPlugin(t *testing.T) {
+	plugin := writeFakePlugin(t)
+
+	tests := []struct {
+		name        string
+		config      *api.ExecConfig
+		wantToken   string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:      "token from arguments and environment",
+			config:    &api.ExecConfig{Command: plugin, Args: []string{"sha256~token"}, Env: []api.ExecEnvVar{{Name: "TOKEN_SUFFIX", Value: "-1"}}, APIVersion: DefaultExecAPIVersion},
+			wantToken: "sha256~token-1",
+		},
+		{
+			name:      "beta API version",
+			config:    &api.ExecConfig{Command: plugin, Args: []string{"beta"}, APIVersion: "client.authentication.k8s.io/v1beta1"},
+			wantToken: "beta",
+		},
+		{
+			name:        "unsupported API version",
+			config:      &api.ExecConfig{Command: plugin, APIVersion: "client.authentication.k8s.io/v1alpha0"},
+			wantErr:     true,
+			errContains: "invalid apiVersion",
+		},
+		{
+			name:        "failing plugin",
+			config:      &api.ExecConfig{Command: plugin, Args: []string{"fail"}, APIVersion: DefaultExecAPIVersion},
+			wantErr:     true,
+			errContains: "exit code 1",
+		},
+		{
+			name:        "no token",
+			config:      &api.ExecConfig{Command: plugin, Args: []string{"no-token"}, APIVersion: DefaultExecAPIVersion},
+			wantErr:     true,
+			errContains: "didn't return a token",
+		},
+		{
+			name:        "invalid output",
+			config:      &api.ExecConfig{Command: plugin, Args: []string{"garbage"}, APIVersion: DefaultExecAPIVersion},
+			wantErr:     true,
+			errContains: "decoding stdout",
+		},
+		{
+			name:        "missing plugin",
+			config:      &api.ExecConfig{Command: "skectl-missing-credential-plugin", APIVersion: DefaultExecAPIVersion},
+			wantErr:     true,
+			errContains: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.InteractiveMode = api.NeverExecInteractiveMode
+			token, err := BearerToken(&rest.Config{Host: "https://api.example.com:6443", ExecProvider: tt.config})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BearerToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err != nil && !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("BearerToken() error = %v, want error containing %v", err, tt.errContains)
+			}
+			if token != tt.wantToken {
+				t.Errorf("BearerToken() token = %v, want %v", token, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestParseExecEnv(t *testing.T) {
+	env, err := ParseExecEnv([]string{"VAULT_ADDR=https://vault.example.com", "EMPTY=", "QUERY=a=b"})
+	if err != nil {
+		t.Fatalf("ParseExecEnv() error = %v", err)
+	}
+	want := []api.ExecEnvVar{
+		{Name: "VAULT_ADDR", Value: "https://vault.example.com"},
+		{Name: "EMPTY", Value: ""},
+		{Name: "QUERY", Value: "a=b"},
+	}
+	if !reflect.DeepEqual(env, want) {
+		t.Errorf("ParseExecEnv() = %v, want %v", env, want)
+	}
+
+	for _, pair := range []string{"NOVALUE", "=value"} {
+		if _, err := ParseExecEnv([]string{pair}); err == nil {
+			t.Errorf("ParseExecEnv(%q) expected an error", pair)
+		}
+	}
+}
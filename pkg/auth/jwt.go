@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNotJWT indicates the token is not a JSON Web Token
+var ErrNotJWT = errors.New("token is not a JWT")
+
+// TokenClaims defines the JWT claims of interest. The signature is not
+// verified, the claims are informational only.
+type TokenClaims struct {
+	Issuer    string
+	Subject   string
+	Audience  []string
+	ExpiresAt time.Time
+	IssuedAt  time.Time
+	Groups    []string
+}
+
+// jwtClaims defines the JSON layout of the JWT claims
+type jwtClaims struct {
+	Issuer   string          `json:"iss"`
+	Subject  string          `json:"sub"`
+	Audience json.RawMessage `json:"aud"`
+	Expiry   int64           `json:"exp"`
+	IssuedAt int64           `json:"iat"`
+	Groups   []string        `json:"groups"`
+}
+
+// ParseJWTClaims decodes the claims of a JWT without verifying its signature
+func ParseJWTClaims(token string) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrNotJWT
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, ErrNotJWT
+	}
+
+	var claims jwtClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("failed to decode JWT claims: %w", err)
+	}
+
+	result := &TokenClaims{
+		Issuer:  claims.Issuer,
+		Subject: claims.Subject,
+		Groups:  claims.Groups,
+	}
+	if claims.Expiry > 0 {
+		result.ExpiresAt = time.Unix(claims.Expiry, 0)
+	}
+	if claims.IssuedAt > 0 {
+		result.IssuedAt = time.Unix(claims.IssuedAt, 0)
+	}
+
+	// The audience is either a single string or a list of strings
+	if len(claims.Audience) > 0 {
+		var audience string
+		if err := json.Unmarshal(claims.Audience, &audience); err == nil {
+			result.Audience = []string{audience}
+		} else if err := json.Unmarshal(claims.Audience, &result.Audience); err != nil {
+			return nil, fmt.Errorf("failed to decode JWT audience: %w", err)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newJWT creates an unsigned JWT carrying the given JSON claims
+func newJWT(claims string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(claims))
+	return header + "." + payload + ".signature"
+}
+
+func TestParseJWTClaims(t *testing.T) {
+	tests := []struct {
+		name         string
+		token        string
+		wantIssuer   string
+		wantExpiry   time.Time
+		wantGroups   []string
+		wantAudience []string
+		wantErr      bool
+		errContains  string
+	}{
+		{
+			name:         "full claims",
+			token:        newJWT(`{"iss":"https://issuer.example.com","sub":"developer","aud":["api"],"exp":1893456000,"groups":["devs","ops"]}`),
+			wantIssuer:   "https://issuer.example.com",
+			wantExpiry:   time.Unix(1893456000, 0),
+			wantGroups:   []string{"devs", "ops"},
+			wantAudience: []string{"api"},
+		},
+		{
+			name:         "single audience",
+			token:        newJWT(`{"iss":"kubernetes/serviceaccount","aud":"api"}`),
+			wantIssuer:   "kubernetes/serviceaccount",
+			wantAudience: []string{"api"},
+		},
+		{
+			name:        "opaque token",
+			token:       "sha256~opaque",
+			wantErr:     true,
+			errContains: "not a JWT",
+		},
+		{
+			name:        "invalid claims",
+			token:       newJWT(`not json`),
+			wantErr:     true,
+			errContains: "failed to decode JWT claims",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ParseJWTClaims(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseJWTClaims() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("ParseJWTClaims() error = %v, want error containing %v", err, tt.errContains)
+				}
+				return
+			}
+			if claims.Issuer != tt.wantIssuer {
+				t.Errorf("ParseJWTClaims() issuer = %v, want %v", claims.Issuer, tt.wantIssuer)
+			}
+			if !claims.ExpiresAt.Equal(tt.wantExpiry) {
+				t.Errorf("ParseJWTClaims() expiry = %v, want %v", claims.ExpiresAt, tt.wantExpiry)
+			}
+			if strings.Join(claims.Groups, ",") != strings.Join(tt.wantGroups, ",") {
+				t.Errorf("ParseJWTClaims() groups = %v, want %v", claims.Groups, tt.wantGroups)
+			}
+			if strings.Join(claims.Audience, ",") != strings.Join(tt.wantAudience, ",") {
+				t.Errorf("ParseJWTClaims() audience = %v, want %v", claims.Audience, tt.wantAudience)
+			}
+		})
+	}
+}
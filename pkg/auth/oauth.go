@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// OAuthMetadataPath is the discovery endpoint exposed by OpenShift API servers
+	OAuthMetadataPath = "/.well-known/oauth-authorization-server"
+
+	// OpenShiftChallengingClientID is the OAuth client used for challenge-based login
+	OpenShiftChallengingClientID = "openshift-challenging-client"
+
+	// csrfTokenHeader must be present on challenge requests, its value is ignored
+	csrfTokenHeader = "X-CSRF-Token"
+)
+
+// ErrOAuthNotSupported indicates the server does not expose OAuth discovery metadata
+var ErrOAuthNotSupported = errors.New("server does not support OAuth discovery")
+
+// ErrInvalidCredentials indicates the OAuth server rejected the credentials
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+// OAuthMetadata defines the OAuth authorization server metadata
+type OAuthMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+}
+
+// oauthAuthenticator implements the OpenShift OAuth challenge-based authenticator
+type oauthAuthenticator struct {
+	config   *Config
+	client   *http.Client
+	metadata *OAuthMetadata
+}
+
+// DiscoverOAuthMetadata fetches the OAuth server metadata from the API server
+func DiscoverOAuthMetadata(config *Config) (*OAuthMetadata, error) {
+	config, err := normalizeConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return discoverOAuthMetadata(newHTTPClient(config), config.Server)
+}
+
+func discoverOAuthMetadata(client *http.Client, server string) (*OAuthMetadata, error) {
+	req, err := http.NewRequest(http.MethodGet, server+OAuthMetadataPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body) // drain response body
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrOAuthNotSupported
+	}
+
+	var metadata OAuthMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return nil, ErrOAuthNotSupported
+	}
+	if metadata.AuthorizationEndpoint == "" {
+		return nil, ErrOAuthNotSupported
+	}
+
+	return &metadata, nil
+}
+
+// NewOAuthAuthenticator creates an authenticator using the OpenShift OAuth server
+func NewOAuthAuthenticator(config *Config) (Authenticator, error) {
+	config, err := normalizeConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	client := newHTTPClient(config)
+	metadata, err := discoverOAuthMetadata(client, config.Server)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOAuthAuthenticator(config, client, metadata), nil
+}
+
+func newOAuthAuthenticator(config *Config, client *http.Client, metadata *OAuthMetadata) *oauthAuthenticator {
+	// The token is returned in the redirect location, so redirects must not be followed
+	noRedirect := *client
+	noRedirect.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &oauthAuthenticator{
+		config:   config,
+		client:   &noRedirect,
+		metadata: metadata,
+	}
+}
+
+// DetectAuthenticator returns the OAuth authenticator when the server exposes
+// OAuth discovery metadata, and the JSON authenticator otherwise
+func DetectAuthenticator(config *Config) (Authenticator, error) {
+	config, err := normalizeConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	client := newHTTPClient(config)
+	metadata, err := discoverOAuthMetadata(client, config.Server)
+	if errors.Is(err, ErrOAuthNotSupported) {
+		return &httpAuthenticator{
+			config: config,
+			client: client,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newOAuthAuthenticator(config, client, metadata), nil
+}
+
+// Authenticate requests an access token using a basic auth challenge
+func (a *oauthAuthenticator) Authenticate(username, password string) (string, error) {
+	if username == "" || password == "" {
+		return "", ErrEmptyCredentials
+	}
+
+	authorizeURL, err := url.Parse(a.metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	query := authorizeURL.Query()
+	query.Set("response_type", "token")
+	query.Set("client_id", OpenShiftChallengingClientID)
+	authorizeURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, authorizeURL.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(username, password)
+	req.Header.Set(csrfTokenHeader, "1")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body) // drain response body
+		_ = resp.Body.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect:
+	case http.StatusUnauthorized:
+		return "", ErrInvalidCredentials
+	default:
+		return "", fmt.Errorf("authentication failed with status code: %d", resp.StatusCode)
+	}
+
+	return tokenFromLocation(resp.Header.Get("Location"))
+}
+
+// tokenFromLocation extracts the access token from the redirect URL fragment
+func tokenFromLocation(location string) (string, error) {
+	if location == "" {
+		return "", fmt.Errorf("missing redirect location in OAuth response")
+	}
+
+	redirectURL, err := url.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("invalid redirect location: %w", err)
+	}
+
+	fragment, err := url.ParseQuery(redirectURL.Fragment)
+	if err != nil {
+		return "", fmt.Errorf("invalid redirect fragment: %w", err)
+	}
+
+	if errCode := fragment.Get("error"); errCode != "" {
+		if desc := fragment.Get("error_description"); desc != "" {
+			return "", fmt.Errorf("authentication failed: %s: %s", errCode, desc)
+		}
+		return "", fmt.Errorf("authentication failed: %s", errCode)
+	}
+
+	token := fragment.Get("access_token")
+	if token == "" {
+		return "", ErrEmptyToken
+	}
+
+	return token, nil
+}
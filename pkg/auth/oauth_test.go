@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newOAuthServer creates a test server emulating the OpenShift OAuth server
+func newOAuthServer(t *testing.T, location string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case OAuthMetadataPath:
+			json.NewEncoder(w).Encode(OAuthMetadata{
+				Issuer:                server.URL,
+				AuthorizationEndpoint: server.URL + "/oauth/authorize",
+				TokenEndpoint:         server.URL + "/oauth/token",
+			})
+		case "/oauth/authorize":
+			if r.URL.Query().Get("response_type") != "token" {
+				t.Errorf("Expected response_type=token, got %s", r.URL.Query().Get("response_type"))
+			}
+			if r.URL.Query().Get("client_id") != OpenShiftChallengingClientID {
+				t.Errorf("Expected client_id=%s, got %s", OpenShiftChallengingClientID, r.URL.Query().Get("client_id"))
+			}
+			if r.Header.Get("X-CSRF-Token") == "" {
+				t.Error("Expected X-CSRF-Token header")
+			}
+
+			username, password, ok := r.BasicAuth()
+			if !ok || username != "testuser" || password != "testpass" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="openshift"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.Header().Set("Location", location)
+			w.WriteHeader(http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func TestOAuthAuthenticator_Authenticate(t *testing.T) {
+	tests := []struct {
+		name        string
+		username    string
+		password    string
+		location    string
+		wantToken   string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:      "successful authentication",
+			username:  "testuser",
+			password:  "testpass",
+			location:  "https://oauth.example.com/oauth/token/implicit#access_token=sha256~abc&expires_in=86400&token_type=Bearer",
+			wantToken: "sha256~abc",
+		},
+		{
+			name:        "invalid credentials",
+			username:    "testuser",
+			password:    "wrong",
+			wantErr:     true,
+			errContains: "invalid username or password",
+		},
+		{
+			name:        "empty credentials",
+			username:    "",
+			password:    "",
+			wantErr:     true,
+			errContains: "username and password are required",
+		},
+		{
+			name:        "error in redirect fragment",
+			username:    "testuser",
+			password:    "testpass",
+			location:    "https://oauth.example.com/oauth/token/implicit#error=access_denied&error_description=scope+denied",
+			wantErr:     true,
+			errContains: "access_denied: scope denied",
+		},
+		{
+			name:        "missing token in redirect fragment",
+			username:    "testuser",
+			password:    "testpass",
+			location:    "https://oauth.example.com/oauth/token/implicit#expires_in=86400",
+			wantErr:     true,
+			errContains: "received empty token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newOAuthServer(t, tt.location)
+			defer server.Close()
+
+			auth, err := NewOAuthAuthenticator(&Config{
+				Server:  server.URL,
+				Timeout: 5 * time.Second,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create authenticator: %v", err)
+			}
+
+			token, err := auth.Authenticate(tt.username, tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Authenticate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err != nil && tt.errContains != "" {
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("Authenticate() error = %v, want error containing %v", err, tt.errContains)
+				}
+			}
+			if !tt.wantErr && token != tt.wantToken {
+				t.Errorf("Authenticate() token = %v, want %v", token, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestDetectAuthenticator(t *testing.T) {
+	t.Run("uses OAuth when discovery document is present", func(t *testing.T) {
+		server := newOAuthServer(t, "")
+		defer server.Close()
+
+		auth, err := DetectAuthenticator(&Config{Server: server.URL})
+		if err != nil {
+			t.Fatalf("DetectAuthenticator() error = %v", err)
+		}
+		if _, ok := auth.(*oauthAuthenticator); !ok {
+			t.Errorf("DetectAuthenticator() = %T, want *oauthAuthenticator", auth)
+		}
+	})
+
+	t.Run("falls back to JSON authentication", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		auth, err := DetectAuthenticator(&Config{Server: server.URL})
+		if err != nil {
+			t.Fatalf("DetectAuthenticator() error = %v", err)
+		}
+		if _, ok := auth.(*httpAuthenticator); !ok {
+			t.Errorf("DetectAuthenticator() = %T, want *httpAuthenticator", auth)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// OAuthAccessTokensPath is the OpenShift endpoint holding OAuth access tokens
+	OAuthAccessTokensPath = "/apis/oauth.openshift.io/v1/oauthaccesstokens"
+
+	// sha256TokenPrefix marks tokens whose objects are named after their hash
+	sha256TokenPrefix = "sha256~"
+)
+
+// ErrRevocationNotSupported indicates the server offers no revocation endpoint
+var ErrRevocationNotSupported = errors.New("server does not support token revocation")
+
+// ErrTokenNotFound indicates the token is unknown to the server
+var ErrTokenNotFound = errors.New("token not found on server")
+
+// Revoker defines the interface for revoking access tokens
+type Revoker interface {
+	// Revoke invalidates the access token on the server
+	Revoke(token string) error
+}
+
+// RevokeRequest defines the token revocation request of the JSON backend
+type RevokeRequest struct {
+	Token string `json:"token"`
+}
+
+// Revoke calls the revocation endpoint of the JSON backend
+func (a *httpAuthenticator) Revoke(token string) error {
+	if token == "" {
+		return ErrEmptyBearerToken
+	}
+	if a.config.RevokePath == "" {
+		return ErrRevocationNotSupported
+	}
+
+	jsonData, err := json.Marshal(RevokeRequest{Token: token})
+	if err != nil {
+		return fmt.Errorf("failed to marshal revocation request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, a.config.Server+a.config.RevokePath, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	err = doRevokeRequest(a.client, req, token)
+	if errors.Is(err, errNotServed) {
+		return ErrRevocationNotSupported
+	}
+	return err
+}
+
+// Revoke deletes the OAuthAccessToken object backing the token
+func (a *oauthAuthenticator) Revoke(token string) error {
+	if token == "" {
+		return ErrEmptyBearerToken
+	}
+
+	tokenURL := a.config.Server + OAuthAccessTokensPath + "/" + url.PathEscape(oauthAccessTokenName(token))
+	req, err := http.NewRequest(http.MethodDelete, tokenURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	err = doRevokeRequest(a.client, req, token)
+	if errors.Is(err, errNotServed) {
+		return ErrTokenNotFound
+	}
+	return err
+}
+
+// oauthAccessTokenName returns the name of the OAuthAccessToken object for a token.
+// Tokens with the sha256~ prefix are stored under the hash of their secret part.
+func oauthAccessTokenName(token string) string {
+	if !strings.HasPrefix(token, sha256TokenPrefix) {
+		return token
+	}
+	sum := sha256.Sum256([]byte(strings.TrimPrefix(token, sha256TokenPrefix)))
+	return sha256TokenPrefix + base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// doRevokeRequest sends the revocation request authenticated with the token itself
+func doRevokeRequest(client *http.Client, req *http.Request, token string) error {
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body) // drain response body
+		_ = resp.Body.Close()
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return ErrInvalidToken
+	case resp.StatusCode == http.StatusNotFound:
+		return errNotServed
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("revocation failed with status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
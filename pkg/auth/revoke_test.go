@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOAuthAccessTokenName(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		expected string
+	}{
+		{
+			name:     "sha256 token",
+			token:    "sha256~secret",
+			expected: "sha256~K7gNU3sdo-OL0wNhqoVWhr3g6s1xYv72ol_pe_Unols",
+		},
+		{
+			name:     "legacy token",
+			token:    "legacy-token",
+			expected: "legacy-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := oauthAccessTokenName(tt.token); got != tt.expected {
+				t.Errorf("oauthAccessTokenName() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOAuthAuthenticator_Revoke(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       string
+		status      int
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:   "successful revocation",
+			token:  "sha256~secret",
+			status: http.StatusOK,
+		},
+		{
+			name:        "token already gone",
+			token:       "sha256~secret",
+			status:      http.StatusNotFound,
+			wantErr:     true,
+			errContains: "token not found",
+		},
+		{
+			name:        "empty token",
+			token:       "",
+			wantErr:     true,
+			errContains: "token is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newOAuthServer(t, "")
+			defer server.Close()
+			server.Config.Handler = withTokenDeletion(t, server.Config.Handler, tt.status)
+
+			auth, err := NewOAuthAuthenticator(&Config{Server: server.URL})
+			if err != nil {
+				t.Fatalf("Failed to create authenticator: %v", err)
+			}
+
+			err = auth.(Revoker).Revoke(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Revoke() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("Revoke() error = %v, want error containing %v", err, tt.errContains)
+			}
+		})
+	}
+}
+
+// withTokenDeletion serves OAuthAccessToken deletion with the given status
+func withTokenDeletion(t *testing.T, next http.Handler, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, OAuthAccessTokensPath+"/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.Method != http.MethodDelete {
+			t.Errorf("Expected DELETE request, got %s", r.Method)
+		}
+		if r.URL.Path != OAuthAccessTokensPath+"/"+oauthAccessTokenName("sha256~secret") {
+			t.Errorf("Unexpected token path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer sha256~secret" {
+			t.Errorf("Expected bearer token, got %s", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(status)
+	})
+}
+
+func TestHTTPAuthenticator_Revoke(t *testing.T) {
+	tests := []struct {
+		name        string
+		revokePath  string
+		status      int
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:       "successful revocation",
+			revokePath: "/auth/revoke",
+			status:     http.StatusNoContent,
+		},
+		{
+			name:       "custom revocation path",
+			revokePath: "logout",
+			status:     http.StatusOK,
+		},
+		{
+			name:        "revocation not supported",
+			revokePath:  "/auth/revoke",
+			status:      http.StatusNotFound,
+			wantErr:     true,
+			errContains: "does not support token revocation",
+		},
+		{
+			name:        "no revocation path",
+			revokePath:  "",
+			wantErr:     true,
+			errContains: "does not support token revocation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("Expected POST request, got %s", r.Method)
+				}
+				if strings.TrimPrefix(r.URL.Path, "/") != strings.TrimPrefix(tt.revokePath, "/") {
+					t.Errorf("Unexpected revocation path %s", r.URL.Path)
+				}
+
+				var req RevokeRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token != "valid-token" {
+					t.Errorf("Expected token in request body, got %+v (%v)", req, err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			auth, err := NewAuthenticator(&Config{
+				Server:     server.URL,
+				RevokePath: tt.revokePath,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create authenticator: %v", err)
+			}
+
+			err = auth.(Revoker).Revoke("valid-token")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Revoke() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("Revoke() error = %v, want error containing %v", err, tt.errContains)
+			}
+		})
+	}
+}
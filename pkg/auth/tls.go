@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidCAData indicates the certificate authority data holds no PEM certificates
+var ErrInvalidCAData = errors.New("no valid certificates found in certificate authority data")
+
+// UntrustedCertificateError indicates the server presented a certificate
+// signed by an unknown authority
+type UntrustedCertificateError struct {
+	// Certificate is the leaf certificate presented by the server
+	Certificate *x509.Certificate
+	// Err is the underlying verification error
+	Err error
+}
+
+// Error implements the error interface
+func (e *UntrustedCertificateError) Error() string {
+	return fmt.Sprintf("server certificate is not trusted: %v", e.Err)
+}
+
+// Unwrap returns the underlying verification error
+func (e *UntrustedCertificateError) Unwrap() error {
+	return e.Err
+}
+
+// Fingerprint returns the colon-separated SHA-256 fingerprint of the certificate
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// newTLSConfig creates the TLS client configuration for the authenticator. As
+// in client-go, a configured certificate authority is the only one trusted.
+func newTLSConfig(config *Config) *tls.Config {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	if len(config.CAData) > 0 && !config.InsecureSkipVerify {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(config.CAData)
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig
+}
+
+// validateCAData ensures the certificate authority data holds at least one certificate
+func validateCAData(caData []byte) error {
+	if len(caData) == 0 {
+		return nil
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(caData) {
+		return ErrInvalidCAData
+	}
+	return nil
+}
+
+// VerifyServerCertificate connects to the server and verifies its certificate
+// chain. An *UntrustedCertificateError is returned when the certificate is
+// signed by an unknown authority, so the caller can ask the user to trust it.
+func VerifyServerCertificate(config *Config) error {
+	config, err := normalizeConfig(config)
+	if err != nil {
+		return err
+	}
+
+	serverURL, err := url.Parse(config.Server)
+	if err != nil {
+		return fmt.Errorf("invalid server URL: %w", err)
+	}
+	if serverURL.Scheme != "https" || config.InsecureSkipVerify {
+		return nil
+	}
+
+	address := serverURL.Host
+	if serverURL.Port() == "" {
+		address = net.JoinHostPort(serverURL.Hostname(), "443")
+	}
+
+	dialer := &net.Dialer{Timeout: config.Timeout}
+	tlsConfig := newTLSConfig(config)
+	tlsConfig.ServerName = serverURL.Hostname()
+
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	if err == nil {
+		return conn.Close()
+	}
+
+	var verifyErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	if errors.As(err, &verifyErr) && errors.As(err, &unknownAuthority) && len(verifyErr.UnverifiedCertificates) > 0 {
+		return &UntrustedCertificateError{
+			Certificate: verifyErr.UnverifiedCertificates[0],
+			Err:         unknownAuthority,
+		}
+	}
+
+	return fmt.Errorf("failed to verify server certificate: %w", err)
+}
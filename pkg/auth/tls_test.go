@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyServerCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	tests := []struct {
+		name          string
+		config        *Config
+		wantUntrusted bool
+		wantErr       bool
+		errContains   string
+	}{
+		{
+			name:          "unknown authority",
+			config:        &Config{Server: server.URL, Timeout: 5 * time.Second},
+			wantUntrusted: true,
+			wantErr:       true,
+		},
+		{
+			name:   "trusted certificate authority",
+			config: &Config{Server: server.URL, Timeout: 5 * time.Second, CAData: caData},
+		},
+		{
+			name:   "insecure skip verify",
+			config: &Config{Server: server.URL, Timeout: 5 * time.Second, InsecureSkipVerify: true},
+		},
+		{
+			name:        "invalid certificate authority data",
+			config:      &Config{Server: server.URL, CAData: []byte("not a certificate")},
+			wantErr:     true,
+			errContains: "no valid certificates",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyServerCertificate(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyServerCertificate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("VerifyServerCertificate() error = %v, want error containing %v", err, tt.errContains)
+			}
+
+			var untrusted *UntrustedCertificateError
+			if errors.As(err, &untrusted) != tt.wantUntrusted {
+				t.Errorf("VerifyServerCertificate() error = %v, want untrusted %v", err, tt.wantUntrusted)
+			}
+			if tt.wantUntrusted && untrusted.Certificate == nil {
+				t.Error("VerifyServerCertificate() returned no certificate")
+			}
+		})
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	want := x509.NewCertPool()
+	want.AppendCertsFromPEM(caData)
+
+	// Only the configured certificate authority is trusted, not the system ones
+	tlsConfig := newTLSConfig(&Config{CAData: caData})
+	if tlsConfig.RootCAs == nil || !tlsConfig.RootCAs.Equal(want) {
+		t.Errorf("newTLSConfig() RootCAs = %v, want only the configured certificate authority", tlsConfig.RootCAs)
+	}
+
+	// The system certificate authorities are used when none is configured
+	if tlsConfig := newTLSConfig(&Config{}); tlsConfig.RootCAs != nil {
+		t.Errorf("newTLSConfig() RootCAs = %v, want nil", tlsConfig.RootCAs)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	cert := server.Certificate()
+	sum := sha256.Sum256(cert.Raw)
+	want := strings.ToUpper(fmt.Sprintf("%x", sum[:1]))
+
+	fingerprint := Fingerprint(cert)
+	if len(strings.Split(fingerprint, ":")) != sha256.Size {
+		t.Errorf("Fingerprint() = %v, want %d colon-separated bytes", fingerprint, sha256.Size)
+	}
+	if !strings.HasPrefix(fingerprint, want+":") {
+		t.Errorf("Fingerprint() = %v, want prefix %v", fingerprint, want)
+	}
+}
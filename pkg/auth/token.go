@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// CurrentUserPath is the OpenShift endpoint describing the current user
+	CurrentUserPath = "/apis/user.openshift.io/v1/users/~"
+
+	// SelfSubjectReviewPath is the Kubernetes endpoint reviewing the current subject
+	SelfSubjectReviewPath = "/apis/authentication.k8s.io/v1/selfsubjectreviews"
+
+	// selfSubjectReviewBetaPath is served by clusters older than Kubernetes 1.28
+	selfSubjectReviewBetaPath = "/apis/authentication.k8s.io/v1beta1/selfsubjectreviews"
+)
+
+// ErrInvalidToken indicates the server rejected the bearer token
+var ErrInvalidToken = errors.New("the provided token is invalid or has expired")
+
+// ErrEmptyBearerToken indicates an empty bearer token
+var ErrEmptyBearerToken = errors.New("token is required")
+
+// errNotServed indicates the requested API is not available on the server
+var errNotServed = errors.New("API not served")
+
+// UserInfo describes the identity a token belongs to
+type UserInfo struct {
+	Username string
+	UID      string
+	Groups   []string
+}
+
+// openshiftUser defines the subset of the OpenShift User object we need
+type openshiftUser struct {
+	Metadata struct {
+		Name string `json:"name"`
+		UID  string `json:"uid"`
+	} `json:"metadata"`
+	Groups []string `json:"groups"`
+}
+
+// selfSubjectReview defines the subset of the SelfSubjectReview object we need
+type selfSubjectReview struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Status     struct {
+		UserInfo struct {
+			Username string   `json:"username"`
+			UID      string   `json:"uid"`
+			Groups   []string `json:"groups"`
+		} `json:"userInfo"`
+	} `json:"status"`
+}
+
+// WhoAmI returns the identity the server sees for the REST config. Requests go
+// through the client-go transport, so every credential it supports and the
+// impersonation of the config are honored. The OpenShift user API is
+// preferred, falling back to SelfSubjectReview.
+func WhoAmI(ctx context.Context, config *rest.Config) (*UserInfo, error) {
+	client, err := newRESTHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := currentOpenShiftUser(ctx, client, config.Host)
+	if !errors.Is(err, errNotServed) {
+		return user, err
+	}
+
+	for _, path := range []string{SelfSubjectReviewPath, selfSubjectReviewBetaPath} {
+		user, err = reviewSelfSubject(ctx, client, config.Host, path)
+		if !errors.Is(err, errNotServed) {
+			return user, err
+		}
+	}
+
+	return nil, fmt.Errorf("server supports neither the OpenShift user API nor SelfSubjectReview")
+}
+
+// BearerToken returns the bearer token client-go sends for the REST config,
+// including the tokens of credential plugins and auth providers. The token is
+// read from a request that never leaves the process.
+func BearerToken(config *rest.Config) (string, error) {
+	config = rest.CopyConfig(config)
+	// Impersonation does not change the token, it only adds headers
+	config.Impersonate = rest.ImpersonationConfig{}
+	var token string
+	config.Wrap(func(http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if value, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); found {
+				token = value
+			}
+			return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Request: req}, nil
+		})
+	})
+
+	client, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodGet, config.Host, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get credentials: %w", err)
+	}
+	resp.Body.Close()
+
+	if token == "" {
+		return "", ErrEmptyBearerToken
+	}
+	return token, nil
+}
+
+// roundTripperFunc adapts a function to an http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newRESTHTTPClient returns an HTTP client authenticating as the REST config,
+// with the default timeout when the config has none
+func newRESTHTTPClient(config *rest.Config) (*http.Client, error) {
+	if config.Timeout == 0 {
+		config = rest.CopyConfig(config)
+		config.Timeout = DefaultConfig().Timeout
+	}
+	client, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+	return client, nil
+}
+
+func currentOpenShiftUser(ctx context.Context, client *http.Client, server string) (*UserInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server+CurrentUserPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var user openshiftUser
+	if err := doRequest(client, req, &user); err != nil {
+		return nil, err
+	}
+
+	return &UserInfo{
+		Username: user.Metadata.Name,
+		UID:      user.Metadata.UID,
+		Groups:   user.Groups,
+	}, nil
+}
+
+func reviewSelfSubject(ctx context.Context, client *http.Client, server, path string) (*UserInfo, error) {
+	// The path has the form /apis/<group>/<version>/selfsubjectreviews
+	review := selfSubjectReview{
+		APIVersion: strings.TrimSuffix(strings.TrimPrefix(path, "/apis/"), "/selfsubjectreviews"),
+		Kind:       "SelfSubjectReview",
+	}
+	jsonData, err := json.Marshal(review)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal review: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if err := doRequest(client, req, &review); err != nil {
+		return nil, err
+	}
+
+	return &UserInfo{
+		Username: review.Status.UserInfo.Username,
+		UID:      review.Status.UserInfo.UID,
+		Groups:   review.Status.UserInfo.Groups,
+	}, nil
+}
+
+// doRequest sends the request and decodes the JSON response, the client adds
+// the credentials
+func doRequest(client *http.Client, req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body) // drain response body
+		_ = resp.Body.Close()
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return ErrInvalidToken
+	case resp.StatusCode == http.StatusNotFound:
+		return errNotServed
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("request failed with status code: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestWhoAmI(t *testing.T) {
+	tests := []struct {
+		name         string
+		token        string
+		openshift    bool
+		impersonate  string
+		wantUsername string
+		wantErr      bool
+		errContains  string
+	}{
+		{
+			name:         "OpenShift user API",
+			token:        "valid-token",
+			openshift:    true,
+			wantUsername: "developer",
+		},
+		{
+			name:         "SelfSubjectReview fallback",
+			token:        "valid-token",
+			openshift:    false,
+			wantUsername: "system:serviceaccount:ci:deployer",
+		},
+		{
+			name:        "invalid token",
+			token:       "expired-token",
+			openshift:   true,
+			wantErr:     true,
+			errContains: "invalid or has expired",
+		},
+		{
+			name:        "no credentials",
+			token:       "",
+			openshift:   true,
+			wantErr:     true,
+			errContains: "invalid or has expired",
+		},
+		{
+			name:         "impersonated user",
+			token:        "valid-token",
+			openshift:    true,
+			impersonate:  "bob",
+			wantUsername: "bob",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer valid-token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				switch {
+				case r.URL.Path == CurrentUserPath && tt.openshift:
+					name := "developer"
+					if user := r.Header.Get("Impersonate-User"); user != "" {
+						name = user
+					}
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"metadata": map[string]string{"name": name},
+						"groups":   []string{"system:authenticated"},
+					})
+				case r.URL.Path == SelfSubjectReviewPath && !tt.openshift:
+					if r.Method != http.MethodPost {
+						t.Errorf("Expected POST request, got %s", r.Method)
+					}
+					var review selfSubjectReview
+					json.NewDecoder(r.Body).Decode(&review)
+					if review.APIVersion != "authentication.k8s.io/v1" {
+						t.Errorf("Expected apiVersion authentication.k8s.io/v1, got %s", review.APIVersion)
+					}
+					review.Status.UserInfo.Username = "system:serviceaccount:ci:deployer"
+					w.WriteHeader(http.StatusCreated)
+					json.NewEncoder(w).Encode(review)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			config := &rest.Config{Host: server.URL, BearerToken: tt.token}
+			config.Impersonate.UserName = tt.impersonate
+			user, err := WhoAmI(context.Background(), config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WhoAmI() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err != nil && tt.errContains != "" {
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("WhoAmI() error = %v, want error containing %v", err, tt.errContains)
+				}
+			}
+			if !tt.wantErr && user.Username != tt.wantUsername {
+				t.Errorf("WhoAmI() username = %v, want %v", user.Username, tt.wantUsername)
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		config    *rest.Config
+		wantToken string
+		wantErr   bool
+	}{
+		{
+			name:      "bearer token",
+			config:    &rest.Config{Host: "https://api.example.com:6443", BearerToken: "sha256~token"},
+			wantToken: "sha256~token",
+		},
+		{
+			name:      "token file",
+			config:    &rest.Config{Host: "https://api.example.com:6443", BearerTokenFile: tokenFile},
+			wantToken: "file-token",
+		},
+		{
+			name: "impersonating",
+			config: &rest.Config{
+				Host:        "https://api.example.com:6443",
+				BearerToken: "sha256~token",
+				Impersonate: rest.ImpersonationConfig{UserName: "bob"},
+			},
+			wantToken: "sha256~token",
+		},
+		{
+			name:    "no token",
+			config:  &rest.Config{Host: "https://api.example.com:6443", Username: "developer", Password: "secret"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := BearerToken(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BearerToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if token != tt.wantToken {
+				t.Errorf("BearerToken() = %v, want %v", token, tt.wantToken)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ConfigFlags holds the kubectl-compatible global flags overriding the kubeconfig
+// for a single invocation
+type ConfigFlags struct {
+	// KubeConfig is the path of the kubeconfig file to use instead of KUBECONFIG
+	KubeConfig string
+	// Context is the kubeconfig context to use instead of the current context
+	Context string
+	// Cluster is the kubeconfig cluster to use
+	Cluster string
+	// User is the kubeconfig user to use
+	User string
+	// Namespace is the namespace to use instead of the context namespace
+	Namespace string
+	// Server is the address of the API server
+	Server string
+	// Token is the bearer token for authentication to the API server
+	Token string
+	// Impersonate is the user the requests act as
+	Impersonate string
+	// ImpersonateGroups are the groups the requests act as, with Impersonate
+	ImpersonateGroups []string
+	// ImpersonateUID is the UID the requests act as, with Impersonate
+	ImpersonateUID string
+}
+
+// NewConfigFlags creates empty config flags
+func NewConfigFlags() *ConfigFlags {
+	return &ConfigFlags{}
+}
+
+// AddFlags binds the config flags to the flag set
+func (f *ConfigFlags) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.KubeConfig, "kubeconfig", f.KubeConfig, "Path to the kubeconfig file to use for CLI requests")
+	flags.StringVar(&f.Context, "context", f.Context, "The name of the kubeconfig context to use")
+	flags.StringVar(&f.Cluster, "cluster", f.Cluster, "The name of the kubeconfig cluster to use")
+	flags.StringVar(&f.User, "user", f.User, "The name of the kubeconfig user to use")
+	flags.StringVarP(&f.Namespace, "namespace", "n", f.Namespace, "If present, the namespace scope for this CLI request")
+	flags.StringVarP(&f.Server, "server", "s", f.Server, "The address and port of the Kubernetes API server")
+	flags.StringVar(&f.Token, "token", f.Token, "Bearer token for authentication to the API server")
+	flags.StringVar(&f.Impersonate, "as", f.Impersonate, "Username to impersonate for the operation")
+	flags.StringArrayVar(&f.ImpersonateGroups, "as-group", f.ImpersonateGroups, "Group to impersonate for the operation, this flag can be repeated to specify multiple groups")
+	flags.StringVar(&f.ImpersonateUID, "as-uid", f.ImpersonateUID, "UID to impersonate for the operation")
+}
+
+// ConfigAccess returns the access used to load and modify the kubeconfig files
+func (f *ConfigFlags) ConfigAccess() clientcmd.ConfigAccess {
+	pathOptions := clientcmd.NewDefaultPathOptions()
+	pathOptions.LoadingRules.ExplicitPath = f.KubeConfig
+	return pathOptions
+}
+
+// ToRawKubeConfigLoader returns the client config with the flag overrides applied
+func (f *ConfigFlags) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = f.KubeConfig
+
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: f.Context,
+		Context: api.Context{
+			Cluster:   f.Cluster,
+			AuthInfo:  f.User,
+			Namespace: f.Namespace,
+		},
+		ClusterInfo: api.Cluster{
+			Server: f.Server,
+		},
+		AuthInfo: api.AuthInfo{
+			Token:             f.Token,
+			Impersonate:       f.Impersonate,
+			ImpersonateGroups: f.ImpersonateGroups,
+			ImpersonateUID:    f.ImpersonateUID,
+		},
+	}
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+}
+
+// ToRESTConfig returns the REST config for API clients
+func (f *ConfigFlags) ToRESTConfig() (*rest.Config, error) {
+	config, err := f.ToRawKubeConfigLoader().ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build client configuration: %w", err)
+	}
+	return config, nil
+}
+
+// Validate rejects flag combinations that cannot be honored, such as groups
+// or a UID to impersonate without a user
+func (f *ConfigFlags) Validate() error {
+	if f.Impersonate == "" && (len(f.ImpersonateGroups) > 0 || f.ImpersonateUID != "") {
+		return fmt.Errorf("requesting uid or groups without impersonating a user, use --as with --as-group and --as-uid")
+	}
+	return nil
+}
+
+// ImpersonationConfig returns the impersonation requested by the flags, empty
+// when no user is impersonated
+func (f *ConfigFlags) ImpersonationConfig() rest.ImpersonationConfig {
+	if f.Impersonate == "" {
+		return rest.ImpersonationConfig{}
+	}
+	return rest.ImpersonationConfig{
+		UserName: f.Impersonate,
+		Groups:   f.ImpersonateGroups,
+		UID:      f.ImpersonateUID,
+	}
+}
+
+// ToNamespace returns the namespace for the request and whether it was set
+// explicitly by the flags or the context
+func (f *ConfigFlags) ToNamespace() (string, bool, error) {
+	return f.ToRawKubeConfigLoader().Namespace()
+}
+
+// CurrentContextName returns the name of the context in use
+func (f *ConfigFlags) CurrentContextName() (string, error) {
+	if f.Context != "" {
+		return f.Context, nil
+	}
+
+	rawConfig, err := f.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	return rawConfig.CurrentContext, nil
+}
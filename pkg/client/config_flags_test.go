@@ -0,0 +1,154 @@
+package client
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// writeTestKubeconfig writes a kubeconfig holding two contexts and returns its path
+func writeTestKubeconfig(t *testing.T) string {
+	t.Helper()
+
+	config := api.NewConfig()
+	config.Clusters["cluster-a"] = &api.Cluster{Server: "https://cluster-a.example.com:6443"}
+	config.Clusters["cluster-b"] = &api.Cluster{Server: "https://cluster-b.example.com:6443"}
+	config.AuthInfos["user-a"] = &api.AuthInfo{Token: "token-a"}
+	config.AuthInfos["user-b"] = &api.AuthInfo{Token: "token-b"}
+	config.Contexts["context-a"] = &api.Context{Cluster: "cluster-a", AuthInfo: "user-a", Namespace: "project-a"}
+	config.Contexts["context-b"] = &api.Context{Cluster: "cluster-b", AuthInfo: "user-b"}
+	config.CurrentContext = "context-a"
+
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, clientcmd.WriteToFile(*config, path))
+	return path
+}
+
+func TestConfigFlags(t *testing.T) {
+	kubeconfigPath := writeTestKubeconfig(t)
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "unused"))
+
+	tests := []struct {
+		name            string
+		args            []string
+		wantHost        string
+		wantToken       string
+		wantNamespace   string
+		wantContext     string
+		wantImpersonate rest.ImpersonationConfig
+	}{
+		{
+			name:          "current context",
+			args:          []string{"--kubeconfig", kubeconfigPath},
+			wantHost:      "https://cluster-a.example.com:6443",
+			wantToken:     "token-a",
+			wantNamespace: "project-a",
+			wantContext:   "context-a",
+		},
+		{
+			name:          "context override",
+			args:          []string{"--kubeconfig", kubeconfigPath, "--context", "context-b"},
+			wantHost:      "https://cluster-b.example.com:6443",
+			wantToken:     "token-b",
+			wantNamespace: "default",
+			wantContext:   "context-b",
+		},
+		{
+			name:          "server, token and namespace override",
+			args:          []string{"--kubeconfig", kubeconfigPath, "-s", "https://other.example.com", "--token", "other-token", "-n", "other"},
+			wantHost:      "https://other.example.com",
+			wantToken:     "other-token",
+			wantNamespace: "other",
+			wantContext:   "context-a",
+		},
+		{
+			name:          "cluster and user override",
+			args:          []string{"--kubeconfig", kubeconfigPath, "--cluster", "cluster-b", "--user", "user-b"},
+			wantHost:      "https://cluster-b.example.com:6443",
+			wantToken:     "token-b",
+			wantNamespace: "project-a",
+			wantContext:   "context-a",
+		},
+		{
+			name:          "impersonation",
+			args:          []string{"--kubeconfig", kubeconfigPath, "--as", "developer", "--as-group", "dev", "--as-group", "system:authenticated", "--as-uid", "1234"},
+			wantHost:      "https://cluster-a.example.com:6443",
+			wantToken:     "token-a",
+			wantNamespace: "project-a",
+			wantContext:   "context-a",
+			wantImpersonate: rest.ImpersonationConfig{
+				UserName: "developer",
+				Groups:   []string{"dev", "system:authenticated"},
+				UID:      "1234",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFlags := NewConfigFlags()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			configFlags.AddFlags(flags)
+			require.NoError(t, flags.Parse(tt.args))
+
+			restConfig, err := configFlags.ToRESTConfig()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantHost, restConfig.Host)
+			assert.Equal(t, tt.wantToken, restConfig.BearerToken)
+			assert.Equal(t, tt.wantImpersonate.UserName, restConfig.Impersonate.UserName)
+			assert.Equal(t, tt.wantImpersonate.Groups, restConfig.Impersonate.Groups)
+			assert.Equal(t, tt.wantImpersonate.UID, restConfig.Impersonate.UID)
+			assert.Equal(t, tt.wantImpersonate, configFlags.ImpersonationConfig())
+
+			namespace, _, err := configFlags.ToNamespace()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNamespace, namespace)
+
+			contextName, err := configFlags.CurrentContextName()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantContext, contextName)
+		})
+	}
+}
+
+func TestConfigFlags_ImpersonationWithoutUser(t *testing.T) {
+	kubeconfigPath := writeTestKubeconfig(t)
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "unused"))
+
+	configFlags := NewConfigFlags()
+	configFlags.KubeConfig = kubeconfigPath
+	configFlags.ImpersonateGroups = []string{"dev"}
+
+	_, err := configFlags.ToRESTConfig()
+	assert.Error(t, err)
+	assert.Equal(t, rest.ImpersonationConfig{}, configFlags.ImpersonationConfig())
+	assert.ErrorContains(t, configFlags.Validate(), "without impersonating a user")
+
+	configFlags.ImpersonateGroups = nil
+	configFlags.ImpersonateUID = "1234"
+	assert.ErrorContains(t, configFlags.Validate(), "without impersonating a user")
+
+	configFlags.Impersonate = "developer"
+	assert.NoError(t, configFlags.Validate())
+}
+
+func TestConfigFlags_ConfigAccess(t *testing.T) {
+	kubeconfigPath := writeTestKubeconfig(t)
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "unused"))
+
+	configFlags := NewConfigFlags()
+	configFlags.KubeConfig = kubeconfigPath
+
+	configAccess := configFlags.ConfigAccess()
+	assert.Equal(t, kubeconfigPath, configAccess.GetDefaultFilename())
+
+	config, err := configAccess.GetStartingConfig()
+	require.NoError(t, err)
+	assert.Len(t, config.Contexts, 2)
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+// TableAcceptHeader asks the server for a Table, falling back to plain JSON
+// when the server does not support server-side printing
+const TableAcceptHeader = "application/json;as=Table;v=v1;g=meta.k8s.io," +
+	"application/json;as=Table;v=v1beta1;g=meta.k8s.io," +
+	"application/json"
+
+// NewTableClient creates a dynamic client whose responses are server-side
+// Tables whenever the server supports them. The rows include the object
+// metadata, or the full objects with metav1.IncludeObject.
+func NewTableClient(config *rest.Config, includeObject metav1.IncludeObjectPolicy) (dynamic.Interface, error) {
+	config = rest.CopyConfig(config)
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &tableRoundTripper{includeObject: includeObject, next: rt}
+	})
+	return dynamic.NewForConfig(config)
+}
+
+// tableRoundTripper asks the server for Tables on every request
+type tableRoundTripper struct {
+	includeObject metav1.IncludeObjectPolicy
+	next          http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (rt *tableRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept", TableAcceptHeader)
+	if rt.includeObject != "" {
+		query := req.URL.Query()
+		query.Set("includeObject", string(rt.includeObject))
+		req.URL.RawQuery = query.Encode()
+	}
+	return rt.next.RoundTrip(req)
+}
+
+// AsTable converts an object returned by a table client into a Table. It
+// reports false when the server returned plain objects instead.
+func AsTable(obj map[string]interface{}) (*metav1.Table, bool, error) {
+	if obj["kind"] != "Table" {
+		return nil, false, nil
+	}
+
+	table := &metav1.Table{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, table); err != nil {
+		return nil, false, fmt.Errorf("failed to decode table: %w", err)
+	}
+	return table, true, nil
+}
@@ -0,0 +1,52 @@
+package describe
+
+import (
+	"context"
+	"encoding/base64"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// describeConfigMap writes the data and binary data keys of a config map
+func describeConfigMap(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured, w *prefixWriter) error {
+	describeMeta(obj, w)
+
+	w.Write(0, "\nData\n====\n")
+	data := nestedMap(obj.Object, "data")
+	for _, key := range sortedKeys(data) {
+		w.Write(0, "%s:\n----\n", key)
+		w.Write(0, "%s\n", nestedString(data, key))
+	}
+
+	w.Write(0, "\nBinaryData\n====\n")
+	binaryData := nestedMap(obj.Object, "binaryData")
+	for _, key := range sortedKeys(binaryData) {
+		w.Write(0, "%s: %d bytes\n", key, decodedSize(nestedString(binaryData, key)))
+	}
+	w.Write(0, "\n")
+	return nil
+}
+
+// describeSecret writes the type and the size of every key of a secret without
+// revealing the values
+func describeSecret(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured, w *prefixWriter) error {
+	describeMeta(obj, w)
+	w.Write(0, "\nType:\t%s\n", valueOr(nestedString(obj.Object, "type"), "Opaque"))
+
+	w.Write(0, "\nData\n====\n")
+	data := nestedMap(obj.Object, "data")
+	for _, key := range sortedKeys(data) {
+		w.Write(0, "%s:\t%d bytes\n", key, decodedSize(nestedString(data, key)))
+	}
+	return nil
+}
+
+// decodedSize returns the number of bytes of a base64 value
+func decodedSize(value string) int {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return len(value)
+	}
+	return len(decoded)
+}
@@ -0,0 +1,108 @@
+package describe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// describeDeployment writes the replicas, strategy, pod template, conditions
+// and replica sets of a deployment
+func describeDeployment(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured, w *prefixWriter) error {
+	deployment := obj.Object
+
+	describeMeta(obj, w)
+	selector := formatSelector(nestedMap(deployment, "spec", "selector"))
+	w.Write(0, "Selector:\t%s\n", selector)
+
+	desired, ok := nestedInt(deployment, "spec", "replicas")
+	if !ok {
+		desired = 1
+	}
+	updated, _ := nestedInt(deployment, "status", "updatedReplicas")
+	total, _ := nestedInt(deployment, "status", "replicas")
+	available, _ := nestedInt(deployment, "status", "availableReplicas")
+	unavailable, _ := nestedInt(deployment, "status", "unavailableReplicas")
+	w.Write(0, "Replicas:\t%d desired | %d updated | %d total | %d available | %d unavailable\n",
+		desired, updated, total, available, unavailable)
+
+	strategy := valueOr(nestedString(deployment, "spec", "strategy", "type"), "RollingUpdate")
+	w.Write(0, "StrategyType:\t%s\n", strategy)
+	minReady, _ := nestedInt(deployment, "spec", "minReadySeconds")
+	w.Write(0, "MinReadySeconds:\t%d\n", minReady)
+	if strategy == "RollingUpdate" {
+		w.Write(0, "RollingUpdateStrategy:\t%s max unavailable, %s max surge\n",
+			valueOr(nestedString(deployment, "spec", "strategy", "rollingUpdate", "maxUnavailable"), "25%"),
+			valueOr(nestedString(deployment, "spec", "strategy", "rollingUpdate", "maxSurge"), "25%"))
+	}
+
+	describePodTemplate(nestedMap(deployment, "spec", "template"), w)
+	describeConditions(nestedSlice(deployment, "status", "conditions"), w)
+
+	replicaSets, err := client.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}).
+		Namespace(obj.GetNamespace()).
+		List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err == nil {
+		var owned []string
+		for i := range replicaSets.Items {
+			rs := &replicaSets.Items[i]
+			if controller := metav1.GetControllerOf(rs); controller == nil || controller.UID != obj.GetUID() {
+				continue
+			}
+			replicas, _ := nestedInt(rs.Object, "spec", "replicas")
+			created, _ := nestedInt(rs.Object, "status", "replicas")
+			owned = append(owned, fmt.Sprintf("%s (%d/%d replicas created)", rs.GetName(), created, replicas))
+		}
+		w.Write(0, "ReplicaSets:\t%s\n", valueOr(strings.Join(owned, ", "), "<none>"))
+	}
+	return nil
+}
+
+// describeConditions writes the type, status and reason of status conditions
+func describeConditions(conditions []map[string]interface{}, w *prefixWriter) {
+	if len(conditions) == 0 {
+		return
+	}
+	w.Write(0, "Conditions:\n")
+	w.Write(1, "Type\tStatus\tReason\n")
+	w.Write(1, "----\t------\t------\n")
+	for _, condition := range conditions {
+		w.Write(1, "%s \t%s\t%s\n",
+			nestedString(condition, "type"),
+			nestedString(condition, "status"),
+			nestedString(condition, "reason"))
+	}
+}
+
+// formatSelector renders a label selector with match labels and expressions
+func formatSelector(selector map[string]interface{}) string {
+	if selector == nil {
+		return "<none>"
+	}
+	labelSelector := &metav1.LabelSelector{}
+	if err := fromUnstructured(selector, labelSelector); err != nil {
+		return "<error>"
+	}
+	parsed, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return "<error>"
+	}
+	if parsed.Empty() {
+		return "<none>"
+	}
+	return parsed.String()
+}
+
+// formatLabels renders a map selector such as a service selector
+func formatLabels(m map[string]string) string {
+	if len(m) == 0 {
+		return "<none>"
+	}
+	return labels.SelectorFromSet(m).String()
+}
@@ -0,0 +1,429 @@
+package describe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/withlin/oc-demo/pkg/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/dynamic"
+)
+
+// now returns the current time, replaced in tests for stable ages
+var now = time.Now
+
+// timeFormat is the format of the timestamps in descriptions
+const timeFormat = time.RFC1123Z
+
+// describeFunc writes the description of an object, fetching related objects
+// with the client when needed
+type describeFunc func(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured, w *prefixWriter) error
+
+// describers maps the kinds with a custom description to their describer
+var describers = map[schema.GroupKind]describeFunc{
+	{Kind: "Pod"}:                                describePod,
+	{Kind: "Service"}:                            describeService,
+	{Kind: "Node"}:                               describeNode,
+	{Kind: "ConfigMap"}:                          describeConfigMap,
+	{Kind: "Secret"}:                             describeSecret,
+	{Group: "apps", Kind: "Deployment"}:          describeDeployment,
+	{Group: "route.openshift.io", Kind: "Route"}: describeRoute,
+	{Group: "apps.openshift.io", Kind: "DeploymentConfig"}: describeDeploymentConfig,
+	{Group: "project.openshift.io", Kind: "Project"}:       describeProject,
+	{Group: "build.openshift.io", Kind: "BuildConfig"}:     describeBuildConfig,
+}
+
+// Describe returns the human-readable description of an object followed by its
+// events. Kinds without a custom describer get a generic description of all
+// their fields.
+func Describe(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured) (string, error) {
+	describer, exists := describers[obj.GroupVersionKind().GroupKind()]
+	if !exists {
+		describer = describeGeneric
+	}
+
+	return tabbedString(func(out io.Writer) error {
+		w := &prefixWriter{out: out}
+		if err := describer(ctx, client, obj, w); err != nil {
+			return err
+		}
+		describeEvents(ctx, client, obj, w)
+		return nil
+	})
+}
+
+// tabbedString runs the function on a tab writer and returns the aligned output
+func tabbedString(f func(io.Writer) error) (string, error) {
+	var buf bytes.Buffer
+	out := tabwriter.NewWriter(&buf, 0, 8, 2, ' ', 0)
+	if err := f(out); err != nil {
+		return "", err
+	}
+	if err := out.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// prefixWriter writes lines indented by two spaces per level
+type prefixWriter struct {
+	out io.Writer
+}
+
+// Write writes a formatted line at the indentation level
+func (w *prefixWriter) Write(level int, format string, args ...interface{}) {
+	fmt.Fprintf(w.out, strings.Repeat("  ", level)+format, args...)
+}
+
+// describeMeta writes the name, namespace, labels and annotations of an object
+func describeMeta(obj *unstructured.Unstructured, w *prefixWriter) {
+	w.Write(0, "Name:\t%s\n", obj.GetName())
+	if obj.GetNamespace() != "" {
+		w.Write(0, "Namespace:\t%s\n", obj.GetNamespace())
+	}
+	if created := obj.GetCreationTimestamp(); !created.IsZero() {
+		w.Write(0, "CreationTimestamp:\t%s\n", created.Format(timeFormat))
+	}
+	writeMap(0, "Labels", obj.GetLabels(), w)
+	writeMap(0, "Annotations", describedAnnotations(obj), w)
+}
+
+// describedAnnotations returns the annotations of an object without the last
+// applied configuration, which repeats the object including Secret data
+func describedAnnotations(obj *unstructured.Unstructured) map[string]string {
+	annotations := map[string]string{}
+	for key, value := range obj.GetAnnotations() {
+		if key != resource.LastAppliedAnnotation {
+			annotations[key] = value
+		}
+	}
+	return annotations
+}
+
+// writeMap writes sorted key=value pairs, one per line, or <none>
+func writeMap(level int, title string, m map[string]string, w *prefixWriter) {
+	w.Write(level, "%s:\t", title)
+	if len(m) == 0 {
+		w.Write(0, "<none>\n")
+		return
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		if i > 0 {
+			w.Write(level, "\t")
+		}
+		w.Write(0, "%s=%s\n", key, m[key])
+	}
+}
+
+// describeControlledBy writes the controller owning the object, if any
+func describeControlledBy(obj *unstructured.Unstructured, w *prefixWriter) {
+	if controller := metav1.GetControllerOf(obj); controller != nil {
+		w.Write(0, "Controlled By:\t%s/%s\n", controller.Kind, controller.Name)
+	}
+}
+
+// describeEvents writes the events about the object, newest last. Failures to
+// list events are ignored so the description is still shown.
+func describeEvents(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured, w *prefixWriter) {
+	selectors := []fields.Selector{
+		fields.OneTermEqualSelector("involvedObject.kind", obj.GetKind()),
+		fields.OneTermEqualSelector("involvedObject.name", obj.GetName()),
+	}
+	if obj.GetNamespace() != "" {
+		selectors = append(selectors, fields.OneTermEqualSelector("involvedObject.namespace", obj.GetNamespace()))
+	}
+	if obj.GetUID() != "" {
+		selectors = append(selectors, fields.OneTermEqualSelector("involvedObject.uid", string(obj.GetUID())))
+	}
+
+	events, err := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "events"}).
+		Namespace(obj.GetNamespace()).
+		List(ctx, metav1.ListOptions{FieldSelector: fields.AndSelectors(selectors...).String()})
+	if err != nil {
+		return
+	}
+
+	if len(events.Items) == 0 {
+		w.Write(0, "Events:\t<none>\n")
+		return
+	}
+
+	items := events.Items
+	sort.SliceStable(items, func(i, j int) bool {
+		return eventTime(&items[i]).Before(eventTime(&items[j]))
+	})
+
+	w.Write(0, "Events:\n")
+	w.Write(1, "Type\tReason\tAge\tFrom\tMessage\n")
+	w.Write(1, "----\t------\t----\t----\t-------\n")
+	for i := range items {
+		event := &items[i]
+		age := "<unknown>"
+		if last := eventTime(event); !last.IsZero() {
+			age = translateTimestampSince(last)
+			count, _ := nestedInt(event.Object, "count")
+			first := timestamp(event.Object, "firstTimestamp")
+			if count > 1 && !first.IsZero() {
+				age = fmt.Sprintf("%s (x%d over %s)", age, count, translateTimestampSince(first))
+			}
+		}
+
+		from := nestedString(event.Object, "source", "component")
+		if from == "" {
+			from = nestedString(event.Object, "reportingComponent")
+		}
+		if host := nestedString(event.Object, "source", "host"); host != "" {
+			from += ", " + host
+		}
+
+		w.Write(1, "%s\t%s\t%s\t%s\t%s\n",
+			nestedString(event.Object, "type"),
+			nestedString(event.Object, "reason"),
+			age,
+			from,
+			strings.TrimSpace(nestedString(event.Object, "message")))
+	}
+}
+
+// eventTime returns the last time the event was observed
+func eventTime(event *unstructured.Unstructured) time.Time {
+	for _, field := range []string{"lastTimestamp", "eventTime", "firstTimestamp"} {
+		if t := timestamp(event.Object, field); !t.IsZero() {
+			return t
+		}
+	}
+	return event.GetCreationTimestamp().Time
+}
+
+// describeGeneric writes the metadata of an object followed by all its other
+// fields, for kinds without a custom describer
+func describeGeneric(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured, w *prefixWriter) error {
+	describeMeta(obj, w)
+	w.Write(0, "API Version:\t%s\n", obj.GetAPIVersion())
+	w.Write(0, "Kind:\t%s\n", obj.GetKind())
+
+	keys := sortedKeys(obj.Object)
+	for _, key := range keys {
+		switch key {
+		case "apiVersion", "kind":
+			continue
+		case "metadata":
+			metadata := nestedMap(obj.Object, "metadata")
+			remaining := map[string]interface{}{}
+			for k, v := range metadata {
+				switch k {
+				case "name", "namespace", "labels", "annotations", "managedFields":
+				default:
+					remaining[k] = v
+				}
+			}
+			if len(remaining) > 0 {
+				w.Write(0, "Metadata:\n")
+				writeValue(1, remaining, w)
+			}
+		default:
+			writeField(0, key, obj.Object[key], w)
+		}
+	}
+	return nil
+}
+
+// writeField writes a field of an unstructured object, nesting maps and lists
+func writeField(level int, key string, value interface{}, w *prefixWriter) {
+	title := fieldTitle(key)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			w.Write(level, "%s:\t<none>\n", title)
+			return
+		}
+		w.Write(level, "%s:\n", title)
+		writeValue(level+1, v, w)
+	case []interface{}:
+		if len(v) == 0 {
+			w.Write(level, "%s:\t<none>\n", title)
+			return
+		}
+		w.Write(level, "%s:\n", title)
+		writeValue(level+1, v, w)
+	default:
+		w.Write(level, "%s:\t%v\n", title, formatScalar(v))
+	}
+}
+
+// writeValue writes the fields of a map or the items of a list
+func writeValue(level int, value interface{}, w *prefixWriter) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			writeField(level, key, v[key], w)
+		}
+	case []interface{}:
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				writeValue(level, item, w)
+			default:
+				w.Write(level, "%v\n", formatScalar(item))
+			}
+		}
+	}
+}
+
+// fieldTitle turns a camelCase field name into a Title Case heading
+func fieldTitle(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		if i == 0 {
+			b.WriteString(strings.ToUpper(string(r)))
+			continue
+		}
+		if r >= 'A' && r <= 'Z' && key[i-1] >= 'a' && key[i-1] <= 'z' {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// formatScalar renders JSON scalars, printing whole numbers without decimals
+func formatScalar(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "<nil>"
+	case float64:
+		if v == float64(int64(v)) {
+			return fmt.Sprintf("%d", int64(v))
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// sortedKeys returns the keys of the map in order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// nestedString returns the string at the path, or an empty string
+func nestedString(obj map[string]interface{}, fields ...string) string {
+	value, _, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return formatScalar(v)
+	}
+}
+
+// nestedInt returns the integer at the path
+func nestedInt(obj map[string]interface{}, fields ...string) (int64, bool) {
+	value, _, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// nestedBool returns the boolean at the path
+func nestedBool(obj map[string]interface{}, fields ...string) bool {
+	value, _, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	b, _ := value.(bool)
+	return b
+}
+
+// nestedMap returns the map at the path, or nil
+func nestedMap(obj map[string]interface{}, fields ...string) map[string]interface{} {
+	value, _, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	m, _ := value.(map[string]interface{})
+	return m
+}
+
+// nestedSlice returns the maps of the list at the path, skipping other items
+func nestedSlice(obj map[string]interface{}, fields ...string) []map[string]interface{} {
+	value, _, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	list, _ := value.([]interface{})
+	var result []map[string]interface{}
+	for _, item := range list {
+		if m, ok := item.(map[string]interface{}); ok {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// nestedStringMap returns the map of strings at the path
+func nestedStringMap(obj map[string]interface{}, fields ...string) map[string]string {
+	result := map[string]string{}
+	for key, value := range nestedMap(obj, fields...) {
+		result[key] = formatScalar(value)
+	}
+	return result
+}
+
+// timestamp returns the RFC 3339 time at the path, or the zero time
+func timestamp(obj map[string]interface{}, fields ...string) time.Time {
+	t, err := time.Parse(time.RFC3339, nestedString(obj, fields...))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// formatTimestamp renders the time at the path, or <unset>
+func formatTimestamp(obj map[string]interface{}, fields ...string) string {
+	t := timestamp(obj, fields...)
+	if t.IsZero() {
+		return "<unset>"
+	}
+	return t.Format(timeFormat)
+}
+
+// translateTimestampSince returns the human-readable time elapsed since t
+func translateTimestampSince(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(now().Sub(t))
+}
+
+// fromUnstructured converts an unstructured map into a typed object
+func fromUnstructured(obj map[string]interface{}, into interface{}) error {
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(obj, into)
+}
+
+// valueOr returns the value, or the fallback when empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
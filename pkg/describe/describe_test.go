@@ -0,0 +1,548 @@
+package describe
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/withlin/oc-demo/pkg/resource"
+	"github.com/withlin/oc-demo/pkg/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// testObjects are the objects described in the tests and their related objects
+var testObjects = []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web-5d8f-abcde
+  namespace: shop
+  uid: pod-uid
+  creationTimestamp: "2024-01-01T07:00:00Z"
+  labels:
+    app: web
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: ReplicaSet
+    name: web-5d8f
+    uid: rs-uid
+    controller: true
+spec:
+  nodeName: worker-1
+  serviceAccountName: default
+  containers:
+  - name: nginx
+    image: nginx:1.25
+    ports:
+    - containerPort: 8080
+    env:
+    - name: MODE
+      value: production
+    - name: PASSWORD
+      valueFrom:
+        secretKeyRef:
+          name: credentials
+          key: password
+    resources:
+      requests:
+        cpu: 100m
+        memory: 64Mi
+    volumeMounts:
+    - name: config
+      mountPath: /etc/nginx
+      readOnly: true
+  volumes:
+  - name: config
+    configMap:
+      name: nginx-config
+status:
+  phase: Running
+  hostIP: 10.0.0.1
+  podIP: 10.128.0.5
+  startTime: "2024-01-01T07:00:00Z"
+  conditions:
+  - type: Ready
+    status: "True"
+  containerStatuses:
+  - name: nginx
+    containerID: cri-o://1234
+    imageID: nginx@sha256:abcd
+    ready: true
+    restartCount: 2
+    state:
+      running:
+        startedAt: "2024-01-01T08:00:00Z"
+    lastState:
+      terminated:
+        reason: Error
+        exitCode: 1
+        startedAt: "2024-01-01T07:00:00Z"
+        finishedAt: "2024-01-01T08:00:00Z"
+`, `
+apiVersion: v1
+kind: Event
+metadata:
+  name: web-5d8f-abcde.1
+  namespace: shop
+involvedObject:
+  kind: Pod
+  name: web-5d8f-abcde
+  namespace: shop
+  uid: pod-uid
+type: Warning
+reason: BackOff
+message: Back-off restarting failed container
+source:
+  component: kubelet
+  host: worker-1
+count: 3
+firstTimestamp: "2024-01-01T09:00:00Z"
+lastTimestamp: "2024-01-01T11:00:00Z"
+`, `
+apiVersion: v1
+kind: Event
+metadata:
+  name: web-5d8f-abcde.0
+  namespace: shop
+involvedObject:
+  kind: Pod
+  name: web-5d8f-abcde
+  namespace: shop
+  uid: pod-uid
+type: Normal
+reason: Scheduled
+message: Successfully assigned shop/web-5d8f-abcde to worker-1
+source:
+  component: default-scheduler
+lastTimestamp: "2024-01-01T07:00:00Z"
+`, `
+apiVersion: v1
+kind: Event
+metadata:
+  name: other.0
+  namespace: shop
+involvedObject:
+  kind: Pod
+  name: other
+  namespace: shop
+type: Normal
+reason: Scheduled
+message: Successfully assigned shop/other to worker-1
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+  uid: deploy-uid
+  creationTimestamp: "2024-01-01T07:00:00Z"
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: web
+  strategy:
+    type: RollingUpdate
+    rollingUpdate:
+      maxUnavailable: 1
+      maxSurge: 25%
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.25
+status:
+  replicas: 2
+  updatedReplicas: 2
+  availableReplicas: 1
+  unavailableReplicas: 1
+  conditions:
+  - type: Available
+    status: "False"
+    reason: MinimumReplicasUnavailable
+`, `
+apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: web-5d8f
+  namespace: shop
+  uid: rs-uid
+  labels:
+    app: web
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: Deployment
+    name: web
+    uid: deploy-uid
+    controller: true
+spec:
+  replicas: 2
+status:
+  replicas: 2
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: shop
+  creationTimestamp: "2024-01-01T07:00:00Z"
+spec:
+  selector:
+    app: web
+  clusterIP: 172.30.0.10
+  ports:
+  - name: http
+    port: 80
+    targetPort: 8080
+`, `
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: web
+  namespace: shop
+subsets:
+- addresses:
+  - ip: 10.128.0.5
+  - ip: 10.128.0.6
+  ports:
+  - name: http
+    port: 8080
+`, `
+apiVersion: v1
+kind: Node
+metadata:
+  name: worker-1
+  creationTimestamp: "2024-01-01T07:00:00Z"
+  labels:
+    node-role.kubernetes.io/worker: ""
+spec:
+  taints:
+  - key: dedicated
+    value: web
+    effect: NoSchedule
+status:
+  addresses:
+  - type: InternalIP
+    address: 10.0.0.1
+  capacity:
+    cpu: "4"
+    memory: 16Gi
+  conditions:
+  - type: Ready
+    status: "True"
+    reason: KubeletReady
+    message: kubelet is posting ready status
+    lastHeartbeatTime: "2024-01-01T11:59:00Z"
+    lastTransitionTime: "2024-01-01T07:00:00Z"
+`, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: nginx-config
+  namespace: shop
+  creationTimestamp: "2024-01-01T07:00:00Z"
+data:
+  nginx.conf: |-
+    worker_processes 1;
+binaryData:
+  logo.png: iVBORw0KGgo=
+`, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+  namespace: shop
+  creationTimestamp: "2024-01-01T07:00:00Z"
+  annotations:
+    description: Database credentials
+    kubectl.kubernetes.io/last-applied-configuration: |
+      {"apiVersion":"v1","data":{"password":"c2VjcmV0LXZhbHVl","username":"YWRtaW4="},"kind":"Secret","metadata":{"name":"credentials","namespace":"shop"},"type":"Opaque"}
+type: Opaque
+data:
+  password: c2VjcmV0LXZhbHVl
+  username: YWRtaW4=
+`, `
+apiVersion: route.openshift.io/v1
+kind: Route
+metadata:
+  name: web
+  namespace: shop
+  creationTimestamp: "2024-01-01T07:00:00Z"
+spec:
+  host: web-shop.apps.example.com
+  to:
+    kind: Service
+    name: web
+    weight: 100
+  port:
+    targetPort: http
+  tls:
+    termination: edge
+    insecureEdgeTerminationPolicy: Redirect
+status:
+  ingress:
+  - host: web-shop.apps.example.com
+    routerName: default
+    conditions:
+    - type: Admitted
+      status: "True"
+`, `
+apiVersion: apps.openshift.io/v1
+kind: DeploymentConfig
+metadata:
+  name: api
+  namespace: shop
+  creationTimestamp: "2024-01-01T07:00:00Z"
+spec:
+  replicas: 1
+  selector:
+    app: api
+  strategy:
+    type: Rolling
+  triggers:
+  - type: ConfigChange
+  - type: ImageChange
+    imageChangeParams:
+      automatic: true
+      from:
+        kind: ImageStreamTag
+        name: api:latest
+  template:
+    metadata:
+      labels:
+        app: api
+    spec:
+      containers:
+      - name: api
+        image: api:latest
+status:
+  latestVersion: 3
+`, `
+apiVersion: v1
+kind: ReplicationController
+metadata:
+  name: api-3
+  namespace: shop
+  creationTimestamp: "2024-01-01T10:00:00Z"
+  annotations:
+    openshift.io/deployment.phase: Complete
+spec:
+  replicas: 1
+  selector:
+    app: api
+    deployment: api-3
+status:
+  replicas: 1
+  readyReplicas: 1
+`, `
+apiVersion: project.openshift.io/v1
+kind: Project
+metadata:
+  name: shop
+  creationTimestamp: "2024-01-01T07:00:00Z"
+  annotations:
+    openshift.io/display-name: Web Shop
+    openshift.io/description: The online shop
+status:
+  phase: Active
+`, `
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: compute
+  namespace: shop
+status:
+  hard:
+    pods: "10"
+  used:
+    pods: "3"
+`, `
+apiVersion: build.openshift.io/v1
+kind: BuildConfig
+metadata:
+  name: api
+  namespace: shop
+  creationTimestamp: "2024-01-01T07:00:00Z"
+spec:
+  source:
+    git:
+      uri: https://github.com/example/api.git
+      ref: main
+  strategy:
+    type: Source
+    sourceStrategy:
+      from:
+        kind: ImageStreamTag
+        name: golang:1.21
+  output:
+    to:
+      kind: ImageStreamTag
+      name: api:latest
+  triggers:
+  - type: ConfigChange
+  - type: GitHub
+status:
+  lastVersion: 2
+`, `
+apiVersion: build.openshift.io/v1
+kind: Build
+metadata:
+  name: api-1
+  namespace: shop
+  creationTimestamp: "2024-01-01T08:00:00Z"
+  labels:
+    openshift.io/build-config.name: api
+status:
+  phase: Failed
+  startTimestamp: "2024-01-01T08:00:00Z"
+  completionTimestamp: "2024-01-01T08:01:30Z"
+`, `
+apiVersion: build.openshift.io/v1
+kind: Build
+metadata:
+  name: api-2
+  namespace: shop
+  creationTimestamp: "2024-01-01T09:00:00Z"
+  labels:
+    openshift.io/build-config.name: api
+status:
+  phase: Complete
+  startTimestamp: "2024-01-01T09:00:00Z"
+  completionTimestamp: "2024-01-01T09:02:00Z"
+`, `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: gadget
+  namespace: shop
+  creationTimestamp: "2024-01-01T07:00:00Z"
+  labels:
+    size: large
+spec:
+  color: blue
+  replicas: 3
+  parts:
+  - name: gear
+    count: 2
+`}
+
+// newTestClient starts a fake API server holding the test objects and returns
+// a dynamic client for it
+func newTestClient(t *testing.T) dynamic.Interface {
+	t.Helper()
+
+	server := testutil.NewFakeAPIServer()
+	t.Cleanup(server.Close)
+	server.AddResource("v1", metav1.APIResource{Name: "replicationcontrollers", SingularName: "replicationcontroller", Kind: "ReplicationController", Namespaced: true})
+	server.AddResource("v1", metav1.APIResource{Name: "resourcequotas", SingularName: "resourcequota", Kind: "ResourceQuota", Namespaced: true})
+	server.AddResource("v1", metav1.APIResource{Name: "limitranges", SingularName: "limitrange", Kind: "LimitRange", Namespaced: true})
+	server.AddResource("route.openshift.io/v1", metav1.APIResource{Name: "routes", SingularName: "route", Kind: "Route", Namespaced: true})
+	server.AddResource("apps.openshift.io/v1", metav1.APIResource{Name: "deploymentconfigs", SingularName: "deploymentconfig", Kind: "DeploymentConfig", Namespaced: true})
+	server.AddResource("project.openshift.io/v1", metav1.APIResource{Name: "projects", SingularName: "project", Kind: "Project"})
+	server.AddResource("build.openshift.io/v1", metav1.APIResource{Name: "buildconfigs", SingularName: "buildconfig", Kind: "BuildConfig", Namespaced: true})
+	server.AddResource("build.openshift.io/v1", metav1.APIResource{Name: "builds", SingularName: "build", Kind: "Build", Namespaced: true})
+	server.AddResource("example.com/v1", metav1.APIResource{Name: "widgets", SingularName: "widget", Kind: "Widget", Namespaced: true})
+	for _, manifest := range testObjects {
+		server.AddYAML(manifest)
+	}
+
+	config, err := clientcmd.NewDefaultClientConfig(*server.Kubeconfig("shop"), nil).ClientConfig()
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	// Descriptions fetch several related objects, avoid client-side throttling
+	config.QPS, config.Burst = 100, 100
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestDescribe(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+	client := newTestClient(t)
+
+	tests := []struct {
+		golden    string
+		resource  schema.GroupVersionResource
+		namespace string
+		name      string
+	}{
+		{golden: "pod", resource: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, namespace: "shop", name: "web-5d8f-abcde"},
+		{golden: "deployment", resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, namespace: "shop", name: "web"},
+		{golden: "service", resource: schema.GroupVersionResource{Version: "v1", Resource: "services"}, namespace: "shop", name: "web"},
+		{golden: "node", resource: schema.GroupVersionResource{Version: "v1", Resource: "nodes"}, name: "worker-1"},
+		{golden: "configmap", resource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, namespace: "shop", name: "nginx-config"},
+		{golden: "secret", resource: schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, namespace: "shop", name: "credentials"},
+		{golden: "route", resource: schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}, namespace: "shop", name: "web"},
+		{golden: "deploymentconfig", resource: schema.GroupVersionResource{Group: "apps.openshift.io", Version: "v1", Resource: "deploymentconfigs"}, namespace: "shop", name: "api"},
+		{golden: "project", resource: schema.GroupVersionResource{Group: "project.openshift.io", Version: "v1", Resource: "projects"}, name: "shop"},
+		{golden: "buildconfig", resource: schema.GroupVersionResource{Group: "build.openshift.io", Version: "v1", Resource: "buildconfigs"}, namespace: "shop", name: "api"},
+		{golden: "generic", resource: schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}, namespace: "shop", name: "gadget"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			obj, err := client.Resource(tt.resource).Namespace(tt.namespace).Get(context.Background(), tt.name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Failed to get object: %v", err)
+			}
+
+			description, err := Describe(context.Background(), client, obj)
+			if err != nil {
+				t.Fatalf("Describe() error = %v", err)
+			}
+
+			goldenFile := filepath.Join("testdata", tt.golden+".golden")
+			if *update {
+				if err := os.WriteFile(goldenFile, []byte(description), 0644); err != nil {
+					t.Fatalf("Failed to update golden file: %v", err)
+				}
+			}
+			expected, err := os.ReadFile(goldenFile)
+			if err != nil {
+				t.Fatalf("Failed to read golden file: %v", err)
+			}
+			if description != string(expected) {
+				t.Errorf("Describe() =\n%s\nwant\n%s", description, expected)
+			}
+		})
+	}
+}
+
+func TestDescribeSecretRedaction(t *testing.T) {
+	client := newTestClient(t)
+	secret, err := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "secrets"}).
+		Namespace("shop").
+		Get(context.Background(), "credentials", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get secret: %v", err)
+	}
+
+	description, err := Describe(context.Background(), client, secret)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	for _, value := range []string{"secret-value", "c2VjcmV0LXZhbHVl", "admin", "YWRtaW4=", resource.LastAppliedAnnotation} {
+		if strings.Contains(description, value) {
+			t.Errorf("Describe() revealed %q:\n%s", value, description)
+		}
+	}
+	if !strings.Contains(description, "password:  12 bytes") {
+		t.Errorf("Describe() does not show the size of the password:\n%s", description)
+	}
+}
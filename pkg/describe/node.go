@@ -0,0 +1,133 @@
+package describe
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// nodeRolePrefix is the prefix of the labels holding the roles of a node
+const nodeRolePrefix = "node-role.kubernetes.io/"
+
+// describeNode writes the roles, taints, conditions, capacity and pods of a node
+func describeNode(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured, w *prefixWriter) error {
+	node := obj.Object
+
+	var roles []string
+	for label := range obj.GetLabels() {
+		if strings.HasPrefix(label, nodeRolePrefix) {
+			roles = append(roles, strings.TrimPrefix(label, nodeRolePrefix))
+		}
+	}
+	sort.Strings(roles)
+
+	w.Write(0, "Name:\t%s\n", obj.GetName())
+	w.Write(0, "Roles:\t%s\n", valueOr(strings.Join(roles, ","), "<none>"))
+	writeMap(0, "Labels", obj.GetLabels(), w)
+	writeMap(0, "Annotations", describedAnnotations(obj), w)
+	w.Write(0, "CreationTimestamp:\t%s\n", obj.GetCreationTimestamp().Format(timeFormat))
+
+	taints := nestedSlice(node, "spec", "taints")
+	if len(taints) == 0 {
+		w.Write(0, "Taints:\t<none>\n")
+	}
+	for i, taint := range taints {
+		line := nestedString(taint, "key")
+		if value := nestedString(taint, "value"); value != "" {
+			line += "=" + value
+		}
+		line += ":" + nestedString(taint, "effect")
+		if i == 0 {
+			w.Write(0, "Taints:\t%s\n", line)
+			continue
+		}
+		w.Write(0, "\t%s\n", line)
+	}
+	w.Write(0, "Unschedulable:\t%v\n", nestedBool(node, "spec", "unschedulable"))
+
+	if conditions := nestedSlice(node, "status", "conditions"); len(conditions) > 0 {
+		w.Write(0, "Conditions:\n")
+		w.Write(1, "Type\tStatus\tLastHeartbeatTime\tLastTransitionTime\tReason\tMessage\n")
+		w.Write(1, "----\t------\t-----------------\t------------------\t------\t-------\n")
+		for _, condition := range conditions {
+			w.Write(1, "%s \t%s \t%s \t%s \t%s \t%s\n",
+				nestedString(condition, "type"),
+				nestedString(condition, "status"),
+				formatTimestamp(condition, "lastHeartbeatTime"),
+				formatTimestamp(condition, "lastTransitionTime"),
+				nestedString(condition, "reason"),
+				nestedString(condition, "message"))
+		}
+	}
+
+	if addresses := nestedSlice(node, "status", "addresses"); len(addresses) > 0 {
+		w.Write(0, "Addresses:\n")
+		for _, address := range addresses {
+			w.Write(1, "%s:\t%s\n", nestedString(address, "type"), nestedString(address, "address"))
+		}
+	}
+	describeResourceList("Capacity", nestedMap(node, "status", "capacity"), w, 0)
+	describeResourceList("Allocatable", nestedMap(node, "status", "allocatable"), w, 0)
+
+	if info := nestedMap(node, "status", "nodeInfo"); len(info) > 0 {
+		w.Write(0, "System Info:\n")
+		for _, field := range []struct{ title, key string }{
+			{"Machine ID", "machineID"},
+			{"System UUID", "systemUUID"},
+			{"Boot ID", "bootID"},
+			{"Kernel Version", "kernelVersion"},
+			{"OS Image", "osImage"},
+			{"Operating System", "operatingSystem"},
+			{"Architecture", "architecture"},
+			{"Container Runtime Version", "containerRuntimeVersion"},
+			{"Kubelet Version", "kubeletVersion"},
+			{"Kube-Proxy Version", "kubeProxyVersion"},
+		} {
+			w.Write(1, "%s:\t%s\n", field.title, nestedString(info, field.key))
+		}
+	}
+
+	selector := fields.AndSelectors(
+		fields.OneTermEqualSelector("spec.nodeName", obj.GetName()),
+		fields.OneTermNotEqualSelector("status.phase", "Succeeded"),
+		fields.OneTermNotEqualSelector("status.phase", "Failed"),
+	)
+	pods, err := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).
+		List(ctx, metav1.ListOptions{FieldSelector: selector.String()})
+	if err != nil {
+		return nil
+	}
+
+	w.Write(0, "Non-terminated Pods:\t(%d in total)\n", len(pods.Items))
+	w.Write(1, "Namespace\tName\tCPU Requests\tMemory Requests\tAge\n")
+	w.Write(1, "---------\t----\t------------\t---------------\t---\n")
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		w.Write(1, "%s\t%s\t%s\t%s\t%s\n",
+			pod.GetNamespace(),
+			pod.GetName(),
+			podRequests(pod, "cpu"),
+			podRequests(pod, "memory"),
+			translateTimestampSince(pod.GetCreationTimestamp().Time))
+	}
+	return nil
+}
+
+// podRequests returns the sum of the requests of a resource by the containers of a pod
+func podRequests(pod *unstructured.Unstructured, name string) string {
+	total := resource.Quantity{}
+	for _, container := range nestedSlice(pod.Object, "spec", "containers") {
+		request, err := resource.ParseQuantity(nestedString(container, "resources", "requests", name))
+		if err == nil {
+			total.Add(request)
+		}
+	}
+	return total.String()
+}
@@ -0,0 +1,262 @@
+package describe
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// displayNameAnnotation holds the human-readable name of a project
+	displayNameAnnotation = "openshift.io/display-name"
+	// descriptionAnnotation holds the description of a project
+	descriptionAnnotation = "openshift.io/description"
+	// nodeSelectorAnnotation holds the default node selector of a project
+	nodeSelectorAnnotation = "openshift.io/node-selector"
+	// buildConfigLabel links builds to their build config
+	buildConfigLabel = "openshift.io/build-config.name"
+	// maxBuilds is the number of recent builds listed for a build config
+	maxBuilds = 5
+)
+
+// describeDeploymentConfig writes the triggers, strategy, pod template and the
+// latest rollout of a deployment config
+func describeDeploymentConfig(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured, w *prefixWriter) error {
+	dc := obj.Object
+
+	describeMeta(obj, w)
+	latest, _ := nestedInt(dc, "status", "latestVersion")
+	w.Write(0, "Latest Version:\t%s\n", valueOr(formatVersion(latest), "Not deployed"))
+	w.Write(0, "Selector:\t%s\n", formatLabels(nestedStringMap(dc, "spec", "selector")))
+	replicas, _ := nestedInt(dc, "spec", "replicas")
+	w.Write(0, "Replicas:\t%d\n", replicas)
+
+	var triggers []string
+	for _, trigger := range nestedSlice(dc, "spec", "triggers") {
+		switch nestedString(trigger, "type") {
+		case "ConfigChange":
+			triggers = append(triggers, "Config")
+		case "ImageChange":
+			triggers = append(triggers, "Image("+nestedString(trigger, "imageChangeParams", "from", "name")+
+				", auto="+fmt.Sprint(nestedBool(trigger, "imageChangeParams", "automatic"))+")")
+		default:
+			triggers = append(triggers, nestedString(trigger, "type"))
+		}
+	}
+	w.Write(0, "Triggers:\t%s\n", valueOr(strings.Join(triggers, ", "), "<none>"))
+	w.Write(0, "Strategy:\t%s\n", valueOr(nestedString(dc, "spec", "strategy", "type"), "Rolling"))
+
+	describePodTemplate(nestedMap(dc, "spec", "template"), w)
+
+	if latest == 0 {
+		w.Write(0, "\nLatest Deployment:\t<none>\n")
+		return nil
+	}
+	name := fmt.Sprintf("%s-%d", obj.GetName(), latest)
+	rc, err := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "replicationcontrollers"}).
+		Namespace(obj.GetNamespace()).
+		Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		w.Write(0, "\nLatest Deployment:\t%s\n", name)
+		return nil
+	}
+
+	w.Write(0, "\nDeployment #%d (latest):\n", latest)
+	w.Write(1, "Name:\t%s\n", rc.GetName())
+	w.Write(1, "Created:\t%s ago\n", translateTimestampSince(rc.GetCreationTimestamp().Time))
+	w.Write(1, "Status:\t%s\n", valueOr(rc.GetAnnotations()["openshift.io/deployment.phase"], "Unknown"))
+	current, _ := nestedInt(rc.Object, "status", "replicas")
+	desired, _ := nestedInt(rc.Object, "spec", "replicas")
+	ready, _ := nestedInt(rc.Object, "status", "readyReplicas")
+	w.Write(1, "Replicas:\t%d current / %d desired\n", current, desired)
+	w.Write(1, "Selector:\t%s\n", formatLabels(nestedStringMap(rc.Object, "spec", "selector")))
+	w.Write(1, "Pods Status:\t%d Running\n", ready)
+	return nil
+}
+
+// describeProject writes the display name, description, status, node selector,
+// quotas and limit ranges of a project
+func describeProject(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured, w *prefixWriter) error {
+	annotations := obj.GetAnnotations()
+
+	w.Write(0, "Name:\t%s\n", obj.GetName())
+	w.Write(0, "Created:\t%s ago\n", translateTimestampSince(obj.GetCreationTimestamp().Time))
+	writeMap(0, "Labels", obj.GetLabels(), w)
+	writeMap(0, "Annotations", describedAnnotations(obj), w)
+	w.Write(0, "Display Name:\t%s\n", valueOr(annotations[displayNameAnnotation], "<none>"))
+	w.Write(0, "Description:\t%s\n", valueOr(annotations[descriptionAnnotation], "<none>"))
+	w.Write(0, "Status:\t%s\n", valueOr(nestedString(obj.Object, "status", "phase"), "Unknown"))
+	w.Write(0, "Node Selector:\t%s\n", valueOr(annotations[nodeSelectorAnnotation], "<none>"))
+
+	quotas, err := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "resourcequotas"}).
+		Namespace(obj.GetName()).
+		List(ctx, metav1.ListOptions{})
+	if err == nil {
+		if len(quotas.Items) == 0 {
+			w.Write(0, "Quota:\t<none>\n")
+		} else {
+			w.Write(0, "Quota:\n")
+		}
+		for i := range quotas.Items {
+			quota := &quotas.Items[i]
+			w.Write(1, "Name:\t%s\n", quota.GetName())
+			w.Write(1, "Resource\tUsed\tHard\n")
+			w.Write(1, "--------\t----\t----\n")
+			hard := nestedMap(quota.Object, "status", "hard")
+			used := nestedMap(quota.Object, "status", "used")
+			for _, name := range sortedKeys(hard) {
+				w.Write(1, "%s\t%s\t%s\n", name, formatScalar(valueOrZero(used[name])), formatScalar(hard[name]))
+			}
+		}
+	}
+
+	limits, err := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "limitranges"}).
+		Namespace(obj.GetName()).
+		List(ctx, metav1.ListOptions{})
+	if err == nil {
+		if len(limits.Items) == 0 {
+			w.Write(0, "Resource limits:\t<none>\n")
+		} else {
+			w.Write(0, "Resource limits:\n")
+		}
+		for i := range limits.Items {
+			limit := &limits.Items[i]
+			w.Write(1, "Name:\t%s\n", limit.GetName())
+			w.Write(1, "Type\tResource\tMin\tMax\tDefault\n")
+			w.Write(1, "----\t--------\t---\t---\t-------\n")
+			for _, item := range nestedSlice(limit.Object, "spec", "limits") {
+				resources := map[string]bool{}
+				for _, field := range []string{"min", "max", "default"} {
+					for name := range nestedMap(item, field) {
+						resources[name] = true
+					}
+				}
+				names := make([]string, 0, len(resources))
+				for name := range resources {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					w.Write(1, "%s\t%s\t%s\t%s\t%s\n",
+						nestedString(item, "type"),
+						name,
+						valueOr(nestedString(item, "min", name), "-"),
+						valueOr(nestedString(item, "max", name), "-"),
+						valueOr(nestedString(item, "default", name), "-"))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// describeBuildConfig writes the strategy, source, output, triggers and recent
+// builds of a build config
+func describeBuildConfig(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured, w *prefixWriter) error {
+	bc := obj.Object
+
+	describeMeta(obj, w)
+	latest, _ := nestedInt(bc, "status", "lastVersion")
+	w.Write(0, "Latest Version:\t%s\n", valueOr(formatVersion(latest), "Never built"))
+	w.Write(0, "\n")
+
+	strategy := nestedString(bc, "spec", "strategy", "type")
+	w.Write(0, "Strategy:\t%s\n", strategy)
+	if uri := nestedString(bc, "spec", "source", "git", "uri"); uri != "" {
+		w.Write(0, "URL:\t%s\n", uri)
+		if ref := nestedString(bc, "spec", "source", "git", "ref"); ref != "" {
+			w.Write(0, "Ref:\t%s\n", ref)
+		}
+	}
+	if contextDir := nestedString(bc, "spec", "source", "contextDir"); contextDir != "" {
+		w.Write(0, "ContextDir:\t%s\n", contextDir)
+	}
+	strategyField := map[string]string{
+		"Source":   "sourceStrategy",
+		"Docker":   "dockerStrategy",
+		"Custom":   "customStrategy",
+		"Pipeline": "jenkinsPipelineStrategy",
+	}[strategy]
+	if from := nestedMap(bc, "spec", "strategy", strategyField, "from"); from != nil {
+		w.Write(0, "From Image:\t%s %s\n", nestedString(from, "kind"), nestedString(from, "name"))
+	}
+	if to := nestedMap(bc, "spec", "output", "to"); to != nil {
+		w.Write(0, "Output to:\t%s %s\n", nestedString(to, "kind"), nestedString(to, "name"))
+	} else {
+		w.Write(0, "Output to:\t<none>\n")
+	}
+	w.Write(0, "\n")
+
+	var triggers []string
+	for _, trigger := range nestedSlice(bc, "spec", "triggers") {
+		switch nestedString(trigger, "type") {
+		case "ConfigChange":
+			triggers = append(triggers, "Config")
+		case "ImageChange":
+			triggers = append(triggers, "Image")
+		case "GitHub", "GitLab", "Bitbucket", "Generic":
+			triggers = append(triggers, "Webhook "+nestedString(trigger, "type"))
+		default:
+			triggers = append(triggers, nestedString(trigger, "type"))
+		}
+	}
+	w.Write(0, "Triggered by:\t%s\n", valueOr(strings.Join(triggers, ", "), "<none>"))
+
+	builds, err := client.Resource(schema.GroupVersionResource{Group: "build.openshift.io", Version: "v1", Resource: "builds"}).
+		Namespace(obj.GetNamespace()).
+		List(ctx, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(labels.Set{buildConfigLabel: obj.GetName()}).String()})
+	if err != nil || len(builds.Items) == 0 {
+		w.Write(0, "Builds History:\t<none>\n")
+		return nil
+	}
+
+	items := builds.Items
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].GetCreationTimestamp().After(items[j].GetCreationTimestamp().Time)
+	})
+	if len(items) > maxBuilds {
+		items = items[:maxBuilds]
+	}
+
+	w.Write(0, "\nBuild\tStatus\tDuration\tCreation Time\n")
+	for i := range items {
+		build := &items[i]
+		buildDuration := "waiting"
+		if started := timestamp(build.Object, "status", "startTimestamp"); !started.IsZero() {
+			completed := timestamp(build.Object, "status", "completionTimestamp")
+			if completed.IsZero() {
+				completed = now()
+			}
+			buildDuration = completed.Sub(started).String()
+		}
+		w.Write(0, "%s \t%s \t%s \t%s\n",
+			build.GetName(),
+			strings.ToLower(nestedString(build.Object, "status", "phase")),
+			buildDuration,
+			build.GetCreationTimestamp().Format(timeFormat))
+	}
+	return nil
+}
+
+// formatVersion renders a positive version number, or an empty string
+func formatVersion(version int64) string {
+	if version <= 0 {
+		return ""
+	}
+	return fmt.Sprint(version)
+}
+
+// valueOrZero returns the value, or zero when it is missing
+func valueOrZero(value interface{}) interface{} {
+	if value == nil {
+		return 0
+	}
+	return value
+}
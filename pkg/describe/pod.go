@@ -0,0 +1,300 @@
+package describe
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// describePod writes the status, containers, conditions and volumes of a pod
+func describePod(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured, w *prefixWriter) error {
+	pod := obj.Object
+
+	w.Write(0, "Name:\t%s\n", obj.GetName())
+	w.Write(0, "Namespace:\t%s\n", obj.GetNamespace())
+	if priority, ok := nestedInt(pod, "spec", "priority"); ok {
+		w.Write(0, "Priority:\t%d\n", priority)
+	}
+	if serviceAccount := nestedString(pod, "spec", "serviceAccountName"); serviceAccount != "" {
+		w.Write(0, "Service Account:\t%s\n", serviceAccount)
+	}
+	node := nestedString(pod, "spec", "nodeName")
+	if hostIP := nestedString(pod, "status", "hostIP"); node != "" && hostIP != "" {
+		node += "/" + hostIP
+	}
+	w.Write(0, "Node:\t%s\n", valueOr(node, "<none>"))
+	if !timestamp(pod, "status", "startTime").IsZero() {
+		w.Write(0, "Start Time:\t%s\n", formatTimestamp(pod, "status", "startTime"))
+	}
+	writeMap(0, "Labels", obj.GetLabels(), w)
+	writeMap(0, "Annotations", describedAnnotations(obj), w)
+
+	status := nestedString(pod, "status", "phase")
+	if obj.GetDeletionTimestamp() != nil {
+		status = "Terminating (lasts " + translateTimestampSince(obj.GetDeletionTimestamp().Time) + ")"
+	}
+	w.Write(0, "Status:\t%s\n", valueOr(status, "Unknown"))
+	if reason := nestedString(pod, "status", "reason"); reason != "" {
+		w.Write(0, "Reason:\t%s\n", reason)
+	}
+	if message := nestedString(pod, "status", "message"); message != "" {
+		w.Write(0, "Message:\t%s\n", message)
+	}
+	w.Write(0, "IP:\t%s\n", nestedString(pod, "status", "podIP"))
+	describeControlledBy(obj, w)
+
+	statuses := map[string]map[string]interface{}{}
+	for _, status := range nestedSlice(pod, "status", "initContainerStatuses") {
+		statuses[nestedString(status, "name")] = status
+	}
+	for _, status := range nestedSlice(pod, "status", "containerStatuses") {
+		statuses[nestedString(status, "name")] = status
+	}
+	if initContainers := nestedSlice(pod, "spec", "initContainers"); len(initContainers) > 0 {
+		describeContainers("Init Containers", initContainers, statuses, w, 0)
+	}
+	describeContainers("Containers", nestedSlice(pod, "spec", "containers"), statuses, w, 0)
+
+	if conditions := nestedSlice(pod, "status", "conditions"); len(conditions) > 0 {
+		w.Write(0, "Conditions:\n")
+		w.Write(1, "Type\tStatus\n")
+		for _, condition := range conditions {
+			w.Write(1, "%s \t%s \n", nestedString(condition, "type"), nestedString(condition, "status"))
+		}
+	}
+
+	describeVolumes(nestedSlice(pod, "spec", "volumes"), w, 0)
+	writeMap(0, "Node-Selectors", nestedStringMap(pod, "spec", "nodeSelector"), w)
+	describeTolerations(nestedSlice(pod, "spec", "tolerations"), w)
+	return nil
+}
+
+// describeContainers writes the containers of a pod or pod template. Statuses
+// are only known for pods and may be empty.
+func describeContainers(title string, containers []map[string]interface{}, statuses map[string]map[string]interface{}, w *prefixWriter, level int) {
+	w.Write(level, "%s:\n", title)
+	if len(containers) == 0 {
+		w.Write(level+1, "<none>\n")
+		return
+	}
+
+	for _, container := range containers {
+		name := nestedString(container, "name")
+		status := statuses[name]
+
+		w.Write(level+1, "%s:\n", name)
+		if status != nil {
+			w.Write(level+2, "Container ID:\t%s\n", nestedString(status, "containerID"))
+		}
+		w.Write(level+2, "Image:\t%s\n", nestedString(container, "image"))
+		if status != nil {
+			w.Write(level+2, "Image ID:\t%s\n", nestedString(status, "imageID"))
+		}
+
+		var ports []string
+		for _, port := range nestedSlice(container, "ports") {
+			containerPort, _ := nestedInt(port, "containerPort")
+			ports = append(ports, fmt.Sprintf("%d/%s", containerPort, valueOr(nestedString(port, "protocol"), "TCP")))
+		}
+		w.Write(level+2, "Port:\t%s\n", valueOr(strings.Join(ports, ", "), "<none>"))
+
+		if command := stringList(container, "command"); len(command) > 0 {
+			w.Write(level+2, "Command:\n")
+			for _, arg := range command {
+				w.Write(level+3, "%s\n", arg)
+			}
+		}
+		if args := stringList(container, "args"); len(args) > 0 {
+			w.Write(level+2, "Args:\n")
+			for _, arg := range args {
+				w.Write(level+3, "%s\n", arg)
+			}
+		}
+
+		if status != nil {
+			describeContainerState("State", nestedMap(status, "state"), w, level+2)
+			if lastState := nestedMap(status, "lastState"); len(lastState) > 0 {
+				describeContainerState("Last State", lastState, w, level+2)
+			}
+			ready := "False"
+			if nestedBool(status, "ready") {
+				ready = "True"
+			}
+			restarts, _ := nestedInt(status, "restartCount")
+			w.Write(level+2, "Ready:\t%s\n", ready)
+			w.Write(level+2, "Restart Count:\t%d\n", restarts)
+		}
+
+		describeResourceList("Limits", nestedMap(container, "resources", "limits"), w, level+2)
+		describeResourceList("Requests", nestedMap(container, "resources", "requests"), w, level+2)
+
+		env := nestedSlice(container, "env")
+		if len(env) == 0 {
+			w.Write(level+2, "Environment:\t<none>\n")
+		} else {
+			w.Write(level+2, "Environment:\n")
+			for _, variable := range env {
+				w.Write(level+3, "%s:\t%s\n", nestedString(variable, "name"), envValue(variable))
+			}
+		}
+
+		mounts := nestedSlice(container, "volumeMounts")
+		if len(mounts) == 0 {
+			w.Write(level+2, "Mounts:\t<none>\n")
+		} else {
+			w.Write(level+2, "Mounts:\n")
+			for _, mount := range mounts {
+				mode := "rw"
+				if nestedBool(mount, "readOnly") {
+					mode = "ro"
+				}
+				w.Write(level+3, "%s from %s (%s)\n", nestedString(mount, "mountPath"), nestedString(mount, "name"), mode)
+			}
+		}
+	}
+}
+
+// describeContainerState writes the running, waiting or terminated state
+func describeContainerState(title string, state map[string]interface{}, w *prefixWriter, level int) {
+	switch {
+	case state["running"] != nil:
+		w.Write(level, "%s:\tRunning\n", title)
+		w.Write(level+1, "Started:\t%s\n", formatTimestamp(state, "running", "startedAt"))
+	case state["waiting"] != nil:
+		w.Write(level, "%s:\tWaiting\n", title)
+		if reason := nestedString(state, "waiting", "reason"); reason != "" {
+			w.Write(level+1, "Reason:\t%s\n", reason)
+		}
+	case state["terminated"] != nil:
+		w.Write(level, "%s:\tTerminated\n", title)
+		if reason := nestedString(state, "terminated", "reason"); reason != "" {
+			w.Write(level+1, "Reason:\t%s\n", reason)
+		}
+		exitCode, _ := nestedInt(state, "terminated", "exitCode")
+		w.Write(level+1, "Exit Code:\t%d\n", exitCode)
+		w.Write(level+1, "Started:\t%s\n", formatTimestamp(state, "terminated", "startedAt"))
+		w.Write(level+1, "Finished:\t%s\n", formatTimestamp(state, "terminated", "finishedAt"))
+	default:
+		w.Write(level, "%s:\tWaiting\n", title)
+	}
+}
+
+// describeResourceList writes the resource limits or requests of a container
+func describeResourceList(title string, resources map[string]interface{}, w *prefixWriter, level int) {
+	if len(resources) == 0 {
+		return
+	}
+	w.Write(level, "%s:\n", title)
+	for _, name := range sortedKeys(resources) {
+		w.Write(level+1, "%s:\t%s\n", name, formatScalar(resources[name]))
+	}
+}
+
+// envValue renders an environment variable value or its source
+func envValue(variable map[string]interface{}) string {
+	if value, exists := variable["value"]; exists {
+		return formatScalar(value)
+	}
+	switch {
+	case nestedMap(variable, "valueFrom", "secretKeyRef") != nil:
+		return fmt.Sprintf("<set to the key '%s' in secret '%s'>",
+			nestedString(variable, "valueFrom", "secretKeyRef", "key"),
+			nestedString(variable, "valueFrom", "secretKeyRef", "name"))
+	case nestedMap(variable, "valueFrom", "configMapKeyRef") != nil:
+		return fmt.Sprintf("<set to the key '%s' of config map '%s'>",
+			nestedString(variable, "valueFrom", "configMapKeyRef", "key"),
+			nestedString(variable, "valueFrom", "configMapKeyRef", "name"))
+	case nestedMap(variable, "valueFrom", "fieldRef") != nil:
+		return fmt.Sprintf("(%s:%s)",
+			nestedString(variable, "valueFrom", "fieldRef", "apiVersion"),
+			nestedString(variable, "valueFrom", "fieldRef", "fieldPath"))
+	}
+	return ""
+}
+
+// describeVolumes writes the volumes of a pod or pod template with their source
+func describeVolumes(volumes []map[string]interface{}, w *prefixWriter, level int) {
+	if len(volumes) == 0 {
+		w.Write(level, "Volumes:\t<none>\n")
+		return
+	}
+
+	w.Write(level, "Volumes:\n")
+	for _, volume := range volumes {
+		w.Write(level+1, "%s:\n", nestedString(volume, "name"))
+		for _, source := range sortedKeys(volume) {
+			if source == "name" {
+				continue
+			}
+			w.Write(level+2, "Type:\t%s\n", source)
+			fields := nestedMap(volume, source)
+			for _, key := range sortedKeys(fields) {
+				switch value := fields[key].(type) {
+				case map[string]interface{}, []interface{}:
+					continue
+				default:
+					w.Write(level+2, "%s:\t%s\n", fieldTitle(key), formatScalar(value))
+				}
+			}
+		}
+	}
+}
+
+// describeTolerations writes the tolerations of a pod
+func describeTolerations(tolerations []map[string]interface{}, w *prefixWriter) {
+	if len(tolerations) == 0 {
+		w.Write(0, "Tolerations:\t<none>\n")
+		return
+	}
+
+	var lines []string
+	for _, toleration := range tolerations {
+		line := nestedString(toleration, "key")
+		if value := nestedString(toleration, "value"); value != "" {
+			line += "=" + value
+		}
+		if effect := nestedString(toleration, "effect"); effect != "" {
+			line += ":" + effect
+		}
+		if nestedString(toleration, "operator") == "Exists" && nestedString(toleration, "key") == "" {
+			line = "op=Exists"
+		}
+		if seconds, ok := nestedInt(toleration, "tolerationSeconds"); ok {
+			line += fmt.Sprintf(" for %ds", seconds)
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+
+	for i, line := range lines {
+		if i == 0 {
+			w.Write(0, "Tolerations:\t%s\n", line)
+			continue
+		}
+		w.Write(0, "\t%s\n", line)
+	}
+}
+
+// describePodTemplate writes the labels, containers and volumes of a pod template
+func describePodTemplate(template map[string]interface{}, w *prefixWriter) {
+	w.Write(0, "Pod Template:\n")
+	writeMap(1, "Labels", nestedStringMap(template, "metadata", "labels"), w)
+	if serviceAccount := nestedString(template, "spec", "serviceAccountName"); serviceAccount != "" {
+		w.Write(1, "Service Account:\t%s\n", serviceAccount)
+	}
+	if initContainers := nestedSlice(template, "spec", "initContainers"); len(initContainers) > 0 {
+		describeContainers("Init Containers", initContainers, nil, w, 1)
+	}
+	describeContainers("Containers", nestedSlice(template, "spec", "containers"), nil, w, 1)
+	describeVolumes(nestedSlice(template, "spec", "volumes"), w, 1)
+}
+
+// stringList returns the strings of the list at the path
+func stringList(obj map[string]interface{}, fields ...string) []string {
+	values, _, _ := unstructured.NestedStringSlice(obj, fields...)
+	return values
+}
@@ -0,0 +1,157 @@
+package describe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// endpointsResource is the resource holding the addresses behind a service
+var endpointsResource = schema.GroupVersionResource{Version: "v1", Resource: "endpoints"}
+
+// describeService writes the type, addresses, ports and endpoints of a service
+func describeService(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured, w *prefixWriter) error {
+	service := obj.Object
+	endpoints := fetchEndpoints(ctx, client, obj.GetNamespace(), obj.GetName())
+
+	describeMeta(obj, w)
+	w.Write(0, "Selector:\t%s\n", formatLabels(nestedStringMap(service, "spec", "selector")))
+	w.Write(0, "Type:\t%s\n", valueOr(nestedString(service, "spec", "type"), "ClusterIP"))
+	w.Write(0, "IP:\t%s\n", valueOr(nestedString(service, "spec", "clusterIP"), "<none>"))
+	if name := nestedString(service, "spec", "externalName"); name != "" {
+		w.Write(0, "External Name:\t%s\n", name)
+	}
+	if ips := stringList(service, "spec", "externalIPs"); len(ips) > 0 {
+		w.Write(0, "External IPs:\t%s\n", strings.Join(ips, ","))
+	}
+	var ingress []string
+	for _, entry := range nestedSlice(service, "status", "loadBalancer", "ingress") {
+		ingress = append(ingress, valueOr(nestedString(entry, "ip"), nestedString(entry, "hostname")))
+	}
+	if len(ingress) > 0 {
+		w.Write(0, "LoadBalancer Ingress:\t%s\n", strings.Join(ingress, ", "))
+	}
+
+	for _, port := range nestedSlice(service, "spec", "ports") {
+		name := valueOr(nestedString(port, "name"), "<unset>")
+		protocol := valueOr(nestedString(port, "protocol"), "TCP")
+		number, _ := nestedInt(port, "port")
+		w.Write(0, "Port:\t%s\t%d/%s\n", name, number, protocol)
+
+		targetPort := valueOr(nestedString(port, "targetPort"), fmt.Sprint(number))
+		w.Write(0, "TargetPort:\t%s/%s\n", targetPort, protocol)
+		if nodePort, ok := nestedInt(port, "nodePort"); ok && nodePort != 0 {
+			w.Write(0, "NodePort:\t%s\t%d/%s\n", name, nodePort, protocol)
+		}
+		w.Write(0, "Endpoints:\t%s\n", formatEndpoints(endpoints, nestedString(port, "name")))
+	}
+	w.Write(0, "Session Affinity:\t%s\n", valueOr(nestedString(service, "spec", "sessionAffinity"), "None"))
+	return nil
+}
+
+// describeRoute writes the host, path, TLS settings and backends of a route
+func describeRoute(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured, w *prefixWriter) error {
+	route := obj.Object
+
+	describeMeta(obj, w)
+	host := nestedString(route, "spec", "host")
+	var admitted []string
+	for _, ingress := range nestedSlice(route, "status", "ingress") {
+		status := "not accepted"
+		for _, condition := range nestedSlice(ingress, "conditions") {
+			if nestedString(condition, "type") == "Admitted" && nestedString(condition, "status") == "True" {
+				status = "exposed"
+			}
+		}
+		admitted = append(admitted, fmt.Sprintf("%s %s on router %s",
+			valueOr(nestedString(ingress, "host"), host), status, nestedString(ingress, "routerName")))
+	}
+	w.Write(0, "Requested Host:\t%s\n", valueOr(host, "<none>"))
+	for _, line := range admitted {
+		w.Write(0, "\t  %s\n", line)
+	}
+	w.Write(0, "Path:\t%s\n", valueOr(nestedString(route, "spec", "path"), "<none>"))
+
+	termination := nestedString(route, "spec", "tls", "termination")
+	if termination == "" {
+		w.Write(0, "TLS Termination:\t<none>\n")
+	} else {
+		w.Write(0, "TLS Termination:\t%s\n", termination)
+		w.Write(0, "Insecure Policy:\t%s\n", valueOr(nestedString(route, "spec", "tls", "insecureEdgeTerminationPolicy"), "<none>"))
+	}
+
+	targetPort := valueOr(nestedString(route, "spec", "port", "targetPort"), "<all endpoints>")
+	w.Write(0, "Endpoint Port:\t%s\n", targetPort)
+	w.Write(0, "\n")
+
+	serviceName := nestedString(route, "spec", "to", "name")
+	w.Write(0, "Service:\t%s\n", serviceName)
+	if weight, ok := nestedInt(route, "spec", "to", "weight"); ok {
+		w.Write(0, "Weight:\t%d\n", weight)
+	}
+	endpoints := fetchEndpoints(ctx, client, obj.GetNamespace(), serviceName)
+	w.Write(0, "Endpoints:\t%s\n", formatEndpoints(endpoints, ""))
+
+	for _, backend := range nestedSlice(route, "spec", "alternateBackends") {
+		w.Write(0, "\n")
+		w.Write(0, "Service:\t%s\n", nestedString(backend, "name"))
+		if weight, ok := nestedInt(backend, "weight"); ok {
+			w.Write(0, "Weight:\t%d\n", weight)
+		}
+		endpoints := fetchEndpoints(ctx, client, obj.GetNamespace(), nestedString(backend, "name"))
+		w.Write(0, "Endpoints:\t%s\n", formatEndpoints(endpoints, ""))
+	}
+	return nil
+}
+
+// fetchEndpoints returns the endpoints of a service, or nil when they cannot be read
+func fetchEndpoints(ctx context.Context, client dynamic.Interface, namespace, name string) *unstructured.Unstructured {
+	if name == "" {
+		return nil
+	}
+	endpoints, err := client.Resource(endpointsResource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return endpoints
+}
+
+// formatEndpoints renders up to three ready ip:port pairs of the endpoints,
+// restricted to the named port when a port name is given
+func formatEndpoints(endpoints *unstructured.Unstructured, portName string) string {
+	if endpoints == nil {
+		return "<none>"
+	}
+
+	const max = 3
+	var list []string
+	count := 0
+	for _, subset := range nestedSlice(endpoints.Object, "subsets") {
+		for _, port := range nestedSlice(subset, "ports") {
+			if portName != "" && nestedString(port, "name") != portName {
+				continue
+			}
+			number, _ := nestedInt(port, "port")
+			for _, address := range nestedSlice(subset, "addresses") {
+				count++
+				if len(list) < max {
+					list = append(list, fmt.Sprintf("%s:%d", nestedString(address, "ip"), number))
+				}
+			}
+		}
+	}
+
+	if count == 0 {
+		return "<none>"
+	}
+	result := strings.Join(list, ",")
+	if count > max {
+		result += fmt.Sprintf(" + %d more...", count-max)
+	}
+	return result
+}
@@ -0,0 +1,241 @@
+package diff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/withlin/oc-demo/pkg/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// ExternalDiffEnvs are the environment variables naming the external diff
+// program, in order of precedence
+var ExternalDiffEnvs = []string{"SKECTL_EXTERNAL_DIFF", "KUBECTL_EXTERNAL_DIFF"}
+
+// argPattern matches the arguments accepted for the external diff program
+var argPattern = regexp.MustCompile(`^[a-zA-Z0-9-=]+$`)
+
+// noiseFields are the metadata fields set by the server that are left out of
+// the diff
+var noiseFields = [][]string{
+	{"metadata", "managedFields"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "generation"},
+	{"metadata", "creationTimestamp"},
+	{"metadata", "uid"},
+	{"metadata", "selfLink"},
+}
+
+// Clean returns a copy of the object without the managed fields, the last
+// applied configuration and the other fields updated by the server on every
+// write. A nil object stays nil.
+func Clean(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if obj == nil {
+		return nil
+	}
+	cleaned := obj.DeepCopy()
+	for _, field := range noiseFields {
+		unstructured.RemoveNestedField(cleaned.Object, field...)
+	}
+	annotations := cleaned.GetAnnotations()
+	delete(annotations, resource.LastAppliedAnnotation)
+	cleaned.SetAnnotations(annotations)
+	return cleaned
+}
+
+// Differ writes the live and merged versions of objects to two directories
+// and compares them
+type Differ struct {
+	// LiveDir holds the objects as they are on the server
+	LiveDir string
+	// MergedDir holds the objects as they would be after the apply
+	MergedDir string
+	root      string
+	names     []string
+}
+
+// NewDiffer creates the directories of a differ below the temporary directory
+func NewDiffer() (*Differ, error) {
+	root, err := os.MkdirTemp("", "skectl-diff-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	d := &Differ{
+		LiveDir:   filepath.Join(root, "LIVE"),
+		MergedDir: filepath.Join(root, "MERGED"),
+		root:      root,
+	}
+	for _, dir := range []string{d.LiveDir, d.MergedDir} {
+		if err := os.Mkdir(dir, 0700); err != nil {
+			d.Close()
+			return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+	}
+	return d, nil
+}
+
+// Close removes the directories of the differ
+func (d *Differ) Close() error {
+	return os.RemoveAll(d.root)
+}
+
+// FileName returns the name of the files holding the object, built from its
+// group, version, kind, namespace and name
+func FileName(obj *unstructured.Unstructured) string {
+	gvk := obj.GroupVersionKind()
+	var parts []string
+	if gvk.Group != "" {
+		parts = append(parts, gvk.Group)
+	}
+	parts = append(parts, gvk.Version, gvk.Kind)
+	if obj.GetNamespace() != "" {
+		parts = append(parts, obj.GetNamespace())
+	}
+	return strings.Join(append(parts, obj.GetName()), ".")
+}
+
+// Add writes the cleaned live and merged versions of the object. The live
+// object is nil when it does not exist yet, which leaves its file out.
+func (d *Differ) Add(live, merged *unstructured.Unstructured) error {
+	name := FileName(merged)
+	d.names = append(d.names, name)
+	if err := writeObject(filepath.Join(d.LiveDir, name), Clean(live)); err != nil {
+		return err
+	}
+	return writeObject(filepath.Join(d.MergedDir, name), Clean(merged))
+}
+
+// writeObject writes the object as YAML, skipping nil objects
+func writeObject(path string, obj *unstructured.Unstructured) error {
+	if obj == nil {
+		return nil
+	}
+	data, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", obj.GetName(), err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ExternalProgram returns the external diff program and its arguments from
+// the environment, or nil when none is set
+func ExternalProgram() ([]string, error) {
+	for _, env := range ExternalDiffEnvs {
+		value := strings.TrimSpace(os.Getenv(env))
+		if value == "" {
+			continue
+		}
+		program := strings.Fields(value)
+		for _, arg := range program[1:] {
+			if !argPattern.MatchString(arg) {
+				return nil, fmt.Errorf("invalid argument %q in %s, only letters, digits, '-' and '=' are allowed", arg, env)
+			}
+		}
+		return program, nil
+	}
+	return nil, nil
+}
+
+// Run compares the directories, with the external program when one is given
+// and with a built-in unified diff otherwise. It reports whether they differ;
+// the exit code of the external program is returned in an *exec.ExitError
+// when it is greater than 1.
+func (d *Differ) Run(program []string, stdout, stderr io.Writer) (bool, error) {
+	if len(program) == 0 {
+		return d.unified(stdout)
+	}
+
+	args := append(program[1:len(program):len(program)], d.LiveDir, d.MergedDir)
+	cmd := exec.Command(program[0], args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return false, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		return true, nil
+	case errors.As(err, &exitErr):
+		return false, fmt.Errorf("external diff %s failed: %w", program[0], err)
+	default:
+		return false, fmt.Errorf("failed to run external diff %s: %w", program[0], err)
+	}
+}
+
+// unified writes a unified diff of every object that changed
+func (d *Differ) unified(w io.Writer) (bool, error) {
+	names := append([]string(nil), d.names...)
+	sort.Strings(names)
+
+	changed := false
+	for i, name := range names {
+		if i > 0 && names[i-1] == name {
+			continue
+		}
+		live, err := readFile(filepath.Join(d.LiveDir, name))
+		if err != nil {
+			return false, err
+		}
+		merged, err := readFile(filepath.Join(d.MergedDir, name))
+		if err != nil {
+			return false, err
+		}
+		if bytes.Equal(live, merged) {
+			continue
+		}
+
+		changed = true
+		liveName := filepath.Join(filepath.Base(d.LiveDir), name)
+		mergedName := filepath.Join(filepath.Base(d.MergedDir), name)
+		text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(live),
+			B:        splitLines(merged),
+			FromFile: liveName,
+			ToFile:   mergedName,
+			Context:  3,
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to compare %s: %w", name, err)
+		}
+		if _, err := fmt.Fprintf(w, "diff -u -N %s %s\n%s", liveName, mergedName, text); err != nil {
+			return false, fmt.Errorf("failed to write diff: %w", err)
+		}
+	}
+	return changed, nil
+}
+
+// splitLines splits the content into lines keeping their newlines, with no
+// line for an empty content
+func splitLines(data []byte) []string {
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// readFile returns the content of the file, empty when it does not exist
+func readFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
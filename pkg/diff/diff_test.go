@@ -0,0 +1,182 @@
+package diff
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// newObject decodes a YAML manifest
+func newObject(t *testing.T, manifest string) *unstructured.Unstructured {
+	t.Helper()
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil {
+		t.Fatalf("Failed to decode manifest: %v", err)
+	}
+	return obj
+}
+
+func TestClean(t *testing.T) {
+	obj := newObject(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+  namespace: default
+  uid: 1234
+  resourceVersion: "7"
+  generation: 2
+  creationTimestamp: "2024-01-01T00:00:00Z"
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: "{}"
+    team: shop
+  managedFields:
+  - manager: skectl
+    operation: Apply
+data:
+  color: blue
+`)
+	want := newObject(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+  namespace: default
+  annotations:
+    team: shop
+data:
+  color: blue
+`)
+
+	if got := Clean(obj); !reflect.DeepEqual(got.Object, want.Object) {
+		t.Errorf("Clean() = %v, want %v", got.Object, want.Object)
+	}
+	if _, found := obj.Object["metadata"].(map[string]interface{})["managedFields"]; !found {
+		t.Error("Clean() modified the object")
+	}
+	if Clean(nil) != nil {
+		t.Error("Clean(nil) should be nil")
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := map[string]string{
+		"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  namespace: shop\n": "apps.v1.Deployment.shop.web",
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\n  namespace: shop\n":       "v1.ConfigMap.shop.web",
+		"apiVersion: v1\nkind: Namespace\nmetadata:\n  name: shop\n":                         "v1.Namespace.shop",
+	}
+	for manifest, expected := range tests {
+		if got := FileName(newObject(t, manifest)); got != expected {
+			t.Errorf("FileName() = %q, want %q", got, expected)
+		}
+	}
+}
+
+func TestDifferUnified(t *testing.T) {
+	differ, err := NewDiffer()
+	if err != nil {
+		t.Fatalf("NewDiffer() error = %v", err)
+	}
+	defer differ.Close()
+
+	live := newObject(t, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n  namespace: default\n  resourceVersion: \"1\"\ndata:\n  color: blue\n")
+	merged := newObject(t, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n  namespace: default\n  resourceVersion: \"2\"\ndata:\n  color: green\n")
+	same := newObject(t, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n  namespace: default\n")
+	created := newObject(t, "apiVersion: v1\nkind: Secret\nmetadata:\n  name: c\n  namespace: default\n")
+	for _, pair := range [][2]*unstructured.Unstructured{{live, merged}, {same, same}, {nil, created}} {
+		if err := differ.Add(pair[0], pair[1]); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	var out bytes.Buffer
+	changed, err := differ.Run(nil, &out, &out)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !changed {
+		t.Error("Run() reported no differences")
+	}
+
+	expected := `diff -u -N LIVE/v1.ConfigMap.default.a MERGED/v1.ConfigMap.default.a
+--- LIVE/v1.ConfigMap.default.a
++++ MERGED/v1.ConfigMap.default.a
+@@ -1,6 +1,6 @@
+ apiVersion: v1
+ data:
+-  color: blue
++  color: green
+ kind: ConfigMap
+ metadata:
+   name: a
+diff -u -N LIVE/v1.Secret.default.c MERGED/v1.Secret.default.c
+--- LIVE/v1.Secret.default.c
++++ MERGED/v1.Secret.default.c
+@@ -0,0 +1,5 @@
++apiVersion: v1
++kind: Secret
++metadata:
++  name: c
++  namespace: default
+`
+	if out.String() != expected {
+		t.Errorf("Run() output = %q, want %q", out.String(), expected)
+	}
+}
+
+func TestDifferExternalProgram(t *testing.T) {
+	differ, err := NewDiffer()
+	if err != nil {
+		t.Fatalf("NewDiffer() error = %v", err)
+	}
+	defer differ.Close()
+
+	// The fake program prints its arguments and exits with the code passed first
+	script := filepath.Join(t.TempDir(), "fake-diff")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ncode=$1\nshift\necho \"$@\"\nexit $code\n"), 0755); err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+
+	for code, wantChanged := range map[string]bool{"0": false, "1": true} {
+		var out bytes.Buffer
+		changed, err := differ.Run([]string{script, code}, &out, &out)
+		if err != nil || changed != wantChanged {
+			t.Errorf("Run() with exit %s = %v, %v, want %v", code, changed, err, wantChanged)
+		}
+		if got := strings.TrimSpace(out.String()); got != differ.LiveDir+" "+differ.MergedDir {
+			t.Errorf("Run() arguments = %q, want the LIVE and MERGED directories", got)
+		}
+	}
+	if _, err := differ.Run([]string{script, "3"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Error("Run() expected an error for exit status 3")
+	}
+}
+
+func TestExternalProgram(t *testing.T) {
+	t.Setenv("SKECTL_EXTERNAL_DIFF", "")
+	t.Setenv("KUBECTL_EXTERNAL_DIFF", "")
+	if program, err := ExternalProgram(); err != nil || program != nil {
+		t.Errorf("ExternalProgram() = %v, %v, want none", program, err)
+	}
+
+	t.Setenv("KUBECTL_EXTERNAL_DIFF", "colordiff -N -u")
+	if program, err := ExternalProgram(); err != nil || !reflect.DeepEqual(program, []string{"colordiff", "-N", "-u"}) {
+		t.Errorf("ExternalProgram() = %v, %v, want colordiff -N -u", program, err)
+	}
+
+	t.Setenv("SKECTL_EXTERNAL_DIFF", "meld")
+	if program, err := ExternalProgram(); err != nil || !reflect.DeepEqual(program, []string{"meld"}) {
+		t.Errorf("ExternalProgram() = %v, %v, want meld", program, err)
+	}
+
+	t.Setenv("SKECTL_EXTERNAL_DIFF", "diff -u;rm")
+	if _, err := ExternalProgram(); err == nil {
+		t.Error("ExternalProgram() expected an error for an invalid argument")
+	}
+}
@@ -0,0 +1,48 @@
+package kubeconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// SetNamespace switches the context to the namespace and returns the name of
+// the context to use. A context named after the OpenShift scheme is left
+// untouched; the context named after the scheme for the namespace is created
+// or updated instead, so every project keeps its own context. Other contexts
+// get the namespace in place.
+func SetNamespace(config *api.Config, contextName, namespace string) (string, error) {
+	context, exists := config.Contexts[contextName]
+	if !exists {
+		return "", fmt.Errorf("context %q does not exist", contextName)
+	}
+
+	username, ok := schemeUsername(contextName, context)
+	if !ok {
+		context.Namespace = namespace
+		return contextName, nil
+	}
+
+	newName := ContextNickname(namespace, context.Cluster, username)
+	newContext := context.DeepCopy()
+	newContext.Namespace = namespace
+	if existing, exists := config.Contexts[newName]; exists {
+		newContext = existing
+		newContext.Cluster = context.Cluster
+		newContext.AuthInfo = context.AuthInfo
+		newContext.Namespace = namespace
+	}
+	config.Contexts[newName] = newContext
+	return newName, nil
+}
+
+// schemeUsername returns the user name of a context named after the OpenShift
+// scheme, whose user is named after the scheme as well
+func schemeUsername(contextName string, context *api.Context) (string, bool) {
+	username, ok := strings.CutSuffix(context.AuthInfo, "/"+context.Cluster)
+	if !ok || username == "" {
+		return "", false
+	}
+	return username, ContextNickname(context.Namespace, context.Cluster, username) == contextName
+}
@@ -0,0 +1,64 @@
+package kubeconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestSetNamespace(t *testing.T) {
+	newConfig := func() *api.Config {
+		config := api.NewConfig()
+		config.Contexts["project-a/api-example-com:6443/developer"] = &api.Context{
+			Cluster:   "api-example-com:6443",
+			AuthInfo:  "developer/api-example-com:6443",
+			Namespace: "project-a",
+		}
+		config.Contexts["project-b/api-example-com:6443/developer"] = &api.Context{
+			Cluster:   "api-example-com:6443",
+			AuthInfo:  "developer/api-example-com:6443",
+			Namespace: "stale",
+		}
+		config.Contexts["dev"] = &api.Context{
+			Cluster:   "api-example-com:6443",
+			AuthInfo:  "developer/api-example-com:6443",
+			Namespace: "project-a",
+		}
+		return config
+	}
+
+	t.Run("scheme context creates a new context", func(t *testing.T) {
+		config := newConfig()
+		name, err := SetNamespace(config, "project-a/api-example-com:6443/developer", "project-c")
+		require.NoError(t, err)
+		assert.Equal(t, "project-c/api-example-com:6443/developer", name)
+		assert.Equal(t, "project-c", config.Contexts[name].Namespace)
+		assert.Equal(t, "developer/api-example-com:6443", config.Contexts[name].AuthInfo)
+		assert.Equal(t, "project-a", config.Contexts["project-a/api-example-com:6443/developer"].Namespace)
+	})
+
+	t.Run("scheme context reuses an existing context", func(t *testing.T) {
+		config := newConfig()
+		name, err := SetNamespace(config, "project-a/api-example-com:6443/developer", "project-b")
+		require.NoError(t, err)
+		assert.Equal(t, "project-b/api-example-com:6443/developer", name)
+		assert.Equal(t, "project-b", config.Contexts[name].Namespace)
+		assert.Len(t, config.Contexts, 3)
+	})
+
+	t.Run("custom context is changed in place", func(t *testing.T) {
+		config := newConfig()
+		name, err := SetNamespace(config, "dev", "project-c")
+		require.NoError(t, err)
+		assert.Equal(t, "dev", name)
+		assert.Equal(t, "project-c", config.Contexts["dev"].Namespace)
+		assert.Len(t, config.Contexts, 3)
+	})
+
+	t.Run("missing context", func(t *testing.T) {
+		_, err := SetNamespace(newConfig(), "missing", "project-c")
+		assert.Error(t, err)
+	})
+}
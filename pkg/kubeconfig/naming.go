@@ -0,0 +1,52 @@
+package kubeconfig
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// DefaultNamespace is the namespace used when no project has been selected
+const DefaultNamespace = "default"
+
+// ClusterNickname returns the cluster name for a server URL, following the
+// OpenShift scheme, e.g. https://api.example.com:6443 becomes api-example-com:6443
+func ClusterNickname(server string) (string, error) {
+	if !strings.Contains(server, "://") {
+		server = "https://" + server
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return "", fmt.Errorf("invalid server URL: %w", err)
+	}
+	if serverURL.Hostname() == "" {
+		return "", fmt.Errorf("invalid server URL: missing host")
+	}
+
+	port := serverURL.Port()
+	if port == "" {
+		port = "443"
+		if serverURL.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	host := strings.ReplaceAll(serverURL.Hostname(), ".", "-")
+	return net.JoinHostPort(host, port), nil
+}
+
+// UserNickname returns the user name for a cluster, e.g. developer/api-example-com:6443
+func UserNickname(username, clusterNickname string) string {
+	return username + "/" + clusterNickname
+}
+
+// ContextNickname returns the context name for a namespace, cluster and user,
+// e.g. myproject/api-example-com:6443/developer
+func ContextNickname(namespace, clusterNickname, username string) string {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	return namespace + "/" + clusterNickname + "/" + username
+}
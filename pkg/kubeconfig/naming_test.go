@@ -0,0 +1,69 @@
+package kubeconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterNickname(t *testing.T) {
+	tests := []struct {
+		name        string
+		server      string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "https with port",
+			server:   "https://api.example.com:6443",
+			expected: "api-example-com:6443",
+		},
+		{
+			name:     "https without port",
+			server:   "https://api.example.com",
+			expected: "api-example-com:443",
+		},
+		{
+			name:     "http without port",
+			server:   "http://api.example.com",
+			expected: "api-example-com:80",
+		},
+		{
+			name:     "missing scheme",
+			server:   "api.example.com:6443",
+			expected: "api-example-com:6443",
+		},
+		{
+			name:     "trailing path",
+			server:   "https://api.example.com:6443/",
+			expected: "api-example-com:6443",
+		},
+		{
+			name:        "missing host",
+			server:      "https://",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ClusterNickname(tt.server)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestUserNickname(t *testing.T) {
+	assert.Equal(t, "developer/api-example-com:6443", UserNickname("developer", "api-example-com:6443"))
+}
+
+func TestContextNickname(t *testing.T) {
+	assert.Equal(t, "myproject/api-example-com:6443/developer", ContextNickname("myproject", "api-example-com:6443", "developer"))
+	assert.Equal(t, "default/api-example-com:6443/developer", ContextNickname("", "api-example-com:6443", "developer"))
+}
@@ -0,0 +1,233 @@
+package logs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/withlin/oc-demo/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultMaxStreams is the default number of log streams read at once
+const DefaultMaxStreams = 5
+
+// lineBuffer is the number of lines buffered per stream before reading pauses
+const lineBuffer = 64
+
+// Source is a container whose logs are read
+type Source struct {
+	// Pod is the name of the pod
+	Pod string
+	// Container is the name of the container
+	Container string
+}
+
+// String returns the source in pod/container form
+func (s Source) String() string {
+	return s.Pod + "/" + s.Container
+}
+
+// Sources returns the containers of the pods to read. The named container is
+// required in every pod; without one, every container is read with
+// allContainers, or else the default container of each pod. The notify
+// function is called when a default container is picked among several.
+func Sources(pods []corev1.Pod, container string, allContainers bool, notify func(pod, container string, names []string)) ([]Source, error) {
+	var sources []Source
+	for i := range pods {
+		pod := &pods[i]
+		names := resource.ContainerNames(pod)
+
+		switch {
+		case container != "":
+			if !contains(names, container) {
+				return nil, fmt.Errorf("container %s is not valid for pod %s, choose one of: %v", container, pod.Name, names)
+			}
+			sources = append(sources, Source{Pod: pod.Name, Container: container})
+		case allContainers:
+			for _, name := range names {
+				sources = append(sources, Source{Pod: pod.Name, Container: name})
+			}
+		default:
+			name, defaulted, err := resource.DefaultContainer(pod)
+			if err != nil {
+				return nil, err
+			}
+			if defaulted && notify != nil {
+				notify(pod.Name, name, names[len(pod.Spec.InitContainers):])
+			}
+			sources = append(sources, Source{Pod: pod.Name, Container: name})
+		}
+	}
+	return sources, nil
+}
+
+// contains reports whether the list holds the value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Streamer reads the logs of several containers at once and writes them line
+// by line. Every stream has a small buffer and the lines are written taking
+// one line of each stream in turn, so a busy container waits for its buffer
+// to drain instead of holding back the others.
+type Streamer struct {
+	// Client reads the logs
+	Client kubernetes.Interface
+	// Namespace is the namespace of the pods
+	Namespace string
+	// Options are the log options shared by every container
+	Options corev1.PodLogOptions
+	// MaxStreams caps the number of streams read at once
+	MaxStreams int
+	// Prefix starts every line with [pod/container]
+	Prefix bool
+}
+
+// line is a line of a stream
+type line struct {
+	source Source
+	text   string
+}
+
+// Run writes the logs of the sources to the writer until they end or the
+// context is done. When following, every stream stays open, so more sources
+// than MaxStreams are rejected.
+func (s *Streamer) Run(ctx context.Context, sources []Source, out io.Writer) error {
+	maxStreams := s.MaxStreams
+	if maxStreams <= 0 {
+		maxStreams = DefaultMaxStreams
+	}
+	if s.Options.Follow && len(sources) > maxStreams {
+		return fmt.Errorf("you are attempting to follow %d log streams, but maximum allowed concurrency is %d, use --max-log-requests to increase the limit",
+			len(sources), maxStreams)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	streams := make([]chan line, len(sources))
+	notify := make(chan struct{}, 1)
+	errs := make([]error, len(sources))
+	slots := make(chan struct{}, maxStreams)
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		streams[i] = make(chan line, lineBuffer)
+		wg.Add(1)
+		go func(i int, source Source) {
+			defer wg.Done()
+			defer func() {
+				close(streams[i])
+				wake(notify)
+			}()
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				return
+			}
+			errs[i] = s.stream(ctx, source, streams[i], notify)
+		}(i, source)
+	}
+
+	err := s.write(ctx, streams, notify, out)
+	cancel()
+	wg.Wait()
+	if err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// stream reads the logs of one container into the channel
+func (s *Streamer) stream(ctx context.Context, source Source, lines chan<- line, notify chan struct{}) error {
+	options := s.Options
+	options.Container = source.Container
+	body, err := s.Client.CoreV1().Pods(s.Namespace).GetLogs(source.Pod, &options).Stream(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to get logs of %s: %w", source, err)
+	}
+	defer body.Close()
+
+	reader := bufio.NewReader(body)
+	for {
+		text, err := reader.ReadString('\n')
+		if text != "" {
+			if !strings.HasSuffix(text, "\n") {
+				text += "\n"
+			}
+			select {
+			case lines <- line{source: source, text: text}:
+				wake(notify)
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read logs of %s: %w", source, err)
+		}
+	}
+}
+
+// write takes one buffered line of every stream in turn and writes it, until
+// every stream is closed or the context is done
+func (s *Streamer) write(ctx context.Context, streams []chan line, notify chan struct{}, out io.Writer) error {
+	active := append([]chan line(nil), streams...)
+	for len(active) > 0 {
+		progressed := false
+		for i := 0; i < len(active); {
+			select {
+			case l, ok := <-active[i]:
+				if !ok {
+					active = append(active[:i], active[i+1:]...)
+					progressed = true
+					continue
+				}
+				text := l.text
+				if s.Prefix {
+					text = "[" + l.source.String() + "] " + text
+				}
+				if _, err := io.WriteString(out, text); err != nil {
+					return fmt.Errorf("failed to write logs: %w", err)
+				}
+				progressed = true
+			default:
+			}
+			i++
+		}
+		if progressed {
+			continue
+		}
+
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
+
+// wake signals the writer that a stream has a line or ended
+func wake(notify chan struct{}) {
+	select {
+	case notify <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,246 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/withlin/oc-demo/pkg/resource"
+	"github.com/withlin/oc-demo/pkg/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// newPod returns a pod with the init containers and containers
+func newPod(name string, initContainers, containers []string, annotations map[string]string) corev1.Pod {
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+	for _, container := range initContainers {
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{Name: container})
+	}
+	for _, container := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: container})
+	}
+	return pod
+}
+
+func TestSources(t *testing.T) {
+	pods := []corev1.Pod{
+		newPod("web-1", []string{"init"}, []string{"nginx", "sidecar"}, nil),
+		newPod("web-2", nil, []string{"nginx", "sidecar"}, map[string]string{resource.DefaultContainerAnnotation: "sidecar"}),
+	}
+
+	tests := []struct {
+		name          string
+		container     string
+		allContainers bool
+		expected      []string
+		notified      []string
+		wantErr       bool
+	}{
+		{
+			name:     "default containers",
+			expected: []string{"web-1/nginx", "web-2/sidecar"},
+			notified: []string{"web-1/nginx [nginx sidecar]"},
+		},
+		{
+			name:      "named container",
+			container: "sidecar",
+			expected:  []string{"web-1/sidecar", "web-2/sidecar"},
+		},
+		{
+			name:          "all containers",
+			allContainers: true,
+			expected:      []string{"web-1/init", "web-1/nginx", "web-1/sidecar", "web-2/nginx", "web-2/sidecar"},
+		},
+		{
+			name:      "unknown container",
+			container: "db",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var notified []string
+			sources, err := Sources(pods, tt.container, tt.allContainers, func(pod, container string, names []string) {
+				notified = append(notified, fmt.Sprintf("%s/%s %v", pod, container, names))
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Sources() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, source := range sources {
+				got = append(got, source.String())
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Sources() = %v, want %v", got, tt.expected)
+			}
+			if !reflect.DeepEqual(notified, tt.notified) {
+				t.Errorf("notified = %v, want %v", notified, tt.notified)
+			}
+		})
+	}
+}
+
+// newTestClient returns a client for the fake server
+func newTestClient(t *testing.T, server *testutil.FakeAPIServer) kubernetes.Interface {
+	t.Helper()
+	config, err := clientcmd.NewDefaultClientConfig(*server.Kubeconfig("default"), nil).ClientConfig()
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	config.QPS, config.Burst = 100, 100
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client
+}
+
+// handleLogs serves the logs of a pod, one line per container with the query
+func handleLogs(server *testutil.FakeAPIServer, pod string) {
+	server.Handle(http.MethodGet, "/api/v1/namespaces/default/pods/"+pod+"/log", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "hello from %s\nquery %s", r.URL.Query().Get("container"), r.URL.RawQuery)
+	})
+}
+
+func TestStreamer(t *testing.T) {
+	server := testutil.NewFakeAPIServer()
+	defer server.Close()
+	handleLogs(server, "web-1")
+	handleLogs(server, "web-2")
+
+	tail := int64(10)
+	streamer := &Streamer{
+		Client:    newTestClient(t, server),
+		Namespace: "default",
+		Options:   corev1.PodLogOptions{TailLines: &tail, Timestamps: true},
+		Prefix:    true,
+	}
+	var out bytes.Buffer
+	sources := []Source{{Pod: "web-1", Container: "nginx"}, {Pod: "web-2", Container: "sidecar"}}
+	if err := streamer.Run(context.Background(), sources, &out); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	for _, expected := range []string{
+		"[web-1/nginx] hello from nginx\n",
+		"[web-2/sidecar] hello from sidecar\n",
+		"[web-1/nginx] query container=nginx&tailLines=10&timestamps=true\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Run() output = %q, want %q", out.String(), expected)
+		}
+	}
+
+	// Missing pods are reported after the other logs
+	out.Reset()
+	err := streamer.Run(context.Background(), []Source{{Pod: "web-1", Container: "nginx"}, {Pod: "missing", Container: "nginx"}}, &out)
+	if err == nil || !strings.Contains(err.Error(), "missing/nginx") {
+		t.Errorf("Run() error = %v, want an error for missing/nginx", err)
+	}
+	if !strings.Contains(out.String(), "hello from nginx") {
+		t.Errorf("Run() output = %q, want the logs of web-1", out.String())
+	}
+}
+
+// slowWriter pauses on every write, like a slow terminal
+type slowWriter struct {
+	buffer bytes.Buffer
+}
+
+// Write writes the data after a short pause
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(20 * time.Microsecond)
+	return w.buffer.Write(p)
+}
+
+func TestStreamerFairness(t *testing.T) {
+	server := testutil.NewFakeAPIServer()
+	defer server.Close()
+
+	// The chatty pod starts once the quiet one has sent its lines
+	quietSent := make(chan struct{})
+	server.Handle(http.MethodGet, "/api/v1/namespaces/default/pods/quiet/log", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "quiet 1\nquiet 2\nquiet 3\n")
+		w.(http.Flusher).Flush()
+		close(quietSent)
+	})
+	server.Handle(http.MethodGet, "/api/v1/namespaces/default/pods/chatty/log", func(w http.ResponseWriter, r *http.Request) {
+		<-quietSent
+		for i := 0; i < 300; i++ {
+			fmt.Fprintf(w, "chatty %d\n", i)
+		}
+	})
+
+	streamer := &Streamer{Client: newTestClient(t, server), Namespace: "default"}
+	out := &slowWriter{}
+	sources := []Source{{Pod: "chatty", Container: "app"}, {Pod: "quiet", Container: "app"}}
+	if err := streamer.Run(context.Background(), sources, out); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.buffer.String()), "\n")
+	if len(lines) != 303 {
+		t.Fatalf("Run() wrote %d lines, want 303", len(lines))
+	}
+	for i, line := range lines {
+		if line == "quiet 3" {
+			if i > 100 {
+				t.Errorf("quiet 3 written at line %d, after the chatty pod", i)
+			}
+			return
+		}
+	}
+	t.Error("quiet 3 was not written")
+}
+
+func TestStreamerConcurrency(t *testing.T) {
+	server := testutil.NewFakeAPIServer()
+	defer server.Close()
+
+	var mu sync.Mutex
+	open, maxOpen := 0, 0
+	for _, pod := range []string{"a", "b", "c"} {
+		server.Handle(http.MethodGet, "/api/v1/namespaces/default/pods/"+pod+"/log", func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			open++
+			if open > maxOpen {
+				maxOpen = open
+			}
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			fmt.Fprintln(w, "done")
+			mu.Lock()
+			open--
+			mu.Unlock()
+		})
+	}
+
+	streamer := &Streamer{Client: newTestClient(t, server), Namespace: "default", MaxStreams: 2}
+	sources := []Source{{Pod: "a", Container: "app"}, {Pod: "b", Container: "app"}, {Pod: "c", Container: "app"}}
+	var out bytes.Buffer
+	if err := streamer.Run(context.Background(), sources, &out); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if strings.Count(out.String(), "done") != 3 {
+		t.Errorf("Run() output = %q, want three streams", out.String())
+	}
+	if maxOpen > 2 {
+		t.Errorf("%d streams were open at once, want at most 2", maxOpen)
+	}
+
+	// Following keeps every stream open, so the cap is checked upfront
+	streamer.Options.Follow = true
+	if err := streamer.Run(context.Background(), sources, &out); err == nil || !strings.Contains(err.Error(), "--max-log-requests") {
+		t.Errorf("Run() error = %v, want the concurrency limit", err)
+	}
+}
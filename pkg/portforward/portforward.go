@@ -0,0 +1,340 @@
+package portforward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/portforward"
+)
+
+// DefaultRetryDelay is the default delay before reconnecting to a pod
+const DefaultRetryDelay = time.Second
+
+// errPodGone stops a forward once its pod is deleted, terminating or restarted
+var errPodGone = errors.New("pod is gone")
+
+// Port is a LOCAL:REMOTE port pair given on the command line
+type Port struct {
+	// Local is the local port, 0 for a random port
+	Local uint16
+	// Remote is the remote port number or name
+	Remote string
+}
+
+// String returns the port in LOCAL:REMOTE form
+func (p Port) String() string {
+	return fmt.Sprintf("%d:%s", p.Local, p.Remote)
+}
+
+// ParsePorts parses port arguments of the form LOCAL:REMOTE, PORT or :REMOTE.
+// The remote port is a number or a port name; a single port is used on both
+// sides and must be a number, and an empty local port picks a random one.
+func ParsePorts(args []string) ([]Port, error) {
+	ports := make([]Port, 0, len(args))
+	for _, arg := range args {
+		local, remote, found := strings.Cut(arg, ":")
+		if !found {
+			local, remote = arg, arg
+		}
+		if remote == "" {
+			return nil, fmt.Errorf("invalid port %q, the remote port is empty", arg)
+		}
+		if number, err := strconv.ParseUint(remote, 10, 16); err == nil && number == 0 {
+			return nil, fmt.Errorf("invalid port %q, the remote port must be greater than 0", arg)
+		}
+
+		port := Port{Remote: remote}
+		if local != "" {
+			number, err := strconv.ParseUint(local, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("invalid port %q, the local port must be a number between 0 and 65535", arg)
+			}
+			port.Local = uint16(number)
+		}
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
+
+// TranslatePorts returns the ports of the pod that the ports refer to, in the
+// LOCAL:REMOTE form of the port forward request. With a service, the remote
+// ports are ports of the service, forwarded to their target port; otherwise
+// they are ports of the pod. Named ports are looked up in the containers of
+// the pod.
+func TranslatePorts(service *corev1.Service, pod *corev1.Pod, ports []Port) ([]string, error) {
+	translated := make([]string, 0, len(ports))
+	for _, port := range ports {
+		remote, err := translatePort(service, pod, port.Remote)
+		if err != nil {
+			return nil, err
+		}
+		translated = append(translated, fmt.Sprintf("%d:%d", port.Local, remote))
+	}
+	return translated, nil
+}
+
+// translatePort returns the number of the pod port that the remote port
+// refers to
+func translatePort(service *corev1.Service, pod *corev1.Pod, remote string) (int32, error) {
+	if service == nil {
+		if number, err := strconv.ParseUint(remote, 10, 16); err == nil {
+			return int32(number), nil
+		}
+		return containerPort(pod, remote)
+	}
+
+	number, numberErr := strconv.ParseUint(remote, 10, 16)
+	for _, servicePort := range service.Spec.Ports {
+		if (numberErr == nil && servicePort.Port == int32(number)) || (numberErr != nil && servicePort.Name == remote) {
+			if servicePort.Protocol != "" && servicePort.Protocol != corev1.ProtocolTCP {
+				return 0, fmt.Errorf("service %s port %s uses protocol %s, only TCP can be forwarded", service.Name, remote, servicePort.Protocol)
+			}
+			switch {
+			case servicePort.TargetPort.StrVal != "":
+				return containerPort(pod, servicePort.TargetPort.StrVal)
+			case servicePort.TargetPort.IntVal != 0:
+				return servicePort.TargetPort.IntVal, nil
+			default:
+				return servicePort.Port, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("service %s does not have a port %s", service.Name, remote)
+}
+
+// containerPort returns the number of the named container port of the pod
+func containerPort(pod *corev1.Pod, name string) (int32, error) {
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name == name {
+				return port.ContainerPort, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("pod %s does not have a port named %s", pod.Name, name)
+}
+
+// Forwarder forwards local ports to a pod. When the connection to the pod is
+// lost, or the pod is deleted or restarted, it resolves the pod again and
+// reconnects on the same local ports until the context is done.
+type Forwarder struct {
+	// Client watches the forwarded pod
+	Client kubernetes.Interface
+	// Addresses are the local addresses to listen on
+	Addresses []string
+	// Ports are the forwarded ports
+	Ports []Port
+	// Service is the service whose ports are forwarded, nil to forward ports
+	// of the pod
+	Service *corev1.Service
+	// Resolve returns the pod to forward to, called before every connection
+	Resolve func(ctx context.Context) (*corev1.Pod, error)
+	// Dialer opens the port forward connection to the pod
+	Dialer func(pod *corev1.Pod) (httpstream.Dialer, error)
+	// RetryDelay is the delay before reconnecting
+	RetryDelay time.Duration
+	// Out receives the forwarding messages
+	Out io.Writer
+	// ErrOut receives the errors and reconnection messages
+	ErrOut io.Writer
+
+	// forward forwards the ports to the pod until the context is done or the
+	// connection is lost, calling ready with the local ports once listening
+	forward func(ctx context.Context, pod *corev1.Pod, ports []string, ready func([]uint16)) error
+}
+
+// Run forwards the ports until the context is done. Errors before the first
+// connection is ready are returned; later ones trigger a reconnection.
+func (f *Forwarder) Run(ctx context.Context) error {
+	forward := f.forward
+	if forward == nil {
+		forward = f.forwardPod
+	}
+	retryDelay := f.retryDelay()
+
+	ports := append([]Port(nil), f.Ports...)
+	connected := false
+	for {
+		err := f.connect(ctx, forward, ports, func(local []uint16) {
+			// Keep the random local ports across reconnections
+			for i := range ports {
+				if i < len(local) {
+					ports[i].Local = local[i]
+				}
+			}
+			connected = true
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !connected {
+			return err
+		}
+		if err == nil {
+			err = portforward.ErrLostConnectionToPod
+		}
+		fmt.Fprintf(f.ErrOut, "%v, reconnecting in %s\n", err, retryDelay)
+
+		select {
+		case <-time.After(retryDelay):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// connect resolves the pod and forwards the ports to it until the connection
+// is lost or the pod is gone
+func (f *Forwarder) connect(ctx context.Context, forward func(context.Context, *corev1.Pod, []string, func([]uint16)) error, ports []Port, ready func([]uint16)) error {
+	pod, err := f.Resolve(ctx)
+	if err != nil {
+		return err
+	}
+	translated, err := TranslatePorts(f.Service, pod, ports)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	gone := make(chan struct{})
+	go func() {
+		f.watchPod(ctx, pod)
+		close(gone)
+		cancel()
+	}()
+
+	err = forward(ctx, pod, translated, ready)
+	select {
+	case <-gone:
+		return fmt.Errorf("pod %s: %w", pod.Name, errPodGone)
+	default:
+	}
+	if err != nil {
+		return fmt.Errorf("pod %s: %w", pod.Name, err)
+	}
+	return nil
+}
+
+// watchPod returns once the pod is deleted, terminating or restarted, or when
+// the context is done
+func (f *Forwarder) watchPod(ctx context.Context, pod *corev1.Pod) {
+	pods := f.Client.CoreV1().Pods(pod.Namespace)
+	resourceVersion := pod.ResourceVersion
+	for ctx.Err() == nil {
+		watcher, err := pods.Watch(ctx, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", pod.Name).String(),
+			ResourceVersion: resourceVersion,
+		})
+		if err != nil {
+			resourceVersion = ""
+			select {
+			case <-time.After(f.retryDelay()):
+			case <-ctx.Done():
+			}
+			continue
+		}
+		for event := range watcher.ResultChan() {
+			if event.Type == watch.Error {
+				// The resource version expired, watch the current state
+				resourceVersion = ""
+				continue
+			}
+			current, ok := event.Object.(*corev1.Pod)
+			if !ok {
+				continue
+			}
+			resourceVersion = current.ResourceVersion
+			if event.Type == watch.Deleted || current.UID != pod.UID || !Running(current) || restarts(current) != restarts(pod) {
+				watcher.Stop()
+				return
+			}
+		}
+		watcher.Stop()
+	}
+}
+
+// retryDelay returns the delay before reconnecting
+func (f *Forwarder) retryDelay() time.Duration {
+	if f.RetryDelay <= 0 {
+		return DefaultRetryDelay
+	}
+	return f.RetryDelay
+}
+
+// forwardPod forwards the ports to the pod through the port forward
+// subresource until the context is done or the connection is lost
+func (f *Forwarder) forwardPod(ctx context.Context, pod *corev1.Pod, ports []string, ready func([]uint16)) error {
+	dialer, err := f.Dialer(pod)
+	if err != nil {
+		return err
+	}
+	addresses := f.Addresses
+	if len(addresses) == 0 {
+		addresses = []string{"localhost"}
+	}
+
+	stop := make(chan struct{})
+	readyChan := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, addresses, ports, stop, readyChan, f.Out, f.ErrOut)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- forwarder.ForwardPorts()
+	}()
+	select {
+	case <-readyChan:
+		forwarded, err := forwarder.GetPorts()
+		if err != nil {
+			close(stop)
+			<-done
+			return err
+		}
+		local := make([]uint16, len(forwarded))
+		for i, port := range forwarded {
+			local[i] = port.Local
+		}
+		ready(local)
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		close(stop)
+		return <-done
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		close(stop)
+		return <-done
+	}
+}
+
+// Running reports whether the pod is running and not being deleted
+func Running(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil
+}
+
+// restarts returns the total number of container restarts of the pod
+func restarts(pod *corev1.Pod) int32 {
+	var total int32
+	for _, status := range pod.Status.ContainerStatuses {
+		total += status.RestartCount
+	}
+	return total
+}
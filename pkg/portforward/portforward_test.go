@@ -0,0 +1,250 @@
+package portforward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/withlin/oc-demo/pkg/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestParsePorts(t *testing.T) {
+	ports, err := ParsePorts([]string{"5000", "8080:80", ":5432", "9090:http"})
+	if err != nil {
+		t.Fatalf("ParsePorts() error = %v", err)
+	}
+	expected := []Port{{5000, "5000"}, {8080, "80"}, {0, "5432"}, {9090, "http"}}
+	if !reflect.DeepEqual(ports, expected) {
+		t.Errorf("ParsePorts() = %v, want %v", ports, expected)
+	}
+
+	for _, arg := range []string{"8080:", "http", "web:80", "70000:80", "8080:0"} {
+		if _, err := ParsePorts([]string{arg}); err == nil {
+			t.Errorf("ParsePorts(%q) expected an error", arg)
+		}
+	}
+}
+
+func TestTranslatePorts(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:  "nginx",
+			Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}, {Name: "metrics", ContainerPort: 9090}},
+		}}},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Name: "web", Port: 80, TargetPort: intstr.FromString("http")},
+			{Name: "admin", Port: 81, TargetPort: intstr.FromInt(9000)},
+			{Name: "plain", Port: 82},
+			{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+		}},
+	}
+
+	tests := []struct {
+		name        string
+		service     *corev1.Service
+		ports       []Port
+		expected    []string
+		expectError bool
+	}{
+		{
+			name:     "pod ports",
+			ports:    []Port{{5000, "5000"}, {0, "metrics"}},
+			expected: []string{"5000:5000", "0:9090"},
+		},
+		{
+			name:        "unknown pod port name",
+			ports:       []Port{{0, "grpc"}},
+			expectError: true,
+		},
+		{
+			name:     "service ports by number and name",
+			service:  service,
+			ports:    []Port{{8080, "80"}, {0, "admin"}, {8082, "82"}},
+			expected: []string{"8080:8080", "0:9000", "8082:82"},
+		},
+		{
+			name:        "unknown service port",
+			service:     service,
+			ports:       []Port{{0, "8080"}},
+			expectError: true,
+		},
+		{
+			name:        "UDP service port",
+			service:     service,
+			ports:       []Port{{0, "53"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TranslatePorts(tt.service, pod, tt.ports)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("TranslatePorts() = %v, expected an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TranslatePorts() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("TranslatePorts() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// newTestClient creates a clientset for the fake API server
+func newTestClient(t *testing.T, server *testutil.FakeAPIServer) kubernetes.Interface {
+	t.Helper()
+
+	config, err := clientcmd.NewDefaultClientConfig(*server.Kubeconfig("default"), nil).ClientConfig()
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	config.QPS, config.Burst = 100, 100
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client
+}
+
+// addPod adds a pod of the web app with the phase to the server
+func addPod(server *testutil.FakeAPIServer, name, phase string) {
+	server.AddYAML(fmt.Sprintf(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: %s
+  namespace: default
+  uid: %s-uid
+  labels:
+    app: web
+spec:
+  containers:
+  - name: nginx
+    ports:
+    - name: http
+      containerPort: 8080
+status:
+  phase: %s
+`, name, name, phase))
+}
+
+// connection is a call of the forward hook
+type connection struct {
+	pod   string
+	ports []string
+}
+
+func TestForwarderReconnects(t *testing.T) {
+	server := testutil.NewFakeAPIServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+	addPod(server, "web-1", "Running")
+
+	connections := make(chan connection, 10)
+	lost := make(chan struct{}, 1)
+	forwarder := &Forwarder{
+		Client: client,
+		Ports:  []Port{{0, "http"}},
+		Resolve: func(ctx context.Context) (*corev1.Pod, error) {
+			pods, err := client.CoreV1().Pods("default").List(ctx, metav1.ListOptions{LabelSelector: "app=web"})
+			if err != nil {
+				return nil, err
+			}
+			for i := range pods.Items {
+				if Running(&pods.Items[i]) {
+					return &pods.Items[i], nil
+				}
+			}
+			return nil, errors.New("no running pod")
+		},
+		RetryDelay: 10 * time.Millisecond,
+		ErrOut:     io.Discard,
+		forward: func(ctx context.Context, pod *corev1.Pod, ports []string, ready func([]uint16)) error {
+			connections <- connection{pod: pod.Name, ports: ports}
+			ready([]uint16{40000})
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-lost:
+				return errors.New("lost connection to pod")
+			}
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- forwarder.Run(ctx)
+	}()
+
+	next := func() connection {
+		t.Helper()
+		select {
+		case c := <-connections:
+			return c
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a connection")
+			return connection{}
+		}
+	}
+
+	if c := next(); c.pod != "web-1" || !reflect.DeepEqual(c.ports, []string{"0:8080"}) {
+		t.Errorf("first connection = %+v, want web-1 on 0:8080", c)
+	}
+
+	// A lost connection reconnects on the same local port
+	lost <- struct{}{}
+	if c := next(); c.pod != "web-1" || !reflect.DeepEqual(c.ports, []string{"40000:8080"}) {
+		t.Errorf("connection after a lost connection = %+v, want web-1 on 40000:8080", c)
+	}
+
+	// A replaced pod reconnects to the new pod
+	addPod(server, "web-2", "Pending")
+	server.DeleteObject("v1", "Pod", "default", "web-1")
+	time.Sleep(50 * time.Millisecond)
+	addPod(server, "web-2", "Running")
+	if c := next(); c.pod != "web-2" || !reflect.DeepEqual(c.ports, []string{"40000:8080"}) {
+		t.Errorf("connection after the pod was replaced = %+v, want web-2 on 40000:8080", c)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return after the context was done")
+	}
+}
+
+func TestForwarderFirstConnectionError(t *testing.T) {
+	forwarder := &Forwarder{
+		Ports: []Port{{0, "80"}},
+		Resolve: func(ctx context.Context) (*corev1.Pod, error) {
+			return nil, errors.New("pod web-1 is not running")
+		},
+		ErrOut: io.Discard,
+	}
+	if err := forwarder.Run(context.Background()); err == nil || err.Error() != "pod web-1 is not running" {
+		t.Errorf("Run() error = %v, want the resolve error", err)
+	}
+}
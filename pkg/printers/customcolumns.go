@@ -0,0 +1,138 @@
+package printers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Column is a custom column with its header and JSONPath field specification
+type Column struct {
+	// Header is the column header
+	Header string
+	// FieldSpec is the JSONPath template of the column value
+	FieldSpec string
+}
+
+// CustomColumnsPrinter prints objects as a table of user defined columns
+type CustomColumnsPrinter struct {
+	columns   []Column
+	paths     []*jsonpath.JSONPath
+	NoHeaders bool
+}
+
+// NewCustomColumnsPrinter creates a printer from a HEADER:.field,... spec
+func NewCustomColumnsPrinter(spec string, noHeaders bool) (*CustomColumnsPrinter, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("custom-columns format specified but no custom columns given")
+	}
+
+	var columns []Column
+	for _, part := range strings.Split(spec, ",") {
+		header, field, found := strings.Cut(part, ":")
+		if !found || header == "" || field == "" {
+			return nil, fmt.Errorf("unexpected custom-columns spec: %s, expected <header>:<json-path-expr>", part)
+		}
+		columns = append(columns, Column{Header: header, FieldSpec: field})
+	}
+	return newCustomColumnsPrinter(columns, noHeaders)
+}
+
+// NewCustomColumnsPrinterFromTemplate creates a printer from a template whose
+// first line holds the headers and second line the field specifications
+func NewCustomColumnsPrinterFromTemplate(template io.Reader, noHeaders bool) (*CustomColumnsPrinter, error) {
+	scanner := bufio.NewScanner(template)
+	var lines []string
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read custom-columns template: %w", err)
+	}
+	if len(lines) != 2 {
+		return nil, fmt.Errorf("invalid custom-columns template, expected 2 lines, found %d", len(lines))
+	}
+
+	headers := strings.Fields(lines[0])
+	fields := strings.Fields(lines[1])
+	if len(headers) != len(fields) {
+		return nil, fmt.Errorf("number of headers (%d) and field specifications (%d) don't match", len(headers), len(fields))
+	}
+
+	columns := make([]Column, len(headers))
+	for i := range headers {
+		columns[i] = Column{Header: headers[i], FieldSpec: fields[i]}
+	}
+	return newCustomColumnsPrinter(columns, noHeaders)
+}
+
+// newCustomColumnsPrinter parses the field specifications of the columns
+func newCustomColumnsPrinter(columns []Column, noHeaders bool) (*CustomColumnsPrinter, error) {
+	p := &CustomColumnsPrinter{columns: columns, NoHeaders: noHeaders}
+	for i, column := range columns {
+		template, err := RelaxedJSONPath(column.FieldSpec)
+		if err != nil {
+			return nil, err
+		}
+		path := jsonpath.New(fmt.Sprintf("column%d", i)).AllowMissingKeys(true)
+		if err := path.Parse(template); err != nil {
+			return nil, fmt.Errorf("error parsing column %s: %w", column.Header, err)
+		}
+		p.columns[i].FieldSpec = template
+		p.paths = append(p.paths, path)
+	}
+	return p, nil
+}
+
+// PrintObj implements ResourcePrinter
+func (p *CustomColumnsPrinter) PrintObj(obj runtime.Object, w io.Writer) error {
+	objects, err := items(obj)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	if !p.NoHeaders {
+		headers := make([]string, len(p.columns))
+		for i, column := range p.columns {
+			headers[i] = column.Header
+		}
+		fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	}
+
+	for _, content := range objects {
+		cells := make([]string, len(p.paths))
+		for i, path := range p.paths {
+			results, err := path.FindResults(content)
+			if err != nil {
+				return fmt.Errorf("error executing column %s: %w", p.columns[i].Header, err)
+			}
+
+			var values []string
+			for _, result := range results {
+				for _, value := range result {
+					var buf bytes.Buffer
+					if err := path.PrintResults(&buf, []reflect.Value{value}); err != nil {
+						return err
+					}
+					values = append(values, buf.String())
+				}
+			}
+			cells[i] = "<none>"
+			if len(values) > 0 {
+				cells[i] = strings.Join(values, ",")
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,130 @@
+package printers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// PrintFlags holds the output flags shared by every command printing objects
+type PrintFlags struct {
+	// OutputFormat is the value of -o, such as json or jsonpath={.metadata.name}
+	OutputFormat string
+	// Template is the template used by -o jsonpath and -o go-template
+	Template string
+	// NoHeaders omits the header row of tables and custom columns
+	NoHeaders bool
+	// SortBy is the field specification to sort lists by
+	SortBy string
+}
+
+// NewPrintFlags creates empty print flags
+func NewPrintFlags() *PrintFlags {
+	return &PrintFlags{}
+}
+
+// AddFlags binds the print flags to the flag set
+func (f *PrintFlags) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&f.OutputFormat, "output", "o", f.OutputFormat,
+		"Output format. One of: json|yaml|name|wide|jsonpath=...|jsonpath-file=...|go-template=...|go-template-file=...|custom-columns=...|custom-columns-file=...")
+	flags.StringVar(&f.Template, "template", f.Template,
+		"Template string or path to template file to use when -o=go-template, -o=go-template-file, -o=jsonpath or -o=jsonpath-file")
+	flags.BoolVar(&f.NoHeaders, "no-headers", f.NoHeaders, "When using the default, wide or custom-column output format, don't print headers")
+	flags.StringVar(&f.SortBy, "sort-by", f.SortBy, "If non-empty, sort list types using this field specification, such as '{.metadata.name}'")
+}
+
+// IsTable reports whether the output format prints server-side tables
+func (f *PrintFlags) IsTable() bool {
+	return f.OutputFormat == "" || f.OutputFormat == "wide"
+}
+
+// ToTablePrinter returns the printer of the default and wide formats
+func (f *PrintFlags) ToTablePrinter() *TablePrinter {
+	return &TablePrinter{NoHeaders: f.NoHeaders, Wide: f.OutputFormat == "wide"}
+}
+
+// ToPrinter returns the printer of the output format, sorting lists when
+// --sort-by is set
+func (f *PrintFlags) ToPrinter() (ResourcePrinter, error) {
+	printer, err := f.toPrinter()
+	if err != nil {
+		return nil, err
+	}
+	return f.WithSorting(printer)
+}
+
+// WithSorting wraps the printer to sort lists when --sort-by is set
+func (f *PrintFlags) WithSorting(printer ResourcePrinter) (ResourcePrinter, error) {
+	if f.SortBy == "" {
+		return printer, nil
+	}
+	if _, err := RelaxedJSONPath(f.SortBy); err != nil {
+		return nil, fmt.Errorf("invalid --sort-by: %w", err)
+	}
+	return &SortingPrinter{SortField: f.SortBy, Delegate: printer}, nil
+}
+
+// toPrinter returns the printer of the output format
+func (f *PrintFlags) toPrinter() (ResourcePrinter, error) {
+	format, argument, _ := strings.Cut(f.OutputFormat, "=")
+	if argument == "" && f.Template != "" {
+		argument = f.Template
+	}
+
+	switch format {
+	case "", "wide":
+		return f.ToTablePrinter(), nil
+	case "json":
+		return &JSONPrinter{}, nil
+	case "yaml":
+		return &YAMLPrinter{}, nil
+	case "name":
+		return &NamePrinter{}, nil
+	case "jsonpath", "jsonpath-file":
+		template, err := templateArgument(format, argument)
+		if err != nil {
+			return nil, err
+		}
+		return NewJSONPathPrinter(template)
+	case "go-template", "go-template-file", "template", "templatefile":
+		template, err := templateArgument(format, argument)
+		if err != nil {
+			return nil, err
+		}
+		return NewGoTemplatePrinter(template)
+	case "custom-columns":
+		return NewCustomColumnsPrinter(argument, f.NoHeaders)
+	case "custom-columns-file":
+		if argument == "" {
+			return nil, fmt.Errorf("custom-columns-file format specified but no file given")
+		}
+		file, err := os.Open(argument)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read custom-columns file: %w", err)
+		}
+		defer file.Close()
+		return NewCustomColumnsPrinterFromTemplate(file, f.NoHeaders)
+	}
+
+	return nil, fmt.Errorf("unable to match a printer suitable for the output format %q, allowed formats are: "+
+		"custom-columns,custom-columns-file,go-template,go-template-file,json,jsonpath,jsonpath-file,name,wide,yaml", f.OutputFormat)
+}
+
+// templateArgument returns the template of a template format, reading it from
+// the file for the -file variants
+func templateArgument(format, argument string) (string, error) {
+	if argument == "" {
+		return "", fmt.Errorf("template format %s specified but no template given", format)
+	}
+	if !strings.HasSuffix(format, "-file") && format != "templatefile" {
+		return argument, nil
+	}
+
+	data, err := os.ReadFile(argument)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template file: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,54 @@
+package printers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// JSONPrinter prints objects as indented JSON
+type JSONPrinter struct{}
+
+// PrintObj implements ResourcePrinter
+func (p *JSONPrinter) PrintObj(obj runtime.Object, w io.Writer) error {
+	content, err := toMap(obj)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(content, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to encode object: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// YAMLPrinter prints objects as YAML, separating consecutive objects with ---
+type YAMLPrinter struct {
+	printed bool
+}
+
+// PrintObj implements ResourcePrinter
+func (p *YAMLPrinter) PrintObj(obj runtime.Object, w io.Writer) error {
+	content, err := toMap(obj)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("failed to encode object: %w", err)
+	}
+	if p.printed {
+		if _, err := fmt.Fprintln(w, "---"); err != nil {
+			return err
+		}
+	}
+	p.printed = true
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,59 @@
+package printers
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// jsonPathRegexp matches expressions optionally wrapped in braces and led by a dot
+var jsonPathRegexp = regexp.MustCompile(`^\{\.?([^{}]+)\}$|^\.?([^{}]+)$`)
+
+// RelaxedJSONPath turns a field specification such as .metadata.name or
+// metadata.name into the JSONPath template {.metadata.name}
+func RelaxedJSONPath(expression string) (string, error) {
+	if expression == "" {
+		return "", fmt.Errorf("JSONPath expression cannot be empty")
+	}
+	matches := jsonPathRegexp.FindStringSubmatch(expression)
+	if matches == nil {
+		return "", fmt.Errorf("unexpected path string, expected a 'name1.name2' or '.name1.name2' or '{name1.name2}' or '{.name1.name2}'")
+	}
+
+	field := matches[1]
+	if field == "" {
+		field = matches[2]
+	}
+	return fmt.Sprintf("{.%s}", field), nil
+}
+
+// JSONPathPrinter prints the result of a JSONPath template for each object
+type JSONPathPrinter struct {
+	template string
+	path     *jsonpath.JSONPath
+}
+
+// NewJSONPathPrinter parses the JSONPath template
+func NewJSONPathPrinter(template string) (*JSONPathPrinter, error) {
+	path := jsonpath.New("output").AllowMissingKeys(true)
+	if err := path.Parse(template); err != nil {
+		return nil, fmt.Errorf("error parsing jsonpath %s: %w", template, err)
+	}
+	return &JSONPathPrinter{template: template, path: path}, nil
+}
+
+// PrintObj implements ResourcePrinter
+func (p *JSONPathPrinter) PrintObj(obj runtime.Object, w io.Writer) error {
+	content, err := toMap(obj)
+	if err != nil {
+		return err
+	}
+
+	if err := p.path.Execute(w, content); err != nil {
+		return fmt.Errorf("error executing jsonpath %q: %w", p.template, err)
+	}
+	return nil
+}
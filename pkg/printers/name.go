@@ -0,0 +1,52 @@
+package printers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// NamePrinter prints <kind>[.<group>]/<name> for each object, or the bare name
+// of objects without a kind such as kubeconfig entries
+type NamePrinter struct{}
+
+// PrintObj implements ResourcePrinter
+func (p *NamePrinter) PrintObj(obj runtime.Object, w io.Writer) error {
+	objects, err := items(obj)
+	if err != nil {
+		return err
+	}
+
+	for _, content := range objects {
+		item := &unstructured.Unstructured{Object: content}
+		name := item.GetName()
+		if name == "" {
+			name, _, _ = unstructured.NestedString(content, "name")
+		}
+
+		kind := item.GetKind()
+		if kind == "" {
+			if _, err := fmt.Fprintln(w, name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		gv, err := schema.ParseGroupVersion(item.GetAPIVersion())
+		if err != nil {
+			return fmt.Errorf("invalid apiVersion %q: %w", item.GetAPIVersion(), err)
+		}
+		resource := strings.ToLower(kind)
+		if gv.Group != "" {
+			resource += "." + gv.Group
+		}
+		if _, err := fmt.Fprintf(w, "%s/%s\n", resource, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
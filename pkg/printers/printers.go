@@ -0,0 +1,60 @@
+package printers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ResourcePrinter prints objects to a writer
+type ResourcePrinter interface {
+	// PrintObj writes the object, or each item of a list, to the writer
+	PrintObj(obj runtime.Object, w io.Writer) error
+}
+
+// ResourcePrinterFunc adapts a function to the ResourcePrinter interface
+type ResourcePrinterFunc func(obj runtime.Object, w io.Writer) error
+
+// PrintObj implements ResourcePrinter
+func (fn ResourcePrinterFunc) PrintObj(obj runtime.Object, w io.Writer) error {
+	return fn(obj, w)
+}
+
+// toMap returns the JSON content of an object as nested maps
+func toMap(obj runtime.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		return u.UnstructuredContent(), nil
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode object: %w", err)
+	}
+	var content map[string]interface{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("failed to decode object: %w", err)
+	}
+	return content, nil
+}
+
+// items returns the items of a list object, or the object itself
+func items(obj runtime.Object) ([]map[string]interface{}, error) {
+	content, err := toMap(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	list, ok := content["items"].([]interface{})
+	if !ok {
+		return []map[string]interface{}{content}, nil
+	}
+	result := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		if m, ok := item.(map[string]interface{}); ok {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
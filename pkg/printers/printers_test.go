@@ -0,0 +1,171 @@
+package printers
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// testPods returns a list of two pods in reverse name order
+func testPods(t *testing.T) *unstructured.UnstructuredList {
+	t.Helper()
+
+	list := &unstructured.UnstructuredList{}
+	for _, manifest := range []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  namespace: shop
+  labels:
+    app: web
+spec:
+  priority: 10
+  containers:
+  - name: nginx
+    image: nginx:1.25
+  - name: sidecar
+    image: envoy:1.28
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: db
+  namespace: shop
+spec:
+  priority: 2
+  containers:
+  - name: postgres
+    image: postgres:16
+`} {
+		obj := unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil {
+			t.Fatalf("Failed to decode pod: %v", err)
+		}
+		list.Items = append(list.Items, obj)
+	}
+	list.SetAPIVersion("v1")
+	list.SetKind("List")
+	return list
+}
+
+// testTable returns a server-side table of the test pods
+func testTable(t *testing.T) *metav1.Table {
+	t.Helper()
+
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Containers", Type: "integer"},
+			{Name: "Labels", Type: "string", Priority: 1},
+		},
+	}
+	for i := range testPods(t).Items {
+		pod := testPods(t).Items[i]
+		containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", "containers")
+		var labels interface{}
+		if app := pod.GetLabels()["app"]; app != "" {
+			labels = "app=" + app
+		}
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells:  []interface{}{pod.GetName(), float64(len(containers)), labels},
+			Object: runtime.RawExtension{Object: &pod},
+		})
+	}
+	return table
+}
+
+func TestPrinters(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "template")
+	if err := os.WriteFile(templateFile, []byte(`{{range .items}}{{.metadata.name}}{{"\n"}}{{end}}`), 0600); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	jsonPathFile := filepath.Join(t.TempDir(), "jsonpath")
+	if err := os.WriteFile(jsonPathFile, []byte(`{range .items[*]}{.metadata.name}{"\n"}{end}`), 0600); err != nil {
+		t.Fatalf("Failed to write jsonpath: %v", err)
+	}
+	columnsFile := filepath.Join(t.TempDir(), "columns")
+	if err := os.WriteFile(columnsFile, []byte("NAME IMAGES\n.metadata.name .spec.containers[*].image\n"), 0600); err != nil {
+		t.Fatalf("Failed to write columns: %v", err)
+	}
+
+	tests := []struct {
+		golden string
+		flags  PrintFlags
+		obj    func(t *testing.T) runtime.Object
+	}{
+		{golden: "json", flags: PrintFlags{OutputFormat: "json"}, obj: func(t *testing.T) runtime.Object { return &testPods(t).Items[1] }},
+		{golden: "json-list", flags: PrintFlags{OutputFormat: "json"}, obj: func(t *testing.T) runtime.Object { return testPods(t) }},
+		{golden: "yaml", flags: PrintFlags{OutputFormat: "yaml"}, obj: func(t *testing.T) runtime.Object { return testPods(t) }},
+		{golden: "name", flags: PrintFlags{OutputFormat: "name"}, obj: func(t *testing.T) runtime.Object { return testPods(t) }},
+		{golden: "jsonpath", flags: PrintFlags{OutputFormat: "jsonpath={.items[*].metadata.name}"}, obj: func(t *testing.T) runtime.Object { return testPods(t) }},
+		{golden: "jsonpath-file", flags: PrintFlags{OutputFormat: "jsonpath-file=" + jsonPathFile}, obj: func(t *testing.T) runtime.Object { return testPods(t) }},
+		{golden: "go-template", flags: PrintFlags{OutputFormat: "go-template", Template: `{{range .items}}{{.metadata.name}}:{{len .spec.containers}}{{"\n"}}{{end}}`}, obj: func(t *testing.T) runtime.Object { return testPods(t) }},
+		{golden: "go-template-file", flags: PrintFlags{OutputFormat: "go-template-file=" + templateFile}, obj: func(t *testing.T) runtime.Object { return testPods(t) }},
+		{golden: "custom-columns", flags: PrintFlags{OutputFormat: "custom-columns=NAME:.metadata.name,APP:.metadata.labels.app,IMAGES:.spec.containers[*].image"}, obj: func(t *testing.T) runtime.Object { return testPods(t) }},
+		{golden: "custom-columns-file", flags: PrintFlags{OutputFormat: "custom-columns-file=" + columnsFile, NoHeaders: true}, obj: func(t *testing.T) runtime.Object { return testPods(t) }},
+		{golden: "sort-by", flags: PrintFlags{OutputFormat: "name", SortBy: ".spec.priority"}, obj: func(t *testing.T) runtime.Object { return testPods(t) }},
+		{golden: "table", flags: PrintFlags{}, obj: func(t *testing.T) runtime.Object { return testTable(t) }},
+		{golden: "table-wide", flags: PrintFlags{OutputFormat: "wide", NoHeaders: true, SortBy: "{.metadata.name}"}, obj: func(t *testing.T) runtime.Object { return testTable(t) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			printer, err := tt.flags.ToPrinter()
+			if err != nil {
+				t.Fatalf("ToPrinter() error = %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := printer.PrintObj(tt.obj(t), &buf); err != nil {
+				t.Fatalf("PrintObj() error = %v", err)
+			}
+
+			goldenFile := filepath.Join("testdata", tt.golden+".golden")
+			if *update {
+				if err := os.WriteFile(goldenFile, buf.Bytes(), 0644); err != nil {
+					t.Fatalf("Failed to update golden file: %v", err)
+				}
+			}
+			expected, err := os.ReadFile(goldenFile)
+			if err != nil {
+				t.Fatalf("Failed to read golden file: %v", err)
+			}
+			if buf.String() != string(expected) {
+				t.Errorf("PrintObj() =\n%s\nwant\n%s", buf.String(), expected)
+			}
+		})
+	}
+}
+
+func TestPrintFlagsInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags PrintFlags
+	}{
+		{name: "unknown format", flags: PrintFlags{OutputFormat: "xml"}},
+		{name: "jsonpath without template", flags: PrintFlags{OutputFormat: "jsonpath"}},
+		{name: "invalid jsonpath", flags: PrintFlags{OutputFormat: "jsonpath={.items["}},
+		{name: "invalid go template", flags: PrintFlags{OutputFormat: "go-template={{.name"}},
+		{name: "invalid custom columns", flags: PrintFlags{OutputFormat: "custom-columns=NAME"}},
+		{name: "missing template file", flags: PrintFlags{OutputFormat: "go-template-file=/nonexistent"}},
+		{name: "invalid sort field", flags: PrintFlags{OutputFormat: "json", SortBy: "{{.name}}"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.flags.ToPrinter(); err == nil {
+				t.Error("ToPrinter() expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package printers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// SortingPrinter sorts the items of lists and the rows of tables by a field
+// before passing them to the delegate printer
+type SortingPrinter struct {
+	// SortField is the field specification, such as .metadata.name
+	SortField string
+	// Delegate prints the sorted object
+	Delegate ResourcePrinter
+}
+
+// PrintObj implements ResourcePrinter
+func (p *SortingPrinter) PrintObj(obj runtime.Object, w io.Writer) error {
+	switch o := obj.(type) {
+	case *metav1.Table:
+		if err := SortTableRows(o, p.SortField); err != nil {
+			return err
+		}
+	case *unstructured.UnstructuredList:
+		if err := SortItems(o.Items, p.SortField); err != nil {
+			return err
+		}
+	}
+	return p.Delegate.PrintObj(obj, w)
+}
+
+// SortItems sorts objects by the value of the field specification. Objects
+// missing the field come first.
+func SortItems(objects []unstructured.Unstructured, field string) error {
+	keys := make([]interface{}, len(objects))
+	for i := range objects {
+		key, err := sortKey(objects[i].Object, field)
+		if err != nil {
+			return err
+		}
+		keys[i] = key
+	}
+
+	sort.Stable(&sorter{
+		len:  len(objects),
+		less: func(i, j int) bool { return less(keys[i], keys[j]) },
+		swap: func(i, j int) {
+			objects[i], objects[j] = objects[j], objects[i]
+			keys[i], keys[j] = keys[j], keys[i]
+		},
+	})
+	return nil
+}
+
+// SortTableRows sorts table rows by the field of the object included in each
+// row. The server includes only the metadata unless the full object is requested.
+func SortTableRows(table *metav1.Table, field string) error {
+	keys := make([]interface{}, len(table.Rows))
+	for i, row := range table.Rows {
+		var content map[string]interface{}
+		switch {
+		case row.Object.Object != nil:
+			m, err := toMap(row.Object.Object)
+			if err != nil {
+				return err
+			}
+			content = m
+		case len(row.Object.Raw) > 0:
+			if err := json.Unmarshal(row.Object.Raw, &content); err != nil {
+				return fmt.Errorf("failed to decode table row: %w", err)
+			}
+		}
+
+		key, err := sortKey(content, field)
+		if err != nil {
+			return err
+		}
+		keys[i] = key
+	}
+
+	rows := table.Rows
+	sort.Stable(&sorter{
+		len:  len(rows),
+		less: func(i, j int) bool { return less(keys[i], keys[j]) },
+		swap: func(i, j int) {
+			rows[i], rows[j] = rows[j], rows[i]
+			keys[i], keys[j] = keys[j], keys[i]
+		},
+	})
+	return nil
+}
+
+// sortKey returns the first value of the field in the object, or nil when missing
+func sortKey(content map[string]interface{}, field string) (interface{}, error) {
+	template, err := RelaxedJSONPath(field)
+	if err != nil {
+		return nil, err
+	}
+	path := jsonpath.New("sort").AllowMissingKeys(true)
+	if err := path.Parse(template); err != nil {
+		return nil, fmt.Errorf("error parsing sort field %s: %w", field, err)
+	}
+
+	results, err := path.FindResults(content)
+	if err != nil {
+		return nil, fmt.Errorf("error sorting by %s: %w", field, err)
+	}
+	if len(results) == 0 || len(results[0]) == 0 {
+		return nil, nil
+	}
+
+	value := results[0][0]
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return nil, nil
+	}
+	return value.Interface(), nil
+}
+
+// less orders missing values first, numbers numerically and anything else by
+// its string form
+func less(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b != nil
+	}
+
+	an, aIsNumber := toFloat(a)
+	bn, bIsNumber := toFloat(b)
+	if aIsNumber && bIsNumber {
+		return an < bn
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b)) < 0
+}
+
+// toFloat converts JSON numbers to float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// sorter implements sort.Interface with functions
+type sorter struct {
+	len  int
+	less func(i, j int) bool
+	swap func(i, j int)
+}
+
+func (s *sorter) Len() int           { return s.len }
+func (s *sorter) Less(i, j int) bool { return s.less(i, j) }
+func (s *sorter) Swap(i, j int)      { s.swap(i, j) }
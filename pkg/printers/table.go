@@ -0,0 +1,154 @@
+package printers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// now returns the current time, replaced in tests for stable ages
+var now = time.Now
+
+// TablePrinter prints server-side Tables as aligned columns
+type TablePrinter struct {
+	// NoHeaders omits the header row
+	NoHeaders bool
+	// Wide prints the additional columns with a priority above zero
+	Wide bool
+	// WithNamespace prepends a NAMESPACE column
+	WithNamespace bool
+}
+
+// PrintObj implements ResourcePrinter. Objects other than tables are printed
+// with the default NAME and AGE columns.
+func (p *TablePrinter) PrintObj(obj runtime.Object, w io.Writer) error {
+	switch o := obj.(type) {
+	case *metav1.Table:
+		return p.PrintTable(o, w)
+	case *unstructured.UnstructuredList:
+		return p.PrintTable(DefaultTable(o.Items), w)
+	case *unstructured.Unstructured:
+		return p.PrintTable(DefaultTable([]unstructured.Unstructured{*o}), w)
+	}
+	return fmt.Errorf("table output is not supported for %T", obj)
+}
+
+// PrintTable writes the rows of the table to the writer
+func (p *TablePrinter) PrintTable(table *metav1.Table, w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+
+	var columns []int
+	for i, column := range table.ColumnDefinitions {
+		if column.Priority == 0 || p.Wide {
+			columns = append(columns, i)
+		}
+	}
+
+	if !p.NoHeaders {
+		var headers []string
+		if p.WithNamespace {
+			headers = append(headers, "NAMESPACE")
+		}
+		for _, i := range columns {
+			headers = append(headers, strings.ToUpper(table.ColumnDefinitions[i].Name))
+		}
+		fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	}
+
+	for _, row := range table.Rows {
+		var cells []string
+		if p.WithNamespace {
+			cells = append(cells, rowNamespace(row))
+		}
+		for _, i := range columns {
+			var value interface{}
+			if i < len(row.Cells) {
+				value = row.Cells[i]
+			}
+			cells = append(cells, formatCell(value, table.ColumnDefinitions[i]))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// rowNamespace returns the namespace from the object metadata included in a row
+func rowNamespace(row metav1.TableRow) string {
+	if row.Object.Object != nil {
+		if accessor, ok := row.Object.Object.(metav1.Object); ok {
+			return accessor.GetNamespace()
+		}
+	}
+
+	var object metav1.PartialObjectMetadata
+	if len(row.Object.Raw) == 0 || json.Unmarshal(row.Object.Raw, &object) != nil {
+		return ""
+	}
+	return object.Namespace
+}
+
+// formatCell renders a table cell according to its column definition
+func formatCell(value interface{}, column metav1.TableColumnDefinition) string {
+	if value == nil {
+		return "<none>"
+	}
+
+	if column.Type == "date" || column.Format == "date" {
+		if s, ok := value.(string); ok {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				return duration.HumanDuration(now().Sub(t))
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		if v == float64(int64(v)) {
+			return fmt.Sprintf("%d", int64(v))
+		}
+		return fmt.Sprintf("%v", v)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// DefaultTable builds a NAME and AGE table for objects of servers that do not
+// support server-side printing
+func DefaultTable(objects []unstructured.Unstructured) *metav1.Table {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Age", Type: "date"},
+		},
+	}
+
+	for i := range objects {
+		obj := &objects[i]
+		var age interface{}
+		if created := obj.GetCreationTimestamp(); !created.IsZero() {
+			age = created.UTC().Format(time.RFC3339)
+		}
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells:  []interface{}{obj.GetName(), age},
+			Object: runtime.RawExtension{Object: obj},
+		})
+	}
+	return table
+}
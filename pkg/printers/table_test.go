@@ -0,0 +1,73 @@
+package printers
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestTablePrinter(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Ready", Type: "integer"},
+			{Name: "Created", Type: "date"},
+			{Name: "Node", Type: "string", Priority: 1},
+		},
+		Rows: []metav1.TableRow{
+			{
+				Cells:  []interface{}{"web", float64(1), "2024-01-01T07:00:00Z", "node-1"},
+				Object: runtime.RawExtension{Raw: []byte(`{"metadata":{"name":"web","namespace":"shop"}}`)},
+			},
+			{
+				Cells:  []interface{}{"db", float64(0), nil, nil},
+				Object: runtime.RawExtension{Raw: []byte(`{"metadata":{"name":"db","namespace":"data"}}`)},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		printer  *TablePrinter
+		expected string
+	}{
+		{
+			name:    "default columns",
+			printer: &TablePrinter{},
+			expected: "NAME   READY   CREATED\n" +
+				"web    1       5h\n" +
+				"db     0       <none>\n",
+		},
+		{
+			name:    "wide with namespace",
+			printer: &TablePrinter{Wide: true, WithNamespace: true},
+			expected: "NAMESPACE   NAME   READY   CREATED   NODE\n" +
+				"shop        web    1       5h        node-1\n" +
+				"data        db     0       <none>    <none>\n",
+		},
+		{
+			name:    "no headers",
+			printer: &TablePrinter{NoHeaders: true},
+			expected: "web   1   5h\n" +
+				"db    0   <none>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.printer.PrintTable(table, &buf); err != nil {
+				t.Fatalf("PrintTable() error = %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("PrintTable() =\n%s\nwant\n%s", buf.String(), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package printers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// GoTemplatePrinter prints the result of a Go template for each object
+type GoTemplatePrinter struct {
+	template *template.Template
+}
+
+// NewGoTemplatePrinter parses the Go template. Besides the standard functions,
+// templates may use exists and base64decode as in kubectl.
+func NewGoTemplatePrinter(text string) (*GoTemplatePrinter, error) {
+	t, err := template.New("output").Funcs(template.FuncMap{
+		"exists":       exists,
+		"base64decode": base64decode,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template %s: %w", text, err)
+	}
+	return &GoTemplatePrinter{template: t}, nil
+}
+
+// PrintObj implements ResourcePrinter
+func (p *GoTemplatePrinter) PrintObj(obj runtime.Object, w io.Writer) error {
+	content, err := toMap(obj)
+	if err != nil {
+		return err
+	}
+
+	if err := p.template.Execute(w, content); err != nil {
+		return fmt.Errorf("error executing template: %w", err)
+	}
+	return nil
+}
+
+// exists reports whether the nested keys are present in the map
+func exists(item interface{}, keys ...string) bool {
+	for _, key := range keys {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if item, ok = m[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// base64decode decodes a base64 string such as a secret value
+func base64decode(value string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", fmt.Errorf("base64 decode failed: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,193 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// DisplayNameAnnotation holds the human readable name of a project
+const DisplayNameAnnotation = "openshift.io/display-name"
+
+// DescriptionAnnotation holds the description of a project
+const DescriptionAnnotation = "openshift.io/description"
+
+var (
+	// ProjectsResource is the OpenShift project resource
+	ProjectsResource = schema.GroupVersionResource{Group: "project.openshift.io", Version: "v1", Resource: "projects"}
+	// ProjectRequestsResource is the OpenShift resource creating projects
+	ProjectRequestsResource = schema.GroupVersionResource{Group: "project.openshift.io", Version: "v1", Resource: "projectrequests"}
+	// NamespacesResource is the namespace resource used on Kubernetes
+	NamespacesResource = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	// RoleBindingsResource is the resource granting the admin role of a
+	// namespace created on Kubernetes
+	RoleBindingsResource = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}
+)
+
+// AdminRole is the cluster role bound to the creator of a namespace
+const AdminRole = "admin"
+
+// Project is a project, or a namespace on Kubernetes, the user can access
+type Project struct {
+	// Name is the name of the project
+	Name string
+	// DisplayName is the human readable name of the project, if any
+	DisplayName string
+	// Status is the phase of the project, Active or Terminating
+	Status string
+}
+
+// String returns the name of the project followed by its display name
+func (p Project) String() string {
+	if p.DisplayName == "" || p.DisplayName == p.Name {
+		return p.Name
+	}
+	return p.Name + " - " + p.DisplayName
+}
+
+// List returns the projects the user can access sorted by name. The OpenShift
+// project API only returns the projects of the user; servers without it fall
+// back to the namespaces.
+func List(ctx context.Context, client dynamic.Interface) ([]Project, error) {
+	list, err := client.Resource(ProjectsResource).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		list, err = client.Resource(NamespacesResource).List(ctx, metav1.ListOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	projects := make([]Project, 0, len(list.Items))
+	for i := range list.Items {
+		projects = append(projects, fromObject(&list.Items[i]))
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects, nil
+}
+
+// Get returns the project, falling back to the namespace on servers without
+// the OpenShift project API
+func Get(ctx context.Context, client dynamic.Interface, name string) (*Project, error) {
+	obj, err := client.Resource(ProjectsResource).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) && !isObjectNotFound(err, ProjectsResource) {
+		obj, err = client.Resource(NamespacesResource).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	project := fromObject(obj)
+	return &project, nil
+}
+
+// Request describes a project to create
+type Request struct {
+	// Name is the name of the project
+	Name string
+	// DisplayName is the human readable name of the project
+	DisplayName string
+	// Description is the description of the project
+	Description string
+}
+
+// Create creates the project through a ProjectRequest, which makes the user
+// an admin of the project. Servers without the OpenShift project API get a
+// namespace instead, with the admin cluster role bound to the user returned
+// by the admin function, which must name a user. The namespace is deleted
+// again when the role cannot be bound.
+func Create(ctx context.Context, client dynamic.Interface, request Request, admin func() (string, error)) error {
+	projectRequest := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion":  ProjectRequestsResource.GroupVersion().String(),
+		"kind":        "ProjectRequest",
+		"metadata":    map[string]interface{}{"name": request.Name},
+		"displayName": request.DisplayName,
+		"description": request.Description,
+	}}
+	_, err := client.Resource(ProjectRequestsResource).Create(ctx, projectRequest, metav1.CreateOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to create project %q: %w", request.Name, err)
+	}
+
+	user, err := admin()
+	if err != nil {
+		return err
+	}
+	if user == "" {
+		return fmt.Errorf("cannot create namespace %q: no user to bind the %s role to", request.Name, AdminRole)
+	}
+
+	namespace := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]interface{}{"name": request.Name},
+	}}
+	annotations := map[string]string{}
+	if request.DisplayName != "" {
+		annotations[DisplayNameAnnotation] = request.DisplayName
+	}
+	if request.Description != "" {
+		annotations[DescriptionAnnotation] = request.Description
+	}
+	if len(annotations) > 0 {
+		namespace.SetAnnotations(annotations)
+	}
+	if _, err := client.Resource(NamespacesResource).Create(ctx, namespace, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create namespace %q: %w", request.Name, err)
+	}
+
+	roleBinding := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": RoleBindingsResource.GroupVersion().String(),
+		"kind":       "RoleBinding",
+		"metadata":   map[string]interface{}{"name": AdminRole, "namespace": request.Name},
+		"roleRef": map[string]interface{}{
+			"apiGroup": RoleBindingsResource.Group,
+			"kind":     "ClusterRole",
+			"name":     AdminRole,
+		},
+		"subjects": []interface{}{map[string]interface{}{
+			"apiGroup": RoleBindingsResource.Group,
+			"kind":     "User",
+			"name":     user,
+		}},
+	}}
+	if _, err := client.Resource(RoleBindingsResource).Namespace(request.Name).Create(ctx, roleBinding, metav1.CreateOptions{}); err != nil {
+		err = fmt.Errorf("failed to bind the %s role of namespace %q to %s: %w", AdminRole, request.Name, user, err)
+		// Do not leave behind a namespace the user may not administer, which
+		// would also make a retry fail
+		deleteErr := client.Resource(NamespacesResource).Delete(ctx, request.Name, metav1.DeleteOptions{})
+		if deleteErr != nil && !apierrors.IsNotFound(deleteErr) {
+			return fmt.Errorf("%w, and namespace %q was left behind: %v", err, request.Name, deleteErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// isObjectNotFound reports whether the not found error is about a missing
+// object of the resource rather than a missing resource
+func isObjectNotFound(err error, resource schema.GroupVersionResource) bool {
+	status, ok := err.(apierrors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return false
+	}
+	details := status.Status().Details
+	return details.Name != "" && details.Group == resource.Group && details.Kind == resource.Resource
+}
+
+// fromObject returns the project of a Project or Namespace object
+func fromObject(obj *unstructured.Unstructured) Project {
+	status, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	return Project{
+		Name:        obj.GetName(),
+		DisplayName: obj.GetAnnotations()[DisplayNameAnnotation],
+		Status:      status,
+	}
+}
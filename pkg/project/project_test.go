@@ -0,0 +1,68 @@
+package project
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/withlin/oc-demo/pkg/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// newTestClient creates a dynamic client for the fake API server
+func newTestClient(t *testing.T, server *testutil.FakeAPIServer) dynamic.Interface {
+	t.Helper()
+
+	config, err := clientcmd.NewDefaultClientConfig(*server.Kubeconfig("default"), nil).ClientConfig()
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	config.QPS, config.Burst = 100, 100
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestListAndGet(t *testing.T) {
+	server := testutil.NewFakeAPIServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+	ctx := context.Background()
+	server.AddYAML("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: web\nstatus:\n  phase: Active\n")
+	server.AddYAML("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: db\nstatus:\n  phase: Terminating\n")
+
+	// Without the project API the namespaces are used
+	projects, err := List(ctx, client)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	expected := []Project{{Name: "db", Status: "Terminating"}, {Name: "web", Status: "Active"}}
+	if !reflect.DeepEqual(projects, expected) {
+		t.Errorf("List() = %+v, want %+v", projects, expected)
+	}
+	if project, err := Get(ctx, client, "web"); err != nil || project.Name != "web" {
+		t.Errorf("Get() = %+v, %v, want the web namespace", project, err)
+	}
+
+	// With the project API only the projects of the user are used
+	server.AddResource("project.openshift.io/v1", metav1.APIResource{Name: "projects", SingularName: "project", Kind: "Project"})
+	server.AddYAML("apiVersion: project.openshift.io/v1\nkind: Project\nmetadata:\n  name: web\n  annotations:\n    openshift.io/display-name: Web Shop\nstatus:\n  phase: Active\n")
+	projects, err = List(ctx, client)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	expected = []Project{{Name: "web", DisplayName: "Web Shop", Status: "Active"}}
+	if !reflect.DeepEqual(projects, expected) {
+		t.Errorf("List() = %+v, want %+v", projects, expected)
+	}
+	if projects[0].String() != "web - Web Shop" {
+		t.Errorf("String() = %q, want %q", projects[0].String(), "web - Web Shop")
+	}
+	if _, err := Get(ctx, client, "db"); err == nil {
+		t.Error("Get() expected an error for a namespace that is not a project")
+	}
+}
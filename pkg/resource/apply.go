@@ -0,0 +1,444 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// LastAppliedAnnotation holds the configuration of the last client-side apply,
+// the same annotation kubectl uses so both tools can apply the same objects
+const LastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// DefaultFieldManager is the field manager of server-side apply requests
+const DefaultFieldManager = "skectl"
+
+// DryRun is the dry-run strategy of a mutating command
+type DryRun string
+
+const (
+	// DryRunNone sends the changes to the server
+	DryRunNone DryRun = "none"
+	// DryRunClient only computes the changes locally
+	DryRunClient DryRun = "client"
+	// DryRunServer sends the changes to the server without persisting them
+	DryRunServer DryRun = "server"
+)
+
+// ParseDryRun parses the value of a --dry-run flag
+func ParseDryRun(value string) (DryRun, error) {
+	switch DryRun(value) {
+	case "", DryRunNone:
+		return DryRunNone, nil
+	case DryRunClient, DryRunServer:
+		return DryRun(value), nil
+	}
+	return "", fmt.Errorf("invalid dry-run value %q, must be \"none\", \"server\" or \"client\"", value)
+}
+
+// serverOptions returns the dryRun request option of the strategy
+func (d DryRun) serverOptions() []string {
+	if d == DryRunServer {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
+
+// Suffix returns the note appended to the messages of a dry run
+func (d DryRun) Suffix() string {
+	switch d {
+	case DryRunClient:
+		return " (dry run)"
+	case DryRunServer:
+		return " (server dry run)"
+	}
+	return ""
+}
+
+// DefaultPruneKinds are the kinds searched by Prune besides the applied ones,
+// the default --prune-allowlist of kubectl
+var DefaultPruneKinds = []schema.GroupVersionKind{
+	{Version: "v1", Kind: "ConfigMap"},
+	{Version: "v1", Kind: "Endpoints"},
+	{Version: "v1", Kind: "Namespace"},
+	{Version: "v1", Kind: "PersistentVolumeClaim"},
+	{Version: "v1", Kind: "PersistentVolume"},
+	{Version: "v1", Kind: "Pod"},
+	{Version: "v1", Kind: "ReplicationController"},
+	{Version: "v1", Kind: "Secret"},
+	{Version: "v1", Kind: "Service"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+	{Group: "batch", Version: "v1", Kind: "CronJob"},
+	{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "ReplicaSet"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+}
+
+// ParsePruneAllowlist parses the group/version/kind values of a
+// --prune-allowlist flag, such as core/v1/ConfigMap or apps/v1/Deployment
+func ParsePruneAllowlist(values []string) ([]schema.GroupVersionKind, error) {
+	kinds := make([]schema.GroupVersionKind, 0, len(values))
+	for _, value := range values {
+		parts := strings.Split(value, "/")
+		if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid prune allowlist entry %q, expected group/version/kind", value)
+		}
+		group := parts[0]
+		if group == "core" {
+			group = ""
+		}
+		kinds = append(kinds, schema.GroupVersionKind{Group: group, Version: parts[1], Kind: parts[2]})
+	}
+	return kinds, nil
+}
+
+// Result is the outcome of applying or pruning an object
+type Result struct {
+	// Object is the object returned by the server, or the local object on a
+	// client dry run
+	Object *unstructured.Unstructured
+	// Mapping is the resource of the object
+	Mapping *meta.RESTMapping
+	// Operation is created, configured, unchanged, serverside-applied or pruned
+	Operation string
+}
+
+// String returns the kubectl-style message of the result, such as
+// "deployment.apps/web configured"
+func (r *Result) String() string {
+	return fmt.Sprintf("%s/%s %s", QualifiedKind(r.Mapping), r.Object.GetName(), r.Operation)
+}
+
+// QualifiedKind returns the lower case kind with its group, like deployment.apps
+func QualifiedKind(mapping *meta.RESTMapping) string {
+	kind := mapping.GroupVersionKind
+	if kind.Group == "" {
+		return strings.ToLower(kind.Kind)
+	}
+	return strings.ToLower(kind.Kind) + "." + kind.Group
+}
+
+// Applier creates or updates objects to match their manifests. Server-side
+// apply is used when enabled and supported by the server, falling back to a
+// client-side three-way merge based on the last applied configuration.
+type Applier struct {
+	// Client sends the requests
+	Client dynamic.Interface
+	// Mapper resolves the resource of every object
+	Mapper meta.RESTMapper
+	// Namespace is used for namespaced objects without a namespace
+	Namespace string
+	// EnforceNamespace rejects objects from other namespaces than Namespace
+	EnforceNamespace bool
+	// ServerSide enables server-side apply
+	ServerSide bool
+	// FieldManager is the manager owning the applied fields
+	FieldManager string
+	// ForceConflicts takes over fields owned by other managers on server-side apply
+	ForceConflicts bool
+	// DryRun is the dry-run strategy
+	DryRun DryRun
+}
+
+// Apply creates or updates the object
+func (a *Applier) Apply(ctx context.Context, obj *unstructured.Unstructured) (*Result, error) {
+	mapping, err := a.Prepare(obj)
+	if err != nil {
+		return nil, err
+	}
+	resourceClient := ClientFor(a.Client, mapping, obj.GetNamespace())
+
+	if a.ServerSide {
+		result, err := a.serverSideApply(ctx, resourceClient, mapping, obj)
+		// Servers without server-side apply reject the patch type
+		if err == nil || !apierrors.IsUnsupportedMediaType(err) {
+			return result, err
+		}
+		a.ServerSide = false
+	}
+	return a.clientSideApply(ctx, resourceClient, mapping, obj)
+}
+
+// Prepare resolves the resource of the object and sets its namespace
+func (a *Applier) Prepare(obj *unstructured.Unstructured) (*meta.RESTMapping, error) {
+	return PrepareObject(a.Mapper, obj, a.Namespace, a.EnforceNamespace)
+}
+
+// PrepareObject resolves the resource of an object read from a manifest and
+// sets its namespace, defaulting to the given one. Objects from another
+// namespace are rejected when enforceNamespace is set.
+func PrepareObject(mapper meta.RESTMapper, obj *unstructured.Unstructured, namespace string, enforceNamespace bool) (*meta.RESTMapping, error) {
+	gvk := obj.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("resource mapping not found for name: %q namespace: %q from %q: no matches for kind %q in version %q",
+				obj.GetName(), obj.GetNamespace(), gvk.GroupVersion(), gvk.Kind, gvk.GroupVersion())
+		}
+		return nil, fmt.Errorf("failed to resolve %s: %w", gvk, err)
+	}
+	if obj.GetName() == "" {
+		return nil, fmt.Errorf("%s has no name", gvk.Kind)
+	}
+
+	if !Namespaced(mapping) {
+		obj.SetNamespace("")
+		return mapping, nil
+	}
+	switch {
+	case obj.GetNamespace() == "":
+		obj.SetNamespace(namespace)
+	case enforceNamespace && obj.GetNamespace() != namespace:
+		return nil, fmt.Errorf("the namespace from the provided object %q does not match the namespace %q. You must pass '--namespace=%s' to perform this operation",
+			obj.GetNamespace(), namespace, obj.GetNamespace())
+	}
+	return mapping, nil
+}
+
+// serverSideApply sends the object as an apply patch
+func (a *Applier) serverSideApply(ctx context.Context, resourceClient dynamic.ResourceInterface, mapping *meta.RESTMapping, obj *unstructured.Unstructured) (*Result, error) {
+	result := &Result{Object: obj, Mapping: mapping, Operation: "serverside-applied"}
+	if a.DryRun == DryRunClient {
+		return result, nil
+	}
+
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", obj.GetName(), err)
+	}
+	force := a.ForceConflicts
+	applied, err := resourceClient.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+		FieldManager: a.fieldManager(),
+		Force:        &force,
+		DryRun:       a.DryRun.serverOptions(),
+	})
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			return nil, fmt.Errorf("failed to apply %s/%s: %w\nThe conflicting fields are owned by other managers. Use --force-conflicts to take them over",
+				QualifiedKind(mapping), obj.GetName(), err)
+		}
+		return nil, fmt.Errorf("failed to apply %s/%s: %w", QualifiedKind(mapping), obj.GetName(), err)
+	}
+	result.Object = applied
+	return result, nil
+}
+
+// clientSideApply creates the object, or patches it with the three-way merge
+// of the last applied configuration, the manifest and the live object
+func (a *Applier) clientSideApply(ctx context.Context, resourceClient dynamic.ResourceInterface, mapping *meta.RESTMapping, obj *unstructured.Unstructured) (*Result, error) {
+	modified, err := withLastApplied(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := resourceClient.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		result := &Result{Object: modified, Mapping: mapping, Operation: "created"}
+		if a.DryRun == DryRunClient {
+			return result, nil
+		}
+		created, err := resourceClient.Create(ctx, modified, metav1.CreateOptions{
+			FieldManager: a.fieldManager(),
+			DryRun:       a.DryRun.serverOptions(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s/%s: %w", QualifiedKind(mapping), obj.GetName(), err)
+		}
+		result.Object = created
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s/%s: %w", QualifiedKind(mapping), obj.GetName(), err)
+	}
+
+	patchType, patch, err := threeWayPatch(mapping.GroupVersionKind, current, modified)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute patch for %s/%s: %w", QualifiedKind(mapping), obj.GetName(), err)
+	}
+	result := &Result{Object: current, Mapping: mapping, Operation: "unchanged"}
+	if string(patch) == "{}" {
+		return result, nil
+	}
+
+	result.Operation = "configured"
+	if a.DryRun == DryRunClient {
+		return result, nil
+	}
+	patched, err := resourceClient.Patch(ctx, obj.GetName(), patchType, patch, metav1.PatchOptions{
+		FieldManager: a.fieldManager(),
+		DryRun:       a.DryRun.serverOptions(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch %s/%s: %w", QualifiedKind(mapping), obj.GetName(), err)
+	}
+	result.Object = patched
+	return result, nil
+}
+
+// fieldManager returns the field manager, defaulting to skectl
+func (a *Applier) fieldManager() string {
+	if a.FieldManager == "" {
+		return DefaultFieldManager
+	}
+	return a.FieldManager
+}
+
+// withLastApplied returns a copy of the object recording itself in the last
+// applied annotation
+func withLastApplied(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	modified := obj.DeepCopy()
+	annotations := modified.GetAnnotations()
+	delete(annotations, LastAppliedAnnotation)
+	modified.SetAnnotations(annotations)
+
+	data, err := json.Marshal(modified.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", obj.GetName(), err)
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[LastAppliedAnnotation] = string(data)
+	modified.SetAnnotations(annotations)
+	return modified, nil
+}
+
+// threeWayPatch computes the patch turning the live object into the modified
+// one while removing the fields dropped since the last apply. Built-in kinds
+// get a strategic merge patch, other kinds a JSON merge patch.
+func threeWayPatch(gvk schema.GroupVersionKind, current, modified *unstructured.Unstructured) (types.PatchType, []byte, error) {
+	original := []byte(current.GetAnnotations()[LastAppliedAnnotation])
+	currentJSON, err := json.Marshal(current.Object)
+	if err != nil {
+		return "", nil, err
+	}
+	modifiedJSON, err := json.Marshal(modified.Object)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if typed, err := scheme.Scheme.New(gvk); err == nil {
+		lookup, err := strategicpatch.NewPatchMetaFromStruct(typed)
+		if err != nil {
+			return "", nil, err
+		}
+		patch, err := strategicpatch.CreateThreeWayMergePatch(original, modifiedJSON, currentJSON, lookup, true)
+		return types.StrategicMergePatchType, patch, err
+	}
+
+	patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(original, modifiedJSON, currentJSON)
+	return types.MergePatchType, patch, err
+}
+
+// Prune deletes the objects matching the selector that were applied before but
+// are missing from the applied results. The resource types of the applied
+// objects and the given kinds are searched, so a type dropped from the
+// manifests is pruned as long as it is one of the kinds. Namespaced types are
+// searched in the namespaces of the applied objects, or in the default
+// namespace. Kinds the server does not serve are skipped. Only objects
+// carrying the last applied annotation or owned by the field manager through
+// server-side apply are deleted.
+func (a *Applier) Prune(ctx context.Context, applied []*Result, selector string, kinds []schema.GroupVersionKind) ([]*Result, error) {
+	type scope struct {
+		resource  schema.GroupVersionResource
+		namespace string
+	}
+	keep := map[string]bool{}
+	var scopes []scope
+	seen := map[scope]*meta.RESTMapping{}
+	addScope := func(mapping *meta.RESTMapping, namespace string) {
+		s := scope{resource: mapping.Resource, namespace: namespace}
+		if _, exists := seen[s]; !exists {
+			seen[s] = mapping
+			scopes = append(scopes, s)
+		}
+	}
+
+	var namespaces []string
+	seenNamespaces := map[string]bool{}
+	for _, result := range applied {
+		namespace := result.Object.GetNamespace()
+		keep[result.Mapping.Resource.String()+"/"+objectKey(result.Object)] = true
+		addScope(result.Mapping, namespace)
+		if namespace != "" && !seenNamespaces[namespace] {
+			seenNamespaces[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	if len(namespaces) == 0 {
+		namespaces = []string{a.Namespace}
+	}
+
+	for _, kind := range kinds {
+		mapping, err := a.Mapper.RESTMapping(kind.GroupKind(), kind.Version)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the resource of %s: %w", kind, err)
+		}
+		if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+			addScope(mapping, "")
+			continue
+		}
+		for _, namespace := range namespaces {
+			addScope(mapping, namespace)
+		}
+	}
+
+	var pruned []*Result
+	for _, s := range scopes {
+		mapping := seen[s]
+		resourceClient := ClientFor(a.Client, mapping, s.namespace)
+		list, err := resourceClient.List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return pruned, fmt.Errorf("failed to list %s: %w", mapping.Resource.Resource, err)
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if keep[s.resource.String()+"/"+objectKey(obj)] || !a.appliedBefore(obj) {
+				continue
+			}
+			if a.DryRun != DryRunClient {
+				policy := metav1.DeletePropagationBackground
+				err := ClientFor(a.Client, mapping, obj.GetNamespace()).Delete(ctx, obj.GetName(), metav1.DeleteOptions{
+					PropagationPolicy: &policy,
+					DryRun:            a.DryRun.serverOptions(),
+				})
+				if err != nil && !apierrors.IsNotFound(err) {
+					return pruned, fmt.Errorf("failed to prune %s/%s: %w", QualifiedKind(mapping), obj.GetName(), err)
+				}
+			}
+			pruned = append(pruned, &Result{Object: obj, Mapping: mapping, Operation: "pruned"})
+		}
+	}
+	return pruned, nil
+}
+
+// appliedBefore reports whether the object was created by apply
+func (a *Applier) appliedBefore(obj *unstructured.Unstructured) bool {
+	if _, exists := obj.GetAnnotations()[LastAppliedAnnotation]; exists {
+		return true
+	}
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Manager == a.fieldManager() && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,330 @@
+package resource
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/withlin/oc-demo/pkg/testutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+)
+
+// newTestApplier returns an applier for the fake server in the default namespace
+func newTestApplier(t *testing.T, server *testutil.FakeAPIServer) *Applier {
+	t.Helper()
+
+	config, err := clientcmd.NewDefaultClientConfig(*server.Kubeconfig("default"), nil).ClientConfig()
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	config.QPS, config.Burst = 100, 100
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to create discovery client: %v", err)
+	}
+
+	return &Applier{
+		Client:       client,
+		Mapper:       restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+		Namespace:    "default",
+		ServerSide:   true,
+		FieldManager: DefaultFieldManager,
+	}
+}
+
+// newObject decodes a YAML manifest
+func newObject(t *testing.T, manifest string) *unstructured.Unstructured {
+	t.Helper()
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil {
+		t.Fatalf("Failed to decode manifest: %v", err)
+	}
+	return obj
+}
+
+// configMap returns a config map manifest with the data and the app label
+func configMap(t *testing.T, name string, data map[string]interface{}) *unstructured.Unstructured {
+	obj := newObject(t, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: "+name+"\n  labels:\n    app: web\n")
+	if data != nil {
+		if err := unstructured.SetNestedField(obj.Object, data, "data"); err != nil {
+			t.Fatalf("Failed to set data: %v", err)
+		}
+	}
+	return obj
+}
+
+// liveData returns the data of a config map stored by the fake server
+func liveData(server *testutil.FakeAPIServer, name string) map[string]interface{} {
+	obj := server.Object("v1", "ConfigMap", "default", name)
+	if obj == nil {
+		return nil
+	}
+	data, _, _ := unstructured.NestedMap(obj.Object, "data")
+	return data
+}
+
+func TestApplierServerSide(t *testing.T) {
+	server := testutil.NewFakeAPIServer()
+	defer server.Close()
+	applier := newTestApplier(t, server)
+	ctx := context.Background()
+
+	result, err := applier.Apply(ctx, configMap(t, "web", map[string]interface{}{"a": "1"}))
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if result.String() != "configmap/web serverside-applied" {
+		t.Errorf("Apply() = %q, want configmap/web serverside-applied", result)
+	}
+	if !reflect.DeepEqual(liveData(server, "web"), map[string]interface{}{"a": "1"}) {
+		t.Errorf("live data = %v, want a=1", liveData(server, "web"))
+	}
+	if !contains(server.Requests(), "PATCH /api/v1/namespaces/default/configmaps/web?fieldManager=skectl&force=false") {
+		t.Errorf("requests = %v, want an apply patch", server.Requests())
+	}
+
+	// Forcing conflicts is passed to the server
+	applier.ForceConflicts = true
+	if _, err := applier.Apply(ctx, configMap(t, "web", map[string]interface{}{"a": "2"})); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if !contains(server.Requests(), "PATCH /api/v1/namespaces/default/configmaps/web?fieldManager=skectl&force=true") {
+		t.Errorf("requests = %v, want a forced apply patch", server.Requests())
+	}
+
+	// A server dry run does not persist the change
+	applier.DryRun = DryRunServer
+	if _, err := applier.Apply(ctx, configMap(t, "other", nil)); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if server.Object("v1", "ConfigMap", "default", "other") != nil {
+		t.Error("server dry run created the object")
+	}
+	if !contains(server.Requests(), "PATCH /api/v1/namespaces/default/configmaps/other?dryRun=All&fieldManager=skectl&force=true") {
+		t.Errorf("requests = %v, want a dry-run apply patch", server.Requests())
+	}
+
+	// A client dry run sends nothing
+	applier.DryRun = DryRunClient
+	requests := len(server.Requests())
+	if _, err := applier.Apply(ctx, configMap(t, "other", nil)); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(server.Requests()) != requests {
+		t.Errorf("client dry run sent requests: %v", server.Requests()[requests:])
+	}
+}
+
+func TestApplierClientSideFallback(t *testing.T) {
+	server := testutil.NewFakeAPIServer()
+	defer server.Close()
+	server.DisableServerSideApply()
+	applier := newTestApplier(t, server)
+	ctx := context.Background()
+
+	result, err := applier.Apply(ctx, configMap(t, "web", map[string]interface{}{"a": "1", "b": "2"}))
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if result.Operation != "created" || applier.ServerSide {
+		t.Fatalf("Apply() = %q with server side %v, want created on the client", result, applier.ServerSide)
+	}
+	live := server.Object("v1", "ConfigMap", "default", "web")
+	if !strings.Contains(live.GetAnnotations()[LastAppliedAnnotation], `"b":"2"`) {
+		t.Errorf("last applied annotation = %q, want the manifest", live.GetAnnotations()[LastAppliedAnnotation])
+	}
+
+	// Applying the same manifest again changes nothing
+	result, err = applier.Apply(ctx, configMap(t, "web", map[string]interface{}{"a": "1", "b": "2"}))
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if result.Operation != "unchanged" {
+		t.Errorf("Apply() = %q, want unchanged", result)
+	}
+
+	// Fields added by others are kept, fields dropped from the manifest are removed
+	live = server.Object("v1", "ConfigMap", "default", "web")
+	unstructured.SetNestedField(live.Object, "3", "data", "c")
+	server.AddObject(live)
+	result, err = applier.Apply(ctx, configMap(t, "web", map[string]interface{}{"a": "10"}))
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if result.Operation != "configured" {
+		t.Errorf("Apply() = %q, want configured", result)
+	}
+	if want := map[string]interface{}{"a": "10", "c": "3"}; !reflect.DeepEqual(liveData(server, "web"), want) {
+		t.Errorf("live data = %v, want %v", liveData(server, "web"), want)
+	}
+	if !contains(server.Requests(), "PATCH /api/v1/namespaces/default/configmaps/web?fieldManager=skectl") {
+		t.Errorf("requests = %v, want a merge patch", server.Requests())
+	}
+}
+
+func TestApplierNamespace(t *testing.T) {
+	server := testutil.NewFakeAPIServer()
+	defer server.Close()
+	applier := newTestApplier(t, server)
+	ctx := context.Background()
+
+	// Cluster-scoped objects lose their namespace
+	namespace := newObject(t, "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: shop\n  namespace: default\n")
+	if _, err := applier.Apply(ctx, namespace); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if server.Object("v1", "Namespace", "", "shop") == nil {
+		t.Error("namespace was not created")
+	}
+
+	// Objects from other namespaces are rejected when the namespace is enforced
+	obj := configMap(t, "web", nil)
+	obj.SetNamespace("shop")
+	applier.EnforceNamespace = true
+	if _, err := applier.Apply(ctx, obj); err == nil || !strings.Contains(err.Error(), "does not match the namespace") {
+		t.Errorf("Apply() error = %v, want a namespace mismatch", err)
+	}
+
+	// Unknown kinds are rejected
+	widget := newObject(t, "apiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: gadget\n")
+	if _, err := applier.Apply(ctx, widget); err == nil {
+		t.Error("Apply() expected an error for an unknown kind")
+	}
+}
+
+func TestApplierPrune(t *testing.T) {
+	for _, serverSide := range []bool{true, false} {
+		server := testutil.NewFakeAPIServer()
+		if !serverSide {
+			server.DisableServerSideApply()
+		}
+		applier := newTestApplier(t, server)
+		ctx := context.Background()
+
+		for _, name := range []string{"a", "b"} {
+			if _, err := applier.Apply(ctx, configMap(t, name, nil)); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+		}
+		// Objects never applied are not pruned
+		manual := configMap(t, "manual", nil)
+		manual.SetNamespace("default")
+		server.AddObject(manual)
+
+		result, err := applier.Apply(ctx, configMap(t, "a", nil))
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		pruned, err := applier.Prune(ctx, []*Result{result}, "app=web", nil)
+		if err != nil {
+			t.Fatalf("Prune() error = %v", err)
+		}
+
+		if len(pruned) != 1 || pruned[0].String() != "configmap/b pruned" {
+			t.Errorf("server side %v: Prune() = %v, want configmap/b pruned", serverSide, pruned)
+		}
+		if server.Object("v1", "ConfigMap", "default", "b") != nil {
+			t.Errorf("server side %v: configmap/b was not deleted", serverSide)
+		}
+		for _, name := range []string{"a", "manual"} {
+			if server.Object("v1", "ConfigMap", "default", name) == nil {
+				t.Errorf("server side %v: configmap/%s was deleted", serverSide, name)
+			}
+		}
+		server.Close()
+	}
+}
+
+func TestApplierPruneDroppedKind(t *testing.T) {
+	server := testutil.NewFakeAPIServer()
+	defer server.Close()
+	applier := newTestApplier(t, server)
+	ctx := context.Background()
+
+	secret := newObject(t, "apiVersion: v1\nkind: Secret\nmetadata:\n  name: credentials\n  labels:\n    app: web\n")
+	for _, obj := range []*unstructured.Unstructured{configMap(t, "a", nil), secret} {
+		if _, err := applier.Apply(ctx, obj); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+	}
+
+	// The manifests no longer hold any secret
+	result, err := applier.Apply(ctx, configMap(t, "a", nil))
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	// Only the kinds of the manifests are searched without prune kinds
+	pruned, err := applier.Prune(ctx, []*Result{result}, "app=web", nil)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if len(pruned) != 0 || server.Object("v1", "Secret", "default", "credentials") == nil {
+		t.Errorf("Prune() = %v, want nothing pruned", pruned)
+	}
+
+	// The default kinds include secrets, kinds the server lacks are skipped
+	pruned, err = applier.Prune(ctx, []*Result{result}, "app=web", DefaultPruneKinds)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if len(pruned) != 1 || pruned[0].String() != "secret/credentials pruned" {
+		t.Errorf("Prune() = %v, want secret/credentials pruned", pruned)
+	}
+	if server.Object("v1", "Secret", "default", "credentials") != nil {
+		t.Error("secret/credentials was not deleted")
+	}
+}
+
+func TestParsePruneAllowlist(t *testing.T) {
+	kinds, err := ParsePruneAllowlist([]string{"core/v1/ConfigMap", "apps/v1/Deployment"})
+	if err != nil {
+		t.Fatalf("ParsePruneAllowlist() error = %v", err)
+	}
+	want := []schema.GroupVersionKind{
+		{Version: "v1", Kind: "ConfigMap"},
+		{Group: "apps", Version: "v1", Kind: "Deployment"},
+	}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("ParsePruneAllowlist() = %v, want %v", kinds, want)
+	}
+
+	for _, value := range []string{"ConfigMap", "v1/ConfigMap", "core//ConfigMap", "apps/v1/"} {
+		if _, err := ParsePruneAllowlist([]string{value}); err == nil {
+			t.Errorf("ParsePruneAllowlist(%q) expected an error", value)
+		}
+	}
+}
+
+func TestParseDryRun(t *testing.T) {
+	for value, expected := range map[string]DryRun{"": DryRunNone, "none": DryRunNone, "client": DryRunClient, "server": DryRunServer} {
+		if got, err := ParseDryRun(value); err != nil || got != expected {
+			t.Errorf("ParseDryRun(%q) = %q, %v, want %q", value, got, err, expected)
+		}
+	}
+	if _, err := ParseDryRun("always"); err == nil {
+		t.Error("ParseDryRun() expected an error")
+	}
+}
+
+// contains reports whether the list holds the value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// errDeleted stops the watch of an object once it is gone
+var errDeleted = errors.New("object deleted")
+
+// ParseCascade parses the value of the --cascade flag into a propagation
+// policy. The true and false values of older kubectl versions mean background
+// and orphan.
+func ParseCascade(value string) (metav1.DeletionPropagation, error) {
+	switch value {
+	case "background", "true":
+		return metav1.DeletePropagationBackground, nil
+	case "foreground":
+		return metav1.DeletePropagationForeground, nil
+	case "orphan", "false":
+		return metav1.DeletePropagationOrphan, nil
+	default:
+		return "", fmt.Errorf(`invalid cascade %q, must be "background", "foreground", or "orphan"`, value)
+	}
+}
+
+// Deleter deletes objects and waits for them to be gone
+type Deleter struct {
+	// Client sends the requests
+	Client dynamic.Interface
+	// Cascade is the propagation policy for the dependents of the objects
+	Cascade metav1.DeletionPropagation
+	// GracePeriod is the grace period in seconds, nil to use the default of
+	// the objects
+	GracePeriod *int64
+}
+
+// Delete deletes the object
+func (d *Deleter) Delete(ctx context.Context, mapping *meta.RESTMapping, obj *unstructured.Unstructured) error {
+	options := metav1.DeleteOptions{}
+	if d.Cascade != "" {
+		options.PropagationPolicy = &d.Cascade
+	}
+	options.GracePeriodSeconds = d.GracePeriod
+
+	err := ClientFor(d.Client, mapping, obj.GetNamespace()).Delete(ctx, obj.GetName(), options)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s %q: %w", QualifiedKind(mapping), obj.GetName(), err)
+	}
+	return nil
+}
+
+// Wait blocks until the object is gone, either removed or replaced by another
+// object with the same name, or until the context is done
+func (d *Deleter) Wait(ctx context.Context, mapping *meta.RESTMapping, obj *unstructured.Unstructured) error {
+	watcher := &Watcher{
+		Client:  ClientFor(d.Client, mapping, obj.GetNamespace()),
+		Options: metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", obj.GetName()).String()},
+	}
+	gone := func(current *unstructured.Unstructured) bool {
+		return current.GetName() != obj.GetName() || current.GetNamespace() != obj.GetNamespace() ||
+			(obj.GetUID() != "" && current.GetUID() != obj.GetUID())
+	}
+
+	events, resourceVersion, err := watcher.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get %s %q: %w", QualifiedKind(mapping), obj.GetName(), err)
+	}
+	found := false
+	for _, event := range events {
+		found = found || !gone(event.Object)
+	}
+	if !found {
+		return nil
+	}
+
+	err = watcher.Run(ctx, resourceVersion, func(event Event) error {
+		if event.Type == watch.Deleted && !gone(event.Object) {
+			return errDeleted
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errDeleted):
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to watch %s %q: %w", QualifiedKind(mapping), obj.GetName(), err)
+	case ctx.Err() != nil:
+		return fmt.Errorf("timed out waiting for the deletion of %s %q: %w", QualifiedKind(mapping), obj.GetName(), ctx.Err())
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package resource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/withlin/oc-demo/pkg/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseCascade(t *testing.T) {
+	tests := map[string]metav1.DeletionPropagation{
+		"background": metav1.DeletePropagationBackground,
+		"true":       metav1.DeletePropagationBackground,
+		"foreground": metav1.DeletePropagationForeground,
+		"orphan":     metav1.DeletePropagationOrphan,
+		"false":      metav1.DeletePropagationOrphan,
+	}
+	for value, expected := range tests {
+		if got, err := ParseCascade(value); err != nil || got != expected {
+			t.Errorf("ParseCascade(%q) = %q, %v, want %q", value, got, err, expected)
+		}
+	}
+	if _, err := ParseCascade("cascade"); err == nil {
+		t.Error("ParseCascade() expected an error")
+	}
+}
+
+func TestDeleter(t *testing.T) {
+	server := testutil.NewFakeAPIServer()
+	defer server.Close()
+	applier := newTestApplier(t, server)
+	gracePeriod := int64(5)
+	deleter := &Deleter{Client: applier.Client, Cascade: metav1.DeletePropagationForeground, GracePeriod: &gracePeriod}
+	ctx := context.Background()
+
+	obj := configMap(t, "web", nil)
+	obj.SetFinalizers([]string{"example.com/cleanup"})
+	mapping, err := applier.Prepare(obj)
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	server.AddObject(obj)
+
+	if err := deleter.Delete(ctx, mapping, obj); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	deletions := server.Deletions()
+	if len(deletions) != 1 || *deletions[0].PropagationPolicy != metav1.DeletePropagationForeground || *deletions[0].GracePeriodSeconds != 5 {
+		t.Errorf("delete options = %+v, want foreground with a grace period of 5s", deletions)
+	}
+
+	// The finalizer keeps the object until it is removed
+	shortCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if err := deleter.Wait(shortCtx, mapping, obj); err == nil {
+		t.Error("Wait() returned before the object was gone")
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		server.DeleteObject("v1", "ConfigMap", "default", "web")
+	}()
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := deleter.Wait(waitCtx, mapping, obj); err != nil {
+		t.Errorf("Wait() error = %v", err)
+	}
+
+	// Missing objects are gone already
+	if err := deleter.Wait(waitCtx, mapping, obj); err != nil {
+		t.Errorf("Wait() error = %v for a missing object", err)
+	}
+	if err := deleter.Delete(ctx, mapping, obj); err == nil {
+		t.Error("Delete() expected an error for a missing object")
+	}
+}
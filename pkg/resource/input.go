@@ -0,0 +1,164 @@
+package resource
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// manifestExtensions are the file extensions read when walking directories
+var manifestExtensions = []string{".yaml", ".yml", ".json"}
+
+// urlClient downloads the manifests of URLs, giving up on stalled servers
+var urlClient = &http.Client{Timeout: 30 * time.Second}
+
+// ReadObjects reads the objects of every file, directory or URL in order. A
+// filename of "-" reads the standard input. Directories are read one level
+// deep unless recursive is set. Documents are YAML or JSON, several per file,
+// and List objects are expanded into their items. Downloads of URLs are
+// cancelled with the context.
+func ReadObjects(ctx context.Context, filenames []string, recursive bool, stdin io.Reader) ([]*unstructured.Unstructured, error) {
+	if len(filenames) == 0 {
+		return nil, fmt.Errorf("must specify at least one file with -f")
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, filename := range filenames {
+		var read []*unstructured.Unstructured
+		var err error
+		switch {
+		case filename == "-":
+			read, err = decodeObjects(stdin, "STDIN")
+		case strings.HasPrefix(filename, "http://") || strings.HasPrefix(filename, "https://"):
+			read, err = readURL(ctx, filename)
+		default:
+			read, err = readPath(filename, recursive)
+		}
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, read...)
+	}
+	return objects, nil
+}
+
+// readURL downloads and decodes the objects of a URL
+func readURL(ctx context.Context, url string) ([]*unstructured.Unstructured, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	resp, err := urlClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read %s: %s", url, resp.Status)
+	}
+	return decodeObjects(resp.Body, url)
+}
+
+// readPath decodes the objects of a file, or of the manifests in a directory
+func readPath(path string, recursive bool) ([]*unstructured.Unstructured, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("the path %q does not exist", path)
+	}
+	if !info.IsDir() {
+		return readFile(path)
+	}
+
+	var objects []*unstructured.Unstructured
+	err = filepath.WalkDir(path, func(file string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if file != path && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isManifest(file) {
+			return nil
+		}
+		read, err := readFile(file)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, read...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// readFile decodes the objects of a single file
+func readFile(path string) ([]*unstructured.Unstructured, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+	return decodeObjects(f, path)
+}
+
+// isManifest reports whether the file has a YAML or JSON extension
+func isManifest(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, manifestExt := range manifestExtensions {
+		if ext == manifestExt {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeObjects decodes a stream of YAML documents or JSON objects, skipping
+// empty documents and expanding lists
+func decodeObjects(r io.Reader, source string) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bufio.NewReader(r), 4096)
+	for {
+		var raw runtime.RawExtension
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode %s: %w", source, err)
+		}
+		data := bytes.TrimSpace(raw.Raw)
+		if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+			continue
+		}
+
+		obj, _, err := unstructured.UnstructuredJSONScheme.Decode(data, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", source, err)
+		}
+		switch obj := obj.(type) {
+		case *unstructured.UnstructuredList:
+			for i := range obj.Items {
+				objects = append(objects, &obj.Items[i])
+			}
+		case *unstructured.Unstructured:
+			objects = append(objects, obj)
+		}
+	}
+	return objects, nil
+}
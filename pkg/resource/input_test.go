@@ -0,0 +1,177 @@
+package resource
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// writeFiles writes the files below the directory, creating subdirectories
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+// objectNames returns the objects as kind/name strings
+func objectNames(objects []*unstructured.Unstructured) []string {
+	var names []string
+	for _, obj := range objects {
+		names = append(names, obj.GetKind()+"/"+obj.GetName())
+	}
+	return names
+}
+
+func TestReadObjects(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"app/multi.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+data:
+  replicas: "1"
+---
+# empty documents are skipped
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: b
+`,
+		"app/pod.json": `{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "c"}}
+{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "d"}}`,
+		"app/list.yml": `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: e
+`,
+		"app/README.md":       "not a manifest",
+		"app/nested/job.yaml": "apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: f\n",
+		"invalid.yaml":        "apiVersion: v1\nmetadata:\n  name: g\n",
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pod.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: remote\n"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		filenames []string
+		recursive bool
+		stdin     string
+		expected  []string
+		wantErr   bool
+	}{
+		{
+			name:      "multi-document file",
+			filenames: []string{filepath.Join(dir, "app", "multi.yaml")},
+			expected:  []string{"ConfigMap/a", "Service/b"},
+		},
+		{
+			name:      "directory",
+			filenames: []string{filepath.Join(dir, "app")},
+			expected:  []string{"Secret/e", "ConfigMap/a", "Service/b", "Pod/c", "Pod/d"},
+		},
+		{
+			name:      "recursive directory",
+			filenames: []string{filepath.Join(dir, "app")},
+			recursive: true,
+			expected:  []string{"Secret/e", "ConfigMap/a", "Service/b", "Job/f", "Pod/c", "Pod/d"},
+		},
+		{
+			name:      "standard input",
+			filenames: []string{"-"},
+			stdin:     "apiVersion: v1\nkind: Pod\nmetadata:\n  name: stdin\n",
+			expected:  []string{"Pod/stdin"},
+		},
+		{
+			name:      "url",
+			filenames: []string{server.URL + "/pod.yaml"},
+			expected:  []string{"Pod/remote"},
+		},
+		{
+			name:      "url not found",
+			filenames: []string{server.URL + "/missing.yaml"},
+			wantErr:   true,
+		},
+		{
+			name:      "missing kind",
+			filenames: []string{filepath.Join(dir, "invalid.yaml")},
+			wantErr:   true,
+		},
+		{
+			name:      "missing file",
+			filenames: []string{filepath.Join(dir, "missing.yaml")},
+			wantErr:   true,
+		},
+		{
+			name:    "no files",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects, err := ReadObjects(context.Background(), tt.filenames, tt.recursive, strings.NewReader(tt.stdin))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadObjects() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := objectNames(objects); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ReadObjects() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadObjectsStalledURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if _, err := ReadObjects(ctx, []string{server.URL + "/pod.yaml"}, false, nil); err == nil {
+			t.Fatal("ReadObjects() expected an error for a stalled URL")
+		}
+	})
+
+	t.Run("client timeout", func(t *testing.T) {
+		oldClient := urlClient
+		defer func() { urlClient = oldClient }()
+		urlClient = &http.Client{Timeout: 50 * time.Millisecond}
+		if _, err := ReadObjects(context.Background(), []string{server.URL + "/pod.yaml"}, false, nil); err == nil {
+			t.Fatal("ReadObjects() expected an error for a stalled URL")
+		}
+	})
+}
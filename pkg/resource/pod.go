@@ -0,0 +1,40 @@
+package resource
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultContainerAnnotation names the container used when none is requested
+const DefaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// DefaultContainer returns the container used when none is named: the one
+// named by the default container annotation, or else the first container. It
+// reports whether the container was picked among several without annotation.
+func DefaultContainer(pod *corev1.Pod) (string, bool, error) {
+	if len(pod.Spec.Containers) == 0 {
+		return "", false, fmt.Errorf("pod %s has no containers", pod.Name)
+	}
+	if annotated := pod.Annotations[DefaultContainerAnnotation]; annotated != "" {
+		for _, name := range ContainerNames(pod) {
+			if name == annotated {
+				return annotated, false, nil
+			}
+		}
+	}
+	return pod.Spec.Containers[0].Name, len(pod.Spec.Containers) > 1, nil
+}
+
+// ContainerNames returns the names of the init containers and containers of
+// the pod
+func ContainerNames(pod *corev1.Pod) []string {
+	var names []string
+	for _, container := range pod.Spec.InitContainers {
+		names = append(names, container.Name)
+	}
+	for _, container := range pod.Spec.Containers {
+		names = append(names, container.Name)
+	}
+	return names
+}
@@ -0,0 +1,115 @@
+package resource
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// Ref is a resource type with an optional object name given on the command line
+type Ref struct {
+	// Type is the resource type, such as pods, pod, po or deployments.apps
+	Type string
+	// Name is the object name, empty to select every object
+	Name string
+}
+
+// String returns the reference in <type>[/<name>] form
+func (r Ref) String() string {
+	if r.Name == "" {
+		return r.Type
+	}
+	return r.Type + "/" + r.Name
+}
+
+// ParseRefs parses command line arguments of the form <type>[/<name>]... or
+// <type>[,<type>...] [<name>...]
+func ParseRefs(args []string) ([]Ref, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("you must specify the type of resource to get")
+	}
+
+	// Every argument is a <type>/<name> pair
+	if strings.Contains(args[0], "/") {
+		refs := make([]Ref, 0, len(args))
+		for _, arg := range args {
+			parts := strings.SplitN(arg, "/", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return nil, fmt.Errorf("arguments in resource/name form must have a single resource and name, got %q", arg)
+			}
+			refs = append(refs, Ref{Type: parts[0], Name: parts[1]})
+		}
+		return refs, nil
+	}
+
+	// The first argument lists the types and the rest are names
+	var refs []Ref
+	for _, resourceType := range strings.Split(args[0], ",") {
+		if resourceType == "" {
+			return nil, fmt.Errorf("invalid resource type %q", args[0])
+		}
+		if len(args) == 1 {
+			refs = append(refs, Ref{Type: resourceType})
+			continue
+		}
+		for _, name := range args[1:] {
+			if strings.Contains(name, "/") {
+				return nil, fmt.Errorf("there is no need to specify a resource type as a separate argument when passing arguments in resource/name form")
+			}
+			refs = append(refs, Ref{Type: resourceType, Name: name})
+		}
+	}
+	return refs, nil
+}
+
+// Mapping resolves a resource type through the REST mapper. The type may be a
+// short name, a plural or singular name or kind, optionally qualified with the
+// version and group as in deployments.v1.apps.
+func Mapping(mapper meta.RESTMapper, resourceType string) (*meta.RESTMapping, error) {
+	resourceType = strings.ToLower(resourceType)
+
+	var gvr schema.GroupVersionResource
+	var err error
+	fullySpecified, groupResource := schema.ParseResourceArg(resourceType)
+	if fullySpecified != nil {
+		gvr, err = mapper.ResourceFor(*fullySpecified)
+	}
+	if fullySpecified == nil || err != nil {
+		gvr, err = mapper.ResourceFor(groupResource.WithVersion(""))
+	}
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("the server doesn't have a resource type %q", resourceType)
+		}
+		return nil, fmt.Errorf("failed to resolve resource type %q: %w", resourceType, err)
+	}
+
+	gvk, err := mapper.KindFor(gvr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve kind of %q: %w", resourceType, err)
+	}
+
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve resource type %q: %w", resourceType, err)
+	}
+	return mapping, nil
+}
+
+// Namespaced reports whether the objects of the mapping live in a namespace
+func Namespaced(mapping *meta.RESTMapping) bool {
+	return mapping.Scope.Name() == meta.RESTScopeNameNamespace
+}
+
+// ClientFor returns the dynamic client of the mapping, scoped to the namespace
+// for namespaced resources. An empty namespace selects all namespaces.
+func ClientFor(client dynamic.Interface, mapping *meta.RESTMapping, namespace string) dynamic.ResourceInterface {
+	resourceClient := client.Resource(mapping.Resource)
+	if Namespaced(mapping) && namespace != "" {
+		return resourceClient.Namespace(namespace)
+	}
+	return resourceClient
+}
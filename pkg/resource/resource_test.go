@@ -0,0 +1,64 @@
+package resource
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRefs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []Ref
+		wantErr  bool
+	}{
+		{
+			name:     "type only",
+			args:     []string{"pods"},
+			expected: []Ref{{Type: "pods"}},
+		},
+		{
+			name:     "type and names",
+			args:     []string{"pod", "a", "b"},
+			expected: []Ref{{Type: "pod", Name: "a"}, {Type: "pod", Name: "b"}},
+		},
+		{
+			name:     "several types",
+			args:     []string{"po,svc", "web"},
+			expected: []Ref{{Type: "po", Name: "web"}, {Type: "svc", Name: "web"}},
+		},
+		{
+			name:     "type/name pairs",
+			args:     []string{"pod/a", "deploy/b"},
+			expected: []Ref{{Type: "pod", Name: "a"}, {Type: "deploy", Name: "b"}},
+		},
+		{
+			name:    "missing name",
+			args:    []string{"pod/"},
+			wantErr: true,
+		},
+		{
+			name:    "mixed forms",
+			args:    []string{"pod", "deploy/b"},
+			wantErr: true,
+		},
+		{
+			name:    "no arguments",
+			args:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs, err := ParseRefs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRefs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(refs, tt.expected) {
+				t.Errorf("ParseRefs() = %v, want %v", refs, tt.expected)
+			}
+		})
+	}
+}
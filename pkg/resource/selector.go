@@ -0,0 +1,44 @@
+package resource
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// PodSelector returns the selector of the pods of a workload or service, read
+// from spec.selector. Label selectors with matchLabels or matchExpressions,
+// as used by deployments, are accepted as well as the plain label maps of
+// services, replication controllers and deployment configs.
+func PodSelector(obj *unstructured.Unstructured) (labels.Selector, error) {
+	value, found, err := unstructured.NestedMap(obj.Object, "spec", "selector")
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector in %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	if !found || len(value) == 0 {
+		return nil, fmt.Errorf("%s %s has no pod selector", obj.GetKind(), obj.GetName())
+	}
+
+	_, hasLabels := value["matchLabels"]
+	_, hasExpressions := value["matchExpressions"]
+	if hasLabels || hasExpressions {
+		selector := &metav1.LabelSelector{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(value, selector); err != nil {
+			return nil, fmt.Errorf("invalid selector in %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		return metav1.LabelSelectorAsSelector(selector)
+	}
+
+	set := labels.Set{}
+	for key, value := range value {
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid selector in %s %s: label %s is not a string", obj.GetKind(), obj.GetName(), key)
+		}
+		set[key] = text
+	}
+	return labels.SelectorFromSet(set), nil
+}
@@ -0,0 +1,252 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// DefaultRetryDelay is the delay before reopening a watch closed by the server
+const DefaultRetryDelay = time.Second
+
+// Event is a change to a watched object
+type Event struct {
+	// Type is Added, Modified or Deleted
+	Type watch.EventType
+	// Object is the changed object, or its metadata when watching tables
+	Object *unstructured.Unstructured
+	// Table holds the single row of the object when watching with a table
+	// client, nil otherwise
+	Table *metav1.Table
+}
+
+// Watcher streams the changes of the objects of one resource. Watches closed by
+// the server are reopened from the last resourceVersion, and when that version
+// has expired (410 Gone) the objects are listed again and the differences are
+// reported as events.
+type Watcher struct {
+	// Client lists and watches the objects
+	Client dynamic.ResourceInterface
+	// Options holds the label and field selectors
+	Options metav1.ListOptions
+	// RetryDelay is the delay before reopening a closed watch
+	RetryDelay time.Duration
+
+	columns []metav1.TableColumnDefinition
+	known   map[string]Event
+}
+
+// List lists the objects, returning them as Added events together with the
+// resourceVersion to start watching from
+func (w *Watcher) List(ctx context.Context) ([]Event, string, error) {
+	list, err := w.Client.List(ctx, w.Options)
+	if err != nil {
+		return nil, "", err
+	}
+
+	events, err := w.toEvents(watch.Added, list.Object, list.Items)
+	if err != nil {
+		return nil, "", err
+	}
+	w.known = map[string]Event{}
+	for _, event := range events {
+		w.known[objectKey(event.Object)] = event
+	}
+	return events, list.GetResourceVersion(), nil
+}
+
+// Run watches the objects from the resourceVersion returned by List and passes
+// every change to the handler until the context is done or the handler fails
+func (w *Watcher) Run(ctx context.Context, resourceVersion string, handler func(Event) error) error {
+	if w.known == nil {
+		w.known = map[string]Event{}
+	}
+	retryDelay := w.RetryDelay
+	if retryDelay == 0 {
+		retryDelay = DefaultRetryDelay
+	}
+
+	for {
+		expired, err := w.watch(ctx, &resourceVersion, handler)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if expired {
+			if resourceVersion, err = w.relist(ctx, handler); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			continue
+		}
+
+		// The server closed the watch, reopen it after a short delay
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// watch runs a single watch, advancing the resourceVersion with every event.
+// It reports whether the resourceVersion has expired.
+func (w *Watcher) watch(ctx context.Context, resourceVersion *string, handler func(Event) error) (bool, error) {
+	options := w.Options
+	options.ResourceVersion = *resourceVersion
+	options.AllowWatchBookmarks = true
+
+	watcher, err := w.Client.Watch(ctx, options)
+	if err != nil {
+		if isExpired(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to watch: %w", err)
+	}
+	defer watcher.Stop()
+
+	for {
+		var event watch.Event
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case event, ok = <-watcher.ResultChan():
+		}
+		if !ok {
+			return false, nil
+		}
+
+		switch event.Type {
+		case watch.Error:
+			err := apierrors.FromObject(event.Object)
+			if isExpired(err) {
+				return true, nil
+			}
+			return false, fmt.Errorf("watch failed: %w", err)
+		case watch.Bookmark:
+			if accessor, err := meta.Accessor(event.Object); err == nil {
+				*resourceVersion = accessor.GetResourceVersion()
+			}
+			continue
+		}
+
+		obj, ok := event.Object.(*unstructured.Unstructured)
+		if !ok {
+			return false, fmt.Errorf("unexpected watch object %T", event.Object)
+		}
+		events, err := w.toEvents(event.Type, obj.Object, []unstructured.Unstructured{*obj})
+		if err != nil {
+			return false, err
+		}
+		for _, e := range events {
+			*resourceVersion = e.Object.GetResourceVersion()
+			if e.Type == watch.Deleted {
+				delete(w.known, objectKey(e.Object))
+			} else {
+				w.known[objectKey(e.Object)] = e
+			}
+			if err := handler(e); err != nil {
+				return false, err
+			}
+		}
+	}
+}
+
+// relist lists the objects again and reports the changes since the last known
+// state as events, returning the new resourceVersion
+func (w *Watcher) relist(ctx context.Context, handler func(Event) error) (string, error) {
+	previous := w.known
+	events, resourceVersion, err := w.List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list after the watch expired: %w", err)
+	}
+
+	for _, event := range events {
+		key := objectKey(event.Object)
+		old, exists := previous[key]
+		delete(previous, key)
+		switch {
+		case !exists:
+			event.Type = watch.Added
+		case old.Object.GetResourceVersion() != event.Object.GetResourceVersion():
+			event.Type = watch.Modified
+		default:
+			continue
+		}
+		if err := handler(event); err != nil {
+			return "", err
+		}
+	}
+
+	// Whatever was not listed again was deleted meanwhile
+	for _, old := range previous {
+		old.Type = watch.Deleted
+		if err := handler(old); err != nil {
+			return "", err
+		}
+	}
+	return resourceVersion, nil
+}
+
+// toEvents splits a response into one event per object. Tables are split into
+// one event per row, keeping the column definitions of earlier responses when
+// the server omits them.
+func (w *Watcher) toEvents(eventType watch.EventType, obj map[string]interface{}, items []unstructured.Unstructured) ([]Event, error) {
+	if obj["kind"] != "Table" {
+		events := make([]Event, len(items))
+		for i := range items {
+			events[i] = Event{Type: eventType, Object: &items[i]}
+		}
+		return events, nil
+	}
+
+	table := &metav1.Table{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, table); err != nil {
+		return nil, fmt.Errorf("failed to decode table: %w", err)
+	}
+	if len(table.ColumnDefinitions) > 0 {
+		w.columns = table.ColumnDefinitions
+	}
+
+	events := make([]Event, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		rowObject := &unstructured.Unstructured{}
+		if err := json.Unmarshal(row.Object.Raw, &rowObject.Object); err != nil {
+			return nil, fmt.Errorf("failed to decode table row: %w", err)
+		}
+		events = append(events, Event{
+			Type:   eventType,
+			Object: rowObject,
+			Table: &metav1.Table{
+				ColumnDefinitions: w.columns,
+				Rows:              []metav1.TableRow{row},
+			},
+		})
+	}
+	return events, nil
+}
+
+// objectKey identifies an object by namespace and name
+func objectKey(obj *unstructured.Unstructured) string {
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// isExpired reports whether the error means the resourceVersion is too old
+func isExpired(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
@@ -0,0 +1,146 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/withlin/oc-demo/pkg/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// newPod returns a pod with the app label
+func newPod(name, app string) *unstructured.Unstructured {
+	pod := &unstructured.Unstructured{}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetNamespace("default")
+	pod.SetName(name)
+	pod.SetLabels(map[string]string{"app": app})
+	return pod
+}
+
+// runWatcher runs the watcher in the background, sending the events as
+// "TYPE name" strings, and returns a function stopping it
+func runWatcher(t *testing.T, watcher *Watcher, resourceVersion string, events chan<- string) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- watcher.Run(ctx, resourceVersion, func(event Event) error {
+			events <- fmt.Sprintf("%s %s", event.Type, event.Object.GetName())
+			return nil
+		})
+	}()
+
+	return func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	}
+}
+
+// expectEvents waits for the events in order
+func expectEvents(t *testing.T, events <-chan string, expected ...string) {
+	t.Helper()
+	for _, want := range expected {
+		select {
+		case got := <-events:
+			if got != want {
+				t.Fatalf("got event %q, want %q", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event %q", want)
+		}
+	}
+}
+
+// waitForWatches waits until the server received the number of watch requests
+func waitForWatches(t *testing.T, server *testutil.FakeAPIServer, count int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		watches := 0
+		for _, request := range server.Requests() {
+			if strings.Contains(request, "watch=true") {
+				watches++
+			}
+		}
+		if watches >= count {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d watch requests", count)
+}
+
+func TestWatcher(t *testing.T) {
+	server := testutil.NewFakeAPIServer()
+	defer server.Close()
+	server.AddObject(newPod("a", "web"))
+	server.AddObject(newPod("b", "web"))
+	server.AddObject(newPod("c", "batch"))
+
+	config, err := clientcmd.NewDefaultClientConfig(*server.Kubeconfig("default"), nil).ClientConfig()
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	watcher := &Watcher{
+		Client:     client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).Namespace("default"),
+		Options:    metav1.ListOptions{LabelSelector: "app=web"},
+		RetryDelay: 10 * time.Millisecond,
+	}
+
+	listed, resourceVersion, err := watcher.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(listed) != 2 || resourceVersion != "3" {
+		t.Fatalf("List() = %d events at %q, want 2 events at \"3\"", len(listed), resourceVersion)
+	}
+
+	events := make(chan string, 100)
+	stop := runWatcher(t, watcher, resourceVersion, events)
+	waitForWatches(t, server, 1)
+
+	// Changes matching the selector are streamed
+	server.AddObject(newPod("d", "web"))
+	server.AddObject(newPod("a", "web"))
+	server.AddObject(newPod("c", "batch"))
+	server.DeleteObject("v1", "Pod", "default", "b")
+	expectEvents(t, events, "ADDED d", "MODIFIED a", "DELETED b")
+
+	// A watch closed by the server resumes from the last resourceVersion
+	server.CloseWatches()
+	waitForWatches(t, server, 2)
+	server.AddObject(newPod("e", "web"))
+	expectEvents(t, events, "ADDED e")
+	stop()
+
+	// An expired resourceVersion falls back to listing, reporting the differences
+	server.AddObject(newPod("f", "web"))
+	server.AddObject(newPod("d", "web"))
+	server.DeleteObject("v1", "Pod", "default", "e")
+	server.Compact()
+
+	stop = runWatcher(t, watcher, resourceVersion, events)
+	expectEvents(t, events, "ADDED f", "MODIFIED d", "DELETED e")
+	stop()
+
+	select {
+	case event := <-events:
+		t.Errorf("unexpected event %q", event)
+	default:
+	}
+}
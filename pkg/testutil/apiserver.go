@@ -0,0 +1,803 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/yaml"
+)
+
+// FakeToken 是假 API 服务器的 kubeconfig 中使用的令牌
+const FakeToken = "fake-token"
+
+// FakeAPIServer 模拟 API 服务器的发现接口和资源接口，对象保存在内存中
+type FakeAPIServer struct {
+	*httptest.Server
+
+	mu              sync.Mutex
+	groupVersions   []string
+	resources       map[string][]metav1.APIResource
+	objects         []*unstructured.Unstructured
+	handlers        map[string]http.HandlerFunc
+	requests        []string
+	resourceVersion int
+	noApply         bool
+	deletions       []metav1.DeleteOptions
+
+	// events 是对象变更的历史，供 watch 请求使用
+	events    []watchEvent
+	compacted int
+	changed   chan struct{}
+	closed    chan struct{}
+}
+
+// watchEvent 是一次对象变更
+type watchEvent struct {
+	resourceVersion int
+	eventType       string
+	obj             *unstructured.Unstructured
+}
+
+// NewFakeAPIServer 创建带有常用核心资源和 apps 资源的假 API 服务器（TLS）
+func NewFakeAPIServer() *FakeAPIServer {
+	s := &FakeAPIServer{
+		resources: map[string][]metav1.APIResource{},
+		handlers:  map[string]http.HandlerFunc{},
+		changed:   make(chan struct{}),
+		closed:    make(chan struct{}),
+	}
+
+	s.AddResource("v1", metav1.APIResource{Name: "pods", SingularName: "pod", Kind: "Pod", Namespaced: true, ShortNames: []string{"po"}})
+	s.AddResource("v1", metav1.APIResource{Name: "services", SingularName: "service", Kind: "Service", Namespaced: true, ShortNames: []string{"svc"}})
+	s.AddResource("v1", metav1.APIResource{Name: "configmaps", SingularName: "configmap", Kind: "ConfigMap", Namespaced: true, ShortNames: []string{"cm"}})
+	s.AddResource("v1", metav1.APIResource{Name: "secrets", SingularName: "secret", Kind: "Secret", Namespaced: true})
+	s.AddResource("v1", metav1.APIResource{Name: "endpoints", SingularName: "endpoints", Kind: "Endpoints", Namespaced: true, ShortNames: []string{"ep"}})
+	s.AddResource("v1", metav1.APIResource{Name: "events", SingularName: "event", Kind: "Event", Namespaced: true, ShortNames: []string{"ev"}})
+	s.AddResource("v1", metav1.APIResource{Name: "namespaces", SingularName: "namespace", Kind: "Namespace", ShortNames: []string{"ns"}})
+	s.AddResource("v1", metav1.APIResource{Name: "nodes", SingularName: "node", Kind: "Node", ShortNames: []string{"no"}})
+	s.AddResource("apps/v1", metav1.APIResource{Name: "deployments", SingularName: "deployment", Kind: "Deployment", Namespaced: true, ShortNames: []string{"deploy"}})
+	s.AddResource("apps/v1", metav1.APIResource{Name: "replicasets", SingularName: "replicaset", Kind: "ReplicaSet", Namespaced: true, ShortNames: []string{"rs"}})
+
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// AddResource 在发现接口中注册资源类型
+func (s *FakeAPIServer) AddResource(groupVersion string, resource metav1.APIResource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.resources[groupVersion]; !exists {
+		s.groupVersions = append(s.groupVersions, groupVersion)
+	}
+	if resource.Verbs == nil {
+		resource.Verbs = metav1.Verbs{"create", "delete", "deletecollection", "get", "list", "patch", "update", "watch"}
+	}
+	s.resources[groupVersion] = append(s.resources[groupVersion], resource)
+}
+
+// AddObject 添加对象，已存在的同名对象会被替换，对象的类型必须已经注册
+func (s *FakeAPIServer) AddObject(obj *unstructured.Unstructured) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.storeLocked(obj)
+}
+
+// DeleteObject 删除指定的对象
+func (s *FakeAPIServer) DeleteObject(groupVersion, kind, namespace, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, obj := range s.objects {
+		if obj.GetAPIVersion() == groupVersion && obj.GetKind() == kind &&
+			obj.GetNamespace() == namespace && obj.GetName() == name {
+			s.deleteLocked(obj)
+			return
+		}
+	}
+}
+
+// DisableServerSideApply 使 apply 修补返回 415，模拟不支持服务端 apply 的服务器
+func (s *FakeAPIServer) DisableServerSideApply() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.noApply = true
+}
+
+// Deletions 返回删除单个对象的请求中收到的删除选项
+func (s *FakeAPIServer) Deletions() []metav1.DeleteOptions {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]metav1.DeleteOptions(nil), s.deletions...)
+}
+
+// CloseWatches 关闭所有正在进行的 watch 请求
+func (s *FakeAPIServer) CloseWatches() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	close(s.closed)
+	s.closed = make(chan struct{})
+}
+
+// Compact 丢弃变更历史，之后使用当前或更早 resourceVersion 的 watch 请求返回 410 Gone
+func (s *FakeAPIServer) Compact() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.compacted = s.resourceVersion + 1
+	s.events = nil
+}
+
+// Close 关闭所有 watch 请求后关闭服务器
+func (s *FakeAPIServer) Close() {
+	s.CloseWatches()
+	s.Server.Close()
+}
+
+// storeLocked 保存对象并记录变更，返回保存的副本，调用方必须持有锁
+func (s *FakeAPIServer) storeLocked(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+	s.resourceVersion++
+	obj.SetResourceVersion(strconv.Itoa(s.resourceVersion))
+
+	eventType := "ADDED"
+	for i, existing := range s.objects {
+		if sameObject(existing, obj) {
+			s.objects = append(s.objects[:i], s.objects[i+1:]...)
+			eventType = "MODIFIED"
+			break
+		}
+	}
+	s.objects = append(s.objects, obj)
+	s.recordLocked(eventType, obj)
+	return obj.DeepCopy()
+}
+
+// deleteLocked 删除对象并记录变更，调用方必须持有锁
+func (s *FakeAPIServer) deleteLocked(obj *unstructured.Unstructured) {
+	for i, existing := range s.objects {
+		if sameObject(existing, obj) {
+			s.objects = append(s.objects[:i], s.objects[i+1:]...)
+			break
+		}
+	}
+	obj = obj.DeepCopy()
+	s.resourceVersion++
+	obj.SetResourceVersion(strconv.Itoa(s.resourceVersion))
+	s.recordLocked("DELETED", obj)
+}
+
+// recordLocked 记录变更并通知 watch 请求，调用方必须持有锁
+func (s *FakeAPIServer) recordLocked(eventType string, obj *unstructured.Unstructured) {
+	s.events = append(s.events, watchEvent{resourceVersion: s.resourceVersion, eventType: eventType, obj: obj.DeepCopy()})
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// AddYAML 从 YAML 文档添加对象
+func (s *FakeAPIServer) AddYAML(manifest string) {
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil {
+		panic(fmt.Sprintf("invalid object manifest: %v", err))
+	}
+	s.AddObject(obj)
+}
+
+// Object 返回指定的对象，不存在时返回 nil
+func (s *FakeAPIServer) Object(groupVersion, kind, namespace, name string) *unstructured.Unstructured {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, obj := range s.objects {
+		if obj.GetAPIVersion() == groupVersion && obj.GetKind() == kind &&
+			obj.GetNamespace() == namespace && obj.GetName() == name {
+			return obj.DeepCopy()
+		}
+	}
+	return nil
+}
+
+// Handle 注册自定义处理函数，优先于内置的资源处理
+func (s *FakeAPIServer) Handle(method, path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method+" "+path] = handler
+}
+
+// Requests 返回除发现接口外收到的请求，格式为 "METHOD path?query"
+func (s *FakeAPIServer) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// Kubeconfig 返回指向假 API 服务器的 kubeconfig，当前上下文使用指定的命名空间
+func (s *FakeAPIServer) Kubeconfig(namespace string) *api.Config {
+	config := api.NewConfig()
+	config.Clusters["cluster"] = &api.Cluster{Server: s.URL, InsecureSkipTLSVerify: true}
+	config.AuthInfos["developer/cluster"] = &api.AuthInfo{Token: FakeToken}
+	config.Contexts[namespace+"/cluster/developer"] = &api.Context{
+		Cluster:   "cluster",
+		AuthInfo:  "developer/cluster",
+		Namespace: namespace,
+	}
+	config.CurrentContext = namespace + "/cluster/developer"
+	return config
+}
+
+// WriteKubeconfig 将 Kubeconfig 写入文件
+func (s *FakeAPIServer) WriteKubeconfig(path, namespace string) error {
+	return clientcmd.WriteToFile(*s.Kubeconfig(namespace), path)
+}
+
+// requestPath 是解析后的资源请求路径
+type requestPath struct {
+	groupVersion string
+	namespace    string
+	resource     string
+	name         string
+	subresource  string
+}
+
+// serveHTTP 处理发现请求、自定义请求和资源请求
+func (s *FakeAPIServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.serveDiscovery(w, r) {
+		return
+	}
+
+	s.mu.Lock()
+	request := r.Method + " " + r.URL.Path
+	if r.URL.RawQuery != "" {
+		request += "?" + r.URL.RawQuery
+	}
+	s.requests = append(s.requests, request)
+	handler, exists := s.handlers[r.Method+" "+r.URL.Path]
+	// 在记录请求时获取，使请求记录之后的 CloseWatches 一定能关闭该 watch
+	closed := s.closed
+	s.mu.Unlock()
+
+	if exists {
+		handler(w, r)
+		return
+	}
+
+	path, ok := parseRequestPath(r.URL.Path)
+	if !ok {
+		writeStatus(w, apierrors.NewNotFound(schema.GroupResource{}, r.URL.Path))
+		return
+	}
+	resource, ok := s.lookupResource(path.groupVersion, path.resource)
+	if !ok {
+		writeStatus(w, apierrors.NewNotFound(schema.GroupResource{Resource: path.resource}, path.name))
+		return
+	}
+
+	switch {
+	case path.subresource != "":
+		writeStatus(w, apierrors.NewNotFound(schema.GroupResource{Resource: path.resource + "/" + path.subresource}, path.name))
+	case r.Method == http.MethodGet && path.name == "" && r.URL.Query().Get("watch") == "true":
+		s.serveWatch(w, r, path, resource, closed)
+	case r.Method == http.MethodGet && path.name == "":
+		s.serveList(w, r, path, resource)
+	case r.Method == http.MethodGet:
+		s.serveGet(w, r, path, resource)
+	case r.Method == http.MethodPost:
+		s.serveCreate(w, r, path, resource)
+	case r.Method == http.MethodPut || r.Method == http.MethodPatch:
+		s.serveUpdate(w, r, path, resource)
+	case r.Method == http.MethodDelete && path.name == "":
+		s.serveDeleteCollection(w, r, path, resource)
+	case r.Method == http.MethodDelete:
+		s.serveDelete(w, r, path, resource)
+	default:
+		writeStatus(w, apierrors.NewMethodNotSupported(schema.GroupResource{Resource: path.resource}, r.Method))
+	}
+}
+
+// serveDiscovery 处理发现请求，返回是否已处理
+func (s *FakeAPIServer) serveDiscovery(w http.ResponseWriter, r *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/api":
+		writeJSON(w, http.StatusOK, &metav1.APIVersions{
+			TypeMeta: metav1.TypeMeta{Kind: "APIVersions"},
+			Versions: []string{"v1"},
+		})
+		return true
+	case "/apis":
+		list := &metav1.APIGroupList{TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"}}
+		for _, groupVersion := range s.groupVersions {
+			gv, _ := schema.ParseGroupVersion(groupVersion)
+			if gv.Group == "" {
+				continue
+			}
+			version := metav1.GroupVersionForDiscovery{GroupVersion: groupVersion, Version: gv.Version}
+			list.Groups = append(list.Groups, metav1.APIGroup{
+				Name:             gv.Group,
+				Versions:         []metav1.GroupVersionForDiscovery{version},
+				PreferredVersion: version,
+			})
+		}
+		writeJSON(w, http.StatusOK, list)
+		return true
+	}
+
+	for _, groupVersion := range s.groupVersions {
+		prefix := "/apis/"
+		if groupVersion == "v1" {
+			prefix = "/api/"
+		}
+		if r.URL.Path == prefix+groupVersion {
+			writeJSON(w, http.StatusOK, &metav1.APIResourceList{
+				TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+				GroupVersion: groupVersion,
+				APIResources: s.resources[groupVersion],
+			})
+			return true
+		}
+	}
+	return false
+}
+
+// serveList 返回匹配标签选择器和字段选择器的对象，请求表格时返回 Table
+func (s *FakeAPIServer) serveList(w http.ResponseWriter, r *http.Request, path requestPath, resource metav1.APIResource) {
+	matches, err := newMatcher(r, path, resource)
+	if err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	s.mu.Lock()
+	var items []*unstructured.Unstructured
+	for _, obj := range s.objects {
+		if matches(obj) {
+			items = append(items, obj.DeepCopy())
+		}
+	}
+	resourceVersion := strconv.Itoa(s.resourceVersion)
+	s.mu.Unlock()
+
+	if wantsTable(r) {
+		writeJSON(w, http.StatusOK, newTable(items, resourceVersion))
+		return
+	}
+
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{
+		"apiVersion": path.groupVersion,
+		"kind":       resource.Kind + "List",
+		"metadata":   map[string]interface{}{"resourceVersion": resourceVersion},
+	}}
+	for _, item := range items {
+		list.Items = append(list.Items, *item)
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// serveWatch 以 watch 事件流返回 resourceVersion 之后的变更，直到客户端断开或
+// CloseWatches 被调用。resourceVersion 早于 Compact 时返回 410 Gone 错误事件。
+func (s *FakeAPIServer) serveWatch(w http.ResponseWriter, r *http.Request, path requestPath, resource metav1.APIResource, closed <-chan struct{}) {
+	matches, err := newMatcher(r, path, resource)
+	if err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	resourceVersion, _ := strconv.Atoi(r.URL.Query().Get("resourceVersion"))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+
+	for {
+		s.mu.Lock()
+		if resourceVersion < s.compacted {
+			s.mu.Unlock()
+			status := apierrors.NewResourceExpired(fmt.Sprintf("too old resource version: %d (%d)", resourceVersion, s.compacted)).Status()
+			status.Kind = "Status"
+			status.APIVersion = "v1"
+			encoder.Encode(map[string]interface{}{"type": "ERROR", "object": &status})
+			return
+		}
+		var pending []watchEvent
+		for _, event := range s.events {
+			if event.resourceVersion > resourceVersion && matches(event.obj) {
+				pending = append(pending, event)
+			}
+		}
+		if len(s.events) > 0 {
+			resourceVersion = s.events[len(s.events)-1].resourceVersion
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		for _, event := range pending {
+			var obj interface{} = event.obj
+			if wantsTable(r) {
+				obj = newTable([]*unstructured.Unstructured{event.obj}, event.obj.GetResourceVersion())
+			}
+			encoder.Encode(map[string]interface{}{"type": event.eventType, "object": obj})
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		select {
+		case <-changed:
+		case <-closed:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// serveGet 返回单个对象
+func (s *FakeAPIServer) serveGet(w http.ResponseWriter, r *http.Request, path requestPath, resource metav1.APIResource) {
+	obj := s.find(path, resource)
+	if obj == nil {
+		writeStatus(w, notFound(path))
+		return
+	}
+	if wantsTable(r) {
+		writeJSON(w, http.StatusOK, newTable([]*unstructured.Unstructured{obj}, obj.GetResourceVersion()))
+		return
+	}
+	writeJSON(w, http.StatusOK, obj)
+}
+
+// serveCreate 创建对象
+func (s *FakeAPIServer) serveCreate(w http.ResponseWriter, r *http.Request, path requestPath, resource metav1.APIResource) {
+	obj, err := decodeBody(r)
+	if err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	if path.namespace != "" {
+		obj.SetNamespace(path.namespace)
+	}
+	path.name = obj.GetName()
+	if s.find(path, resource) != nil {
+		writeStatus(w, apierrors.NewAlreadyExists(schema.GroupResource{Resource: path.resource}, path.name))
+		return
+	}
+	if !isDryRun(r) {
+		s.mu.Lock()
+		obj = s.storeLocked(obj)
+		s.mu.Unlock()
+	}
+	writeJSON(w, http.StatusCreated, obj)
+}
+
+// serveUpdate 替换或修补对象，apply 修补在对象不存在时创建对象
+func (s *FakeAPIServer) serveUpdate(w http.ResponseWriter, r *http.Request, path requestPath, resource metav1.APIResource) {
+	patch, err := decodeBody(r)
+	if err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	existing := s.find(path, resource)
+	apply := r.Header.Get("Content-Type") == "application/apply-patch+yaml"
+	s.mu.Lock()
+	noApply := s.noApply
+	s.mu.Unlock()
+	if apply && noApply {
+		writeStatus(w, apierrors.NewGenericServerResponse(http.StatusUnsupportedMediaType, "patch", schema.GroupResource{Resource: path.resource}, path.name, "the body of the request was in an unknown format", 0, false))
+		return
+	}
+	if existing == nil && !apply {
+		writeStatus(w, notFound(path))
+		return
+	}
+
+	obj := patch
+	if existing != nil && r.Method == http.MethodPatch {
+		obj = &unstructured.Unstructured{Object: mergeObjects(existing.Object, patch.Object)}
+	}
+	if obj.GetAPIVersion() == "" {
+		obj.SetAPIVersion(path.groupVersion)
+		obj.SetKind(resource.Kind)
+	}
+	obj.SetNamespace(path.namespace)
+	obj.SetName(path.name)
+	if apply {
+		obj.SetManagedFields([]metav1.ManagedFieldsEntry{{
+			Manager:    r.URL.Query().Get("fieldManager"),
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: path.groupVersion,
+		}})
+	}
+
+	status := http.StatusOK
+	if existing == nil {
+		status = http.StatusCreated
+	}
+	if isDryRun(r) {
+		writeJSON(w, status, obj)
+		return
+	}
+
+	s.mu.Lock()
+	obj = s.storeLocked(obj)
+	s.mu.Unlock()
+	writeJSON(w, status, obj)
+}
+
+// serveDelete 删除单个对象并记录删除选项，带有 finalizer 的对象只设置 deletionTimestamp，
+// 直到测试调用 DeleteObject 才真正删除
+func (s *FakeAPIServer) serveDelete(w http.ResponseWriter, r *http.Request, path requestPath, resource metav1.APIResource) {
+	var options metav1.DeleteOptions
+	if data, err := io.ReadAll(r.Body); err == nil && len(data) > 0 {
+		if err := json.Unmarshal(data, &options); err != nil {
+			writeStatus(w, apierrors.NewBadRequest(err.Error()))
+			return
+		}
+	}
+
+	obj := s.find(path, resource)
+	if obj == nil {
+		writeStatus(w, notFound(path))
+		return
+	}
+	if isDryRun(r) {
+		writeJSON(w, http.StatusOK, obj)
+		return
+	}
+
+	s.mu.Lock()
+	s.deletions = append(s.deletions, options)
+	if len(obj.GetFinalizers()) > 0 {
+		now := metav1.Now()
+		obj.SetDeletionTimestamp(&now)
+		obj = s.storeLocked(obj)
+	} else {
+		s.deleteLocked(obj)
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, obj)
+}
+
+// serveDeleteCollection 删除匹配标签选择器的所有对象
+func (s *FakeAPIServer) serveDeleteCollection(w http.ResponseWriter, r *http.Request, path requestPath, resource metav1.APIResource) {
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	s.mu.Lock()
+	var deleted []*unstructured.Unstructured
+	for _, obj := range s.objects {
+		if matchesResource(obj, path, resource) && (path.namespace == "" || obj.GetNamespace() == path.namespace) &&
+			selector.Matches(labels.Set(obj.GetLabels())) {
+			deleted = append(deleted, obj)
+		}
+	}
+	for _, obj := range deleted {
+		s.deleteLocked(obj)
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, &metav1.Status{TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}, Status: metav1.StatusSuccess})
+}
+
+// lookupResource 查找已注册的资源类型
+func (s *FakeAPIServer) lookupResource(groupVersion, name string) (metav1.APIResource, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, resource := range s.resources[groupVersion] {
+		if resource.Name == name {
+			return resource, true
+		}
+	}
+	return metav1.APIResource{}, false
+}
+
+// find 返回路径指定的对象副本，不存在时返回 nil
+func (s *FakeAPIServer) find(path requestPath, resource metav1.APIResource) *unstructured.Unstructured {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, obj := range s.objects {
+		if matchesResource(obj, path, resource) && obj.GetNamespace() == path.namespace && obj.GetName() == path.name {
+			return obj.DeepCopy()
+		}
+	}
+	return nil
+}
+
+// parseRequestPath 解析 /api/v1/... 或 /apis/<group>/<version>/... 形式的资源路径
+func parseRequestPath(urlPath string) (requestPath, bool) {
+	var path requestPath
+	segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+
+	switch {
+	case len(segments) >= 3 && segments[0] == "api":
+		path.groupVersion = segments[1]
+		segments = segments[2:]
+	case len(segments) >= 4 && segments[0] == "apis":
+		path.groupVersion = segments[1] + "/" + segments[2]
+		segments = segments[3:]
+	default:
+		return path, false
+	}
+
+	if len(segments) >= 3 && segments[0] == "namespaces" {
+		path.namespace = segments[1]
+		segments = segments[2:]
+	}
+	if len(segments) > 3 {
+		return path, false
+	}
+
+	path.resource = segments[0]
+	if len(segments) > 1 {
+		path.name = segments[1]
+	}
+	if len(segments) > 2 {
+		path.subresource = segments[2]
+	}
+	return path, true
+}
+
+// newMatcher 返回判断对象是否匹配请求的资源类型、命名空间和选择器的函数，字段选择器按对象中的同名字段路径取值
+func newMatcher(r *http.Request, path requestPath, resource metav1.APIResource) (func(*unstructured.Unstructured) bool, error) {
+	labelSelector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		return nil, err
+	}
+	fieldSelector, err := fields.ParseSelector(r.URL.Query().Get("fieldSelector"))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(obj *unstructured.Unstructured) bool {
+		if !matchesResource(obj, path, resource) || (path.namespace != "" && obj.GetNamespace() != path.namespace) {
+			return false
+		}
+		objectFields := fields.Set{}
+		for _, requirement := range fieldSelector.Requirements() {
+			value, _, _ := unstructured.NestedFieldNoCopy(obj.Object, strings.Split(requirement.Field, ".")...)
+			if value != nil {
+				objectFields[requirement.Field] = fmt.Sprint(value)
+			}
+		}
+		return labelSelector.Matches(labels.Set(obj.GetLabels())) && fieldSelector.Matches(objectFields)
+	}, nil
+}
+
+// sameObject 判断两个对象的类型、命名空间和名称是否相同
+func sameObject(a, b *unstructured.Unstructured) bool {
+	return a.GetAPIVersion() == b.GetAPIVersion() && a.GetKind() == b.GetKind() &&
+		a.GetNamespace() == b.GetNamespace() && a.GetName() == b.GetName()
+}
+
+// matchesResource 判断对象是否属于路径的资源类型
+func matchesResource(obj *unstructured.Unstructured, path requestPath, resource metav1.APIResource) bool {
+	return obj.GetAPIVersion() == path.groupVersion && obj.GetKind() == resource.Kind
+}
+
+// wantsTable 判断请求是否要求服务端表格
+func wantsTable(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "as=Table")
+}
+
+// isDryRun 判断请求是否为服务端试运行
+func isDryRun(r *http.Request) bool {
+	return r.URL.Query().Get("dryRun") != ""
+}
+
+// newTable 创建包含 Name 列和 wide 输出的 Labels 列的服务端表格
+func newTable(items []*unstructured.Unstructured, resourceVersion string) *metav1.Table {
+	table := &metav1.Table{
+		TypeMeta: metav1.TypeMeta{Kind: "Table", APIVersion: "meta.k8s.io/v1"},
+		ListMeta: metav1.ListMeta{ResourceVersion: resourceVersion},
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Labels", Type: "string", Priority: 1},
+		},
+	}
+	for _, item := range items {
+		var selected []string
+		for key, value := range item.GetLabels() {
+			selected = append(selected, key+"="+value)
+		}
+		var labelCell interface{}
+		if len(selected) > 0 {
+			sort.Strings(selected)
+			labelCell = strings.Join(selected, ",")
+		}
+
+		metadata, _ := json.Marshal(map[string]interface{}{
+			"kind":       "PartialObjectMetadata",
+			"apiVersion": "meta.k8s.io/v1",
+			"metadata":   item.Object["metadata"],
+		})
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells:  []interface{}{item.GetName(), labelCell},
+			Object: runtime.RawExtension{Raw: metadata},
+		})
+	}
+	return table
+}
+
+// mergeObjects 按 JSON 合并修补的语义合并对象，策略合并修补也按此处理
+func mergeObjects(original, patch map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for key, value := range original {
+		merged[key] = value
+	}
+	for key, value := range patch {
+		// 忽略策略合并修补的指令，如 $setElementOrder
+		if strings.HasPrefix(key, "$") {
+			continue
+		}
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		patchMap, patchIsMap := value.(map[string]interface{})
+		originalMap, originalIsMap := merged[key].(map[string]interface{})
+		if patchIsMap && originalIsMap {
+			merged[key] = mergeObjects(originalMap, patchMap)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// decodeBody 解码 JSON 或 YAML 格式的请求体
+func decodeBody(r *http.Request) (*unstructured.Unstructured, error) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(data, &obj.Object); err != nil {
+		return nil, err
+	}
+	if obj.Object == nil {
+		obj.Object = map[string]interface{}{}
+	}
+	return obj, nil
+}
+
+// notFound 返回路径指定对象的 NotFound 错误
+func notFound(path requestPath) *apierrors.StatusError {
+	gv, _ := schema.ParseGroupVersion(path.groupVersion)
+	return apierrors.NewNotFound(schema.GroupResource{Group: gv.Group, Resource: path.resource}, path.name)
+}
+
+// writeStatus 以 Status 对象返回 API 错误
+func writeStatus(w http.ResponseWriter, err *apierrors.StatusError) {
+	status := err.Status()
+	status.Kind = "Status"
+	status.APIVersion = "v1"
+	writeJSON(w, int(status.Code), &status)
+}
+
+// writeJSON 以 JSON 格式返回对象
+func writeJSON(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(obj)
+}
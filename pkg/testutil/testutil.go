@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"sync"
 )
 
 // CaptureOutput 捕获标准输出和标准错误
@@ -12,6 +13,7 @@ type CaptureOutput struct {
 	stderr *os.File
 	outBuf *bytes.Buffer
 	errBuf *bytes.Buffer
+	wg     sync.WaitGroup
 }
 
 // NewCaptureOutput 创建新的输出捕获器
@@ -43,10 +45,13 @@ func (c *CaptureOutput) Start() error {
 	os.Stderr = wErr
 
 	// 启动 goroutine 来读取输出
+	c.wg.Add(2)
 	go func() {
+		defer c.wg.Done()
 		io.Copy(c.outBuf, rOut)
 	}()
 	go func() {
+		defer c.wg.Done()
 		io.Copy(c.errBuf, rErr)
 	}()
 
@@ -66,19 +71,24 @@ func (c *CaptureOutput) Stop() {
 		os.Stderr = c.stderr
 		c.stderr = nil
 	}
+	// 等待所有输出被读取完毕
+	c.wg.Wait()
 }
 
-// Stdout 返回捕获的标准输出
+// Stdout 停止捕获并返回捕获的标准输出
 func (c *CaptureOutput) Stdout() string {
+	c.Stop()
 	return c.outBuf.String()
 }
 
-// Stderr 返回捕获的标准错误
+// Stderr 停止捕获并返回捕获的标准错误
 func (c *CaptureOutput) Stderr() string {
+	c.Stop()
 	return c.errBuf.String()
 }
 
-// Combined 返回组合的输出（标准输出和标准错误）
+// Combined 停止捕获并返回组合的输出（标准输出和标准错误）
 func (c *CaptureOutput) Combined() string {
+	c.Stop()
 	return c.outBuf.String() + c.errBuf.String()
 } 
\ No newline at end of file
@@ -3,6 +3,7 @@ package util
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"syscall"
@@ -24,7 +25,8 @@ type SecureInputReader interface {
 
 // defaultInputReader implements standard input
 type defaultInputReader struct {
-	reader *bufio.Reader
+	reader io.Reader
+	writer io.Writer
 }
 
 // terminalInputReader implements terminal input
@@ -37,6 +39,7 @@ type terminalInputReader struct {
 func NewInputReader() InputReader {
 	return &defaultInputReader{
 		reader: bufio.NewReader(os.Stdin),
+		writer: os.Stdout,
 	}
 }
 
@@ -45,6 +48,7 @@ func NewSecureInputReader() SecureInputReader {
 	return &terminalInputReader{
 		defaultInputReader: defaultInputReader{
 			reader: bufio.NewReader(os.Stdin),
+			writer: os.Stdout,
 		},
 		fd: int(syscall.Stdin),
 	}
@@ -52,7 +56,7 @@ func NewSecureInputReader() SecureInputReader {
 
 // ReadLine implements standard input reading
 func (r *defaultInputReader) ReadLine(prompt string) (string, error) {
-	if _, err := fmt.Fprint(os.Stdout, prompt); err != nil {
+	if _, err := fmt.Fprint(r.writer, prompt); err != nil {
 		return "", fmt.Errorf("failed to write prompt: %w", err)
 	}
 
@@ -72,7 +76,7 @@ func (r *terminalInputReader) ReadLine(prompt string) (string, error) {
 
 // ReadSecurely implements secure input reading
 func (r *terminalInputReader) ReadSecurely(prompt string) (string, error) {
-	if _, err := fmt.Fprint(os.Stdout, prompt); err != nil {
+	if _, err := fmt.Fprint(r.writer, prompt); err != nil {
 		return "", fmt.Errorf("failed to write prompt: %w", err)
 	}
 
@@ -100,19 +104,19 @@ func (r *terminalInputReader) ReadSecurely(prompt string) (string, error) {
 
 		switch c {
 		case '\r', '\n':
-			_, _ = fmt.Fprintln(os.Stdout)
+			_, _ = fmt.Fprintln(r.writer)
 			return string(password), nil
 		case 3: // Ctrl+C
 			return "", fmt.Errorf("interrupted by user")
 		case '\b', 127: // Backspace and Delete
 			if len(password) > 0 {
 				password = password[:len(password)-1]
-				_, _ = fmt.Fprint(os.Stdout, "\b \b")
+				_, _ = fmt.Fprint(r.writer, "\b \b")
 			}
 		default:
 			if c >= 32 && c <= 126 { // Printable characters
 				password = append(password, c)
-				_, _ = fmt.Fprint(os.Stdout, "*")
+				_, _ = fmt.Fprint(r.writer, "*")
 			}
 		}
 	}
@@ -127,4 +131,19 @@ func ReadPassword(prompt string) (string, error) {
 func ReadInput(prompt string) (string, error) {
 	reader := NewInputReader()
 	return reader.ReadLine(prompt)
-} 
\ No newline at end of file
+}
+
+// Confirm asks a yes/no question and reports whether the answer was yes
+func Confirm(reader InputReader, prompt string) (bool, error) {
+	answer, err := reader.ReadLine(prompt)
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -202,4 +202,30 @@ func TestReadPassword(t *testing.T) {
 		}
 		require.NotEmpty(t, result, "password input should not be empty")
 	})
-} 
\ No newline at end of file
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{name: "should accept y", input: "y\n", expected: true},
+		{name: "should accept yes in any case", input: "YES\n", expected: true},
+		{name: "should reject n", input: "n\n", expected: false},
+		{name: "should reject empty answer", input: "\n", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputReader := &defaultInputReader{
+				reader: bytes.NewBufferString(tt.input),
+				writer: io.Discard,
+			}
+
+			result, err := Confirm(inputReader, "Continue? (y/n): ")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package util
+
+import (
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/ssh/terminal"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// fdHolder is implemented by streams backed by a file descriptor, like os.File
+type fdHolder interface {
+	Fd() uintptr
+}
+
+// TTY holds the standard streams of an interactive command, such as exec or
+// attach. Raw mode and size tracking only apply to streams that are terminals,
+// so the same code path serves pipes and redirected files.
+type TTY struct {
+	// In is the input stream
+	In io.Reader
+	// Out is the output stream, whose size is sent to the remote terminal
+	Out io.Writer
+	// Raw puts the input terminal in raw mode while Safe runs
+	Raw bool
+}
+
+// NewTTY creates a TTY for the streams
+func NewTTY(in io.Reader, out io.Writer, raw bool) *TTY {
+	return &TTY{In: in, Out: out, Raw: raw}
+}
+
+// IsTerminalIn reports whether the input stream is a terminal
+func (t *TTY) IsTerminalIn() bool {
+	return isTerminal(t.In)
+}
+
+// IsTerminalOut reports whether the output stream is a terminal
+func (t *TTY) IsTerminalOut() bool {
+	return isTerminal(t.Out)
+}
+
+// Safe runs the function with the input terminal in raw mode when Raw is set,
+// restoring the previous terminal state afterwards
+func (t *TTY) Safe(fn func() error) error {
+	fd, ok := terminalFd(t.In)
+	if !t.Raw || !ok {
+		return fn()
+	}
+
+	oldState, err := terminal.MakeRaw(fd)
+	if err != nil {
+		return fn()
+	}
+	defer func() {
+		_ = terminal.Restore(fd, oldState)
+	}()
+	return fn()
+}
+
+// GetSize returns the size of the output terminal, nil when the output is not
+// a terminal
+func (t *TTY) GetSize() *remotecommand.TerminalSize {
+	fd, ok := terminalFd(t.Out)
+	if !ok {
+		return nil
+	}
+	width, height, err := terminal.GetSize(fd)
+	if err != nil || width <= 0 || height <= 0 {
+		return nil
+	}
+	return &remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}
+}
+
+// MonitorSize returns a queue of the sizes of the output terminal, starting
+// with the current size and followed by every change until Stop is called.
+// The queue is nil when the output is not a terminal.
+func (t *TTY) MonitorSize() *SizeQueue {
+	initial := t.GetSize()
+	if initial == nil {
+		return nil
+	}
+
+	q := &SizeQueue{sizes: make(chan remotecommand.TerminalSize, 1), stop: make(chan struct{})}
+	q.sizes <- *initial
+	resized, stopResize := resizeEvents()
+	go func() {
+		defer stopResize()
+		last := *initial
+		for {
+			select {
+			case <-resized:
+			case <-q.stop:
+				return
+			}
+			size := t.GetSize()
+			if size == nil || *size == last {
+				continue
+			}
+			last = *size
+			// Drop a pending size nobody read yet, only the latest one matters
+			select {
+			case <-q.sizes:
+			default:
+			}
+			q.sizes <- last
+		}
+	}()
+	return q
+}
+
+// SizeQueue passes the terminal sizes to a remote command, implementing
+// remotecommand.TerminalSizeQueue
+type SizeQueue struct {
+	sizes    chan remotecommand.TerminalSize
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// Next returns the next terminal size, nil once the queue is stopped
+func (q *SizeQueue) Next() *remotecommand.TerminalSize {
+	select {
+	case size := <-q.sizes:
+		return &size
+	case <-q.stop:
+		return nil
+	}
+}
+
+// Stop ends the queue and the monitoring of the terminal
+func (q *SizeQueue) Stop() {
+	q.stopOnce.Do(func() { close(q.stop) })
+}
+
+// isTerminal reports whether the stream is a terminal
+func isTerminal(stream interface{}) bool {
+	_, ok := terminalFd(stream)
+	return ok
+}
+
+// terminalFd returns the file descriptor of a stream that is a terminal
+func terminalFd(stream interface{}) (int, bool) {
+	file, ok := stream.(fdHolder)
+	if !ok {
+		return 0, false
+	}
+	fd := int(file.Fd())
+	return fd, terminal.IsTerminal(fd)
+}
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+func TestTTYWithoutTerminal(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "output"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	tests := []struct {
+		name string
+		in   io.Reader
+		out  io.Writer
+	}{
+		{name: "buffers", in: &bytes.Buffer{}, out: &bytes.Buffer{}},
+		{name: "regular file", in: file, out: file},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tty := NewTTY(tt.in, tt.out, true)
+			assert.False(t, tty.IsTerminalIn())
+			assert.False(t, tty.IsTerminalOut())
+			assert.Nil(t, tty.GetSize())
+			assert.Nil(t, tty.MonitorSize())
+
+			called := false
+			err := tty.Safe(func() error {
+				called = true
+				return ErrMockRead
+			})
+			assert.True(t, called, "Safe should run the function")
+			assert.ErrorIs(t, err, ErrMockRead)
+		})
+	}
+}
+
+func TestSizeQueue(t *testing.T) {
+	q := &SizeQueue{sizes: make(chan remotecommand.TerminalSize, 1), stop: make(chan struct{})}
+	q.sizes <- remotecommand.TerminalSize{Width: 80, Height: 24}
+	assert.Equal(t, &remotecommand.TerminalSize{Width: 80, Height: 24}, q.Next())
+
+	// Stopping the queue releases a pending Next
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Stop()
+	}()
+	assert.Nil(t, q.Next())
+	q.Stop()
+}
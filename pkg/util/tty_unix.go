@@ -0,0 +1,17 @@
+//go:build !windows
+
+package util
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// resizeEvents returns a channel receiving a value on every terminal resize,
+// and a function ending the notifications
+func resizeEvents() (<-chan os.Signal, func()) {
+	events := make(chan os.Signal, 1)
+	signal.Notify(events, syscall.SIGWINCH)
+	return events, func() { signal.Stop(events) }
+}
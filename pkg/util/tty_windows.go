@@ -0,0 +1,18 @@
+//go:build windows
+
+package util
+
+import (
+	"time"
+)
+
+// resizePollInterval is the interval between size checks, as Windows has no
+// resize signal
+const resizePollInterval = 250 * time.Millisecond
+
+// resizeEvents returns a channel receiving a value at every poll interval, and
+// a function ending the notifications
+func resizeEvents() (<-chan time.Time, func()) {
+	ticker := time.NewTicker(resizePollInterval)
+	return ticker.C, ticker.Stop
+}